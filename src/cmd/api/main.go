@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 	"github.com/LacirJR/psygrow-api/src/internal/config"
+	"github.com/LacirJR/psygrow-api/src/internal/core/security"
 	"github.com/LacirJR/psygrow-api/src/internal/infra/migration"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
 	"github.com/LacirJR/psygrow-api/src/internal/router"
 	"github.com/LacirJR/psygrow-api/src/internal/seed"
 	"github.com/gin-gonic/gin"
@@ -24,6 +26,27 @@ func main() {
 	//Criar usuario padrao
 	seed.CreateDefaultAdminUser()
 
+	//Criar modelos de anamnese iniciais
+	seed.SeedStarterAnamneseTemplates()
+
+	//Criar escalas psicométricas
+	seed.SeedScales()
+
+	//Iniciar serviço de tokens com rotação de chaves
+	tokenSettings := config.LoadTokenSettings()
+	tokenService, err := security.NewTokenService(repository.NewSigningKeyRepository(config.DB), security.TokenServiceConfig{
+		Algorithm:        tokenSettings.Algorithm,
+		Issuer:           tokenSettings.Issuer,
+		RotationInterval: tokenSettings.RotationInterval,
+		LegacySecret:     tokenSettings.LegacySecret,
+		LegacyUntil:      tokenSettings.LegacyUntil,
+	})
+	if err != nil {
+		log.Fatalf("Erro ao iniciar serviço de tokens: %v", err)
+	}
+	security.SetTokenService(tokenService)
+	tokenService.StartRotation(tokenSettings.RotationCheck)
+
 	//Registrar rotas
 	app := gin.Default()
 	router.RegisterRoutes(app)
@@ -31,7 +54,7 @@ func main() {
 	var port = config.GetEnvironment(config.AppPort)
 	//Iniciar servidor
 	log.Printf("Iniciando servidor na porta %s...", port)
-	err := app.Run(fmt.Sprintf(":%s", port))
+	err = app.Run(fmt.Sprintf(":%s", port))
 	if err != nil {
 		log.Fatal("Erro ao iniciar servidor:", err)
 	}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/backfill"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/migration"
+	"log"
+	"os"
+)
+
+// Normaliza CPF, telefones e endereços já gravados e lista as linhas que não passam na validação,
+// cria o funil padrão de leads dos usuários que ainda não o têm, publica a primeira versão dos
+// modelos de anamnese anteriores ao versionamento e abre episódios de cuidado para
+// os pacientes ativos, vinculando a eles os agendamentos e sessões existentes.
+// Sem -apply apenas mostra o que seria alterado, sem aplicar as migrações.
+func main() {
+	apply := flag.Bool("apply", false, "grava as alterações (sem esta opção apenas simula)")
+	flag.Parse()
+
+	//Carregar arquivo .env
+	config.LoadEnv()
+
+	//Iniciar banco de dados
+	config.InitDatabase()
+
+	//Aplicar migrações, que também gravam dados; a simulação lê o esquema já migrado pela API
+	if *apply {
+		migration.Migrate()
+	}
+
+	reports, err := backfill.NormalizeContacts(config.DB, *apply)
+	if err != nil {
+		log.Fatalf("Erro ao normalizar contatos: %v", err)
+	}
+
+	failures := 0
+	for _, report := range reports {
+		fmt.Printf("%s: %d linhas lidas, %d normalizadas, %d inválidas\n", report.Table, report.Scanned, report.Updated, len(report.Failures))
+		for _, failure := range report.Failures {
+			fmt.Printf("  %s %s: %s\n", report.Table, failure.ID, failure.Errors.Error())
+		}
+		failures += len(report.Failures)
+	}
+
+	pipelines, err := backfill.SeedLeadPipelines(config.DB, *apply)
+	if err != nil {
+		log.Fatalf("Erro ao criar funis padrão de leads: %v", err)
+	}
+	fmt.Printf("lead_stages: %d usuários sem etapas receberam as etapas padrão\n", pipelines.Stages)
+	fmt.Printf("lead_lost_reasons: %d usuários sem motivos de perda receberam os motivos padrão\n", pipelines.LostReasons)
+
+	versions, err := backfill.VersionAnamneseTemplates(config.DB, *apply)
+	if err != nil {
+		log.Fatalf("Erro ao versionar modelos de anamnese: %v", err)
+	}
+	fmt.Printf("anamnese_template_versions: %d modelos com perguntas receberam a primeira versão publicada\n", versions.Templates)
+	fmt.Printf("anamnese_fields: %d perguntas vinculadas à primeira versão do modelo\n", versions.Fields)
+	fmt.Printf("patient_anamneses: %d anamneses vinculadas à primeira versão do modelo\n", versions.Anamneses)
+
+	episodes, err := backfill.OpenCareEpisodes(config.DB, *apply)
+	if err != nil {
+		log.Fatalf("Erro ao abrir episódios de cuidado: %v", err)
+	}
+	fmt.Printf("care_episodes: %d pacientes ativos sem episódio aberto receberam um episódio\n", episodes.Opened)
+	fmt.Printf("appointments: %d agendamentos vinculados ao episódio do paciente\n", episodes.Appointments)
+	fmt.Printf("sessions: %d sessões vinculadas ao episódio do paciente\n", episodes.Sessions)
+
+	if !*apply {
+		fmt.Println("Simulação: nenhuma alteração foi gravada. Use -apply para gravar.")
+	}
+	if failures > 0 {
+		os.Exit(1)
+	}
+}
@@ -10,4 +10,12 @@ const (
 	JwtSecretKey     = "JWT_SECRET"
 	SslMode          = "SSL_MODE"
 	CorsAllowOrigins = "CORS_ALLOW_ORIGINS"
+	TotpIssuer       = "TOTP_ISSUER"
+	TotpSecretKey    = "TOTP_SECRET_KEY"
+
+	JwtAlgorithm           = "JWT_ALGORITHM"
+	JwtIssuer              = "JWT_ISSUER"
+	JwtKeyRotationInterval = "JWT_KEY_ROTATION_INTERVAL"
+	JwtKeyRotationCheck    = "JWT_KEY_ROTATION_CHECK"
+	JwtLegacyUntil         = "JWT_LEGACY_UNTIL"
 )
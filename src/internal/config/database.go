@@ -22,7 +22,7 @@ func InitDatabase() {
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
-		log.Fatal("Erro ao conectar com o banco de dados: %v", err)
+		log.Fatalf("Erro ao conectar com o banco de dados: %v", err)
 	}
 
 	DB = db
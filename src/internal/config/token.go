@@ -0,0 +1,55 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+// TokenSettings holds the environment configuration of the JWT token service
+type TokenSettings struct {
+	Algorithm        string
+	Issuer           string
+	RotationInterval time.Duration
+	RotationCheck    time.Duration
+	LegacySecret     []byte
+	LegacyUntil      time.Time
+}
+
+// LoadTokenSettings reads the token service configuration, falling back to defaults for unset values
+func LoadTokenSettings() TokenSettings {
+	return TokenSettings{
+		Algorithm:        GetEnvironmentWithDefault(JwtAlgorithm, "RS256"),
+		Issuer:           GetEnvironmentWithDefault(JwtIssuer, "psygrow-api"),
+		RotationInterval: durationFromEnvironment(JwtKeyRotationInterval, time.Hour*24*30),
+		RotationCheck:    durationFromEnvironment(JwtKeyRotationCheck, time.Hour),
+		LegacySecret:     []byte(GetEnvironmentWithDefault(JwtSecretKey, "")),
+		LegacyUntil:      timeFromEnvironment(JwtLegacyUntil),
+	}
+}
+
+func durationFromEnvironment(key string, defaultValue time.Duration) time.Duration {
+	raw := GetEnvironmentWithDefault(key, "")
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		log.Printf("Aviso: valor inválido para '%s', usando %s", key, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+// timeFromEnvironment reads an RFC 3339 instant, returning the zero time when unset or invalid
+func timeFromEnvironment(key string) time.Time {
+	raw := GetEnvironmentWithDefault(key, "")
+	if raw == "" {
+		return time.Time{}
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		log.Printf("Aviso: valor inválido para '%s', ignorando", key)
+		return time.Time{}
+	}
+	return value
+}
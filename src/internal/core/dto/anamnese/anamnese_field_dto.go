@@ -13,6 +13,20 @@ type AnamneseFieldRequest struct {
 	FieldActive   bool                      `json:"field_active"`
 	AnamneseID    string                    `json:"anamnese_id" binding:"required,uuid"`
 	Options       []AnamneseFieldOptionItem `json:"options,omitempty"`
+	// Visibility shows the field only for some answers of another field; nil always shows it
+	Visibility *AnamneseFieldVisibilityRequest `json:"visibility,omitempty"`
+}
+
+// AnamneseFieldVisibilityRequest represents the visibility rule of an anamnese field
+type AnamneseFieldVisibilityRequest struct {
+	DependsOnFieldID string `json:"depends_on_field_id" binding:"required,uuid"`
+	Operator         string `json:"operator" binding:"required,oneof=equals not_equals contains answered not_answered"`
+	Value            string `json:"value"`
+}
+
+// AnamneseFieldOrderRequest represents a request to reorder every field of a template
+type AnamneseFieldOrderRequest struct {
+	FieldIDs []string `json:"field_ids" binding:"required,min=1,dive,uuid"`
 }
 
 // AnamneseFieldResponse represents the response for an anamnese field
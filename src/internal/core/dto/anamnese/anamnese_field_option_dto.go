@@ -45,3 +45,21 @@ type AnamneseFieldOptionItem struct {
 	OptionValue string `json:"option_value" binding:"required"`
 	OptionOrder int    `json:"option_order" binding:"required"`
 }
+
+// AnamneseFieldOptionReplaceRequest represents a request to replace all the options of a field.
+// Items with the ID of a current option update it; options left out are deleted.
+type AnamneseFieldOptionReplaceRequest struct {
+	Options []AnamneseFieldOptionReplaceItem `json:"options" binding:"required,dive"`
+}
+
+// AnamneseFieldOptionReplaceItem represents a single option item in a replace request
+type AnamneseFieldOptionReplaceItem struct {
+	ID          string `json:"id" binding:"omitempty,uuid"`
+	OptionValue string `json:"option_value" binding:"required"`
+	OptionOrder int    `json:"option_order" binding:"required"`
+}
+
+// AnamneseFieldOptionOrderRequest represents a request to reorder every option of a field
+type AnamneseFieldOptionOrderRequest struct {
+	OptionIDs []string `json:"option_ids" binding:"required,min=1,dive,uuid"`
+}
@@ -0,0 +1,175 @@
+package dto
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// AnamneseTemplateSchemaVersion is the version of the export format written by this API.
+// Imports of any other version are rejected.
+const AnamneseTemplateSchemaVersion = 1
+
+var (
+	ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+	ErrDuplicatedFieldKey       = errors.New("field key used more than once")
+	ErrUnknownFieldKey          = errors.New("visibility rule depends on an unknown field key")
+)
+
+// AnamneseTemplateExport is a template with the fields and options of one of its versions, in
+// a format that can be imported into any account. Fields are identified by keys local to the
+// file, which visibility rules use to refer to each other.
+type AnamneseTemplateExport struct {
+	SchemaVersion int                   `json:"schema_version" binding:"required"`
+	Title         string                `json:"title" binding:"required,max=100"`
+	Fields        []AnamneseFieldExport `json:"fields" binding:"required,min=1,dive"`
+}
+
+// AnamneseFieldExport represents an exported anamnese field
+type AnamneseFieldExport struct {
+	Key           string                    `json:"key" binding:"required,max=50"`
+	FieldNumber   int                       `json:"field_number" binding:"required"`
+	FieldType     string                    `json:"field_type" binding:"required,oneof=date datetime text number checkbox select multiselect"`
+	FieldTitle    string                    `json:"field_title" binding:"required,max=255"`
+	FieldRequired bool                      `json:"field_required"`
+	FieldActive   *bool                     `json:"field_active,omitempty"` // Active when omitted
+	Options       []AnamneseOptionExport    `json:"options,omitempty" binding:"dive"`
+	Visibility    *AnamneseVisibilityExport `json:"visibility,omitempty"`
+}
+
+// AnamneseOptionExport represents an exported option of an anamnese field
+type AnamneseOptionExport struct {
+	OptionValue string `json:"option_value" binding:"required,max=255"`
+	OptionOrder int    `json:"option_order"`
+}
+
+// AnamneseVisibilityExport represents the visibility rule of an exported field
+type AnamneseVisibilityExport struct {
+	DependsOn string `json:"depends_on" binding:"required"` // Key of another field of the file
+	Operator  string `json:"operator" binding:"required,oneof=equals not_equals contains answered not_answered"`
+	Value     string `json:"value"`
+}
+
+// NewAnamneseTemplateExport exports a template with the given fields of one of its versions,
+// using the field keys as the keys of the file
+func NewAnamneseTemplateExport(title string, fields []*model.AnamneseField) AnamneseTemplateExport {
+	export := AnamneseTemplateExport{
+		SchemaVersion: AnamneseTemplateSchemaVersion,
+		Title:         title,
+		Fields:        make([]AnamneseFieldExport, len(fields)),
+	}
+
+	for i, field := range fields {
+		active := field.FieldActive
+		exported := AnamneseFieldExport{
+			Key:           field.FieldKey.String(),
+			FieldNumber:   field.FieldNumber,
+			FieldType:     field.FieldType,
+			FieldTitle:    field.FieldTitle,
+			FieldRequired: field.FieldRequired,
+			FieldActive:   &active,
+		}
+		for _, option := range field.Options {
+			exported.Options = append(exported.Options, AnamneseOptionExport{
+				OptionValue: option.OptionValue,
+				OptionOrder: option.OptionOrder,
+			})
+		}
+		if field.VisibleIfFieldKey != nil && field.VisibleIfOperator != nil {
+			exported.Visibility = &AnamneseVisibilityExport{
+				DependsOn: field.VisibleIfFieldKey.String(),
+				Operator:  *field.VisibleIfOperator,
+			}
+			if field.VisibleIfValue != nil {
+				exported.Visibility.Value = *field.VisibleIfValue
+			}
+		}
+		export.Fields[i] = exported
+	}
+
+	return export
+}
+
+// ToModels builds a new template owned by the given user, with a first published version
+// holding the exported fields and options. Every field and option gets new IDs and keys.
+func (e AnamneseTemplateExport) ToModels(userID uuid.UUID) (*model.AnamneseTemplate, *model.AnamneseTemplateVersion, []*model.AnamneseField, error) {
+	if e.SchemaVersion != AnamneseTemplateSchemaVersion {
+		return nil, nil, nil, ErrUnsupportedSchemaVersion
+	}
+
+	keys := make(map[string]uuid.UUID, len(e.Fields))
+	for _, field := range e.Fields {
+		if _, duplicated := keys[field.Key]; duplicated {
+			return nil, nil, nil, ErrDuplicatedFieldKey
+		}
+		keys[field.Key] = uuid.New()
+	}
+
+	now := time.Now()
+	template := &model.AnamneseTemplate{
+		ID:        uuid.New(),
+		Title:     e.Title,
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	version := &model.AnamneseTemplateVersion{
+		ID:          uuid.New(),
+		TemplateID:  template.ID,
+		Version:     1,
+		Status:      model.AnamneseVersionStatusPublished,
+		PublishedAt: &now,
+	}
+
+	fields := make([]*model.AnamneseField, len(e.Fields))
+	for i, exported := range e.Fields {
+		fieldID := keys[exported.Key]
+		field := &model.AnamneseField{
+			ID:            fieldID,
+			FieldNumber:   exported.FieldNumber,
+			FieldType:     exported.FieldType,
+			FieldTitle:    exported.FieldTitle,
+			FieldRequired: exported.FieldRequired,
+			FieldActive:   exported.FieldActive == nil || *exported.FieldActive,
+			UserID:        userID,
+			AnamneseID:    template.ID,
+			VersionID:     version.ID,
+			FieldKey:      fieldID,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		for _, option := range exported.Options {
+			optionID := uuid.New()
+			field.Options = append(field.Options, model.AnamneseFieldOption{
+				ID:              optionID,
+				AnamneseFieldID: fieldID,
+				OptionValue:     option.OptionValue,
+				OptionOrder:     option.OptionOrder,
+				OptionKey:       optionID,
+				CreatedAt:       now,
+				UpdatedAt:       now,
+			})
+		}
+		if rule := exported.Visibility; rule != nil {
+			dependsOn, ok := keys[rule.DependsOn]
+			if !ok {
+				return nil, nil, nil, ErrUnknownFieldKey
+			}
+			operator, value := rule.Operator, rule.Value
+			field.VisibleIfFieldKey = &dependsOn
+			field.VisibleIfOperator = &operator
+			field.VisibleIfValue = &value
+		}
+		fields[i] = field
+	}
+
+	return template, version, fields, nil
+}
+
+// AnamneseTemplateCloneRequest represents the request to clone a template into another account.
+// Without a target the template is cloned into the account of the authenticated user.
+type AnamneseTemplateCloneRequest struct {
+	UserID         *string `json:"user_id" binding:"omitempty,uuid,excluded_with=OrganizationID"`
+	OrganizationID *string `json:"organization_id" binding:"omitempty,uuid"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"time"
+)
+
+// AnamneseTemplateVersionResponse represents the response for a version of an anamnese template
+type AnamneseTemplateVersionResponse struct {
+	ID          string                   `json:"id"`
+	TemplateID  string                   `json:"template_id"`
+	Version     int                      `json:"version"`
+	Status      string                   `json:"status"`
+	PublishedAt *time.Time               `json:"published_at"`
+	CreatedAt   time.Time                `json:"created_at"`
+	Fields      *[]AnamneseFieldResponse `json:"fields,omitempty"`
+}
+
+// NewAnamneseTemplateVersionResponse creates a new AnamneseTemplateVersionResponse from a version,
+// including its fields when they are loaded
+func NewAnamneseTemplateVersionResponse(version model.AnamneseTemplateVersion) AnamneseTemplateVersionResponse {
+	response := AnamneseTemplateVersionResponse{
+		ID:          version.ID.String(),
+		TemplateID:  version.TemplateID.String(),
+		Version:     version.Version,
+		Status:      version.Status,
+		PublishedAt: version.PublishedAt,
+		CreatedAt:   version.CreatedAt,
+	}
+
+	if version.Fields != nil {
+		fields := make([]AnamneseFieldResponse, len(version.Fields))
+		for i, field := range version.Fields {
+			options := make([]AnamneseFieldOptionResponse, len(field.Options))
+			for j, option := range field.Options {
+				options[j] = NewAnamneseFieldOptionResponse(option.ID, option.OptionValue, option.OptionOrder, option.AnamneseFieldID)
+			}
+			fields[i] = NewAnamneseFieldResponse(field.ID, field.FieldNumber, field.FieldType, field.FieldTitle,
+				field.FieldRequired, field.FieldActive, field.UserID, field.AnamneseID, options)
+		}
+		response.Fields = &fields
+	}
+
+	return response
+}
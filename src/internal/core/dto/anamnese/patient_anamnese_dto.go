@@ -10,6 +10,13 @@ type PatientAnamneseRequest struct {
 	PatientID  string                        `json:"patient_id" binding:"required,uuid"`
 	AnamneseID string                        `json:"anamnese_id" binding:"required,uuid"`
 	Fields     []PatientAnamneseFieldRequest `json:"fields" binding:"required,dive"`
+	Status     string                        `json:"status" binding:"omitempty,oneof=draft finalized"` // Defaults to finalized
+}
+
+// PatientAnamneseAnswersRequest represents the request to change some answers of a draft
+// patient anamnese. An empty value clears the answer.
+type PatientAnamneseAnswersRequest struct {
+	Fields []PatientAnamneseFieldRequest `json:"fields" binding:"required,min=1,dive"`
 }
 
 // PatientAnamneseResponse represents the response for a patient anamnese
@@ -42,8 +49,9 @@ func NewPatientAnamneseResponse(
 
 // PatientAnamneseFieldRequest represents the request to create a new patient anamnese field
 type PatientAnamneseFieldRequest struct {
-	FieldID string `json:"field_id" binding:"required,uuid"`
-	Value   string `json:"value"`
+	FieldID string   `json:"field_id" binding:"required,uuid"`
+	Value   string   `json:"value"`
+	Values  []string `json:"values"` // Chosen options of a multiselect field
 }
 
 // PatientAnamneseFieldResponse represents the response for a patient anamnese field
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Statuses of a self-service anamnese link, derived from its dates
+const (
+	PatientAnamneseLinkStatusPending = "pending"
+	PatientAnamneseLinkStatusUsed    = "used"
+	PatientAnamneseLinkStatusExpired = "expired"
+	PatientAnamneseLinkStatusRevoked = "revoked"
+)
+
+// PatientAnamneseLinkRequest represents the request to send a self-service anamnese link to a patient
+type PatientAnamneseLinkRequest struct {
+	AnamneseID     string `json:"anamnese_id" binding:"required,uuid"`
+	ExpiresInHours int    `json:"expires_in_hours" binding:"omitempty,min=1,max=720"` // Defaults to 72 hours
+}
+
+// PatientAnamneseLinkResponse represents the response for a self-service anamnese link. The token
+// is only returned when the link is created.
+type PatientAnamneseLinkResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	PatientID         uuid.UUID  `json:"patient_id"`
+	AnamneseID        uuid.UUID  `json:"anamnese_id"`
+	TemplateVersionID uuid.UUID  `json:"template_version_id"`
+	Token             string     `json:"token,omitempty"`
+	Status            string     `json:"status"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	UsedAt            *time.Time `json:"used_at"`
+	PatientAnamneseID *uuid.UUID `json:"patient_anamnese_id"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// NewPatientAnamneseLinkResponse creates a new PatientAnamneseLinkResponse from a link, with its
+// status at the given time
+func NewPatientAnamneseLinkResponse(link model.PatientAnamneseLink, now time.Time) PatientAnamneseLinkResponse {
+	status := PatientAnamneseLinkStatusPending
+	switch {
+	case link.UsedAt != nil:
+		status = PatientAnamneseLinkStatusUsed
+	case link.RevokedAt != nil:
+		status = PatientAnamneseLinkStatusRevoked
+	case !now.Before(link.ExpiresAt):
+		status = PatientAnamneseLinkStatusExpired
+	}
+
+	return PatientAnamneseLinkResponse{
+		ID:                link.ID,
+		PatientID:         link.PatientID,
+		AnamneseID:        link.AnamneseID,
+		TemplateVersionID: link.TemplateVersionID,
+		Status:            status,
+		ExpiresAt:         link.ExpiresAt,
+		UsedAt:            link.UsedAt,
+		PatientAnamneseID: link.PatientAnamneseID,
+		RevokedAt:         link.RevokedAt,
+		CreatedAt:         link.CreatedAt,
+	}
+}
+
+// PatientAnamneseLinkAccessResponse represents a logged use of a self-service anamnese link
+type PatientAnamneseLinkAccessResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Action    string    `json:"action"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewPatientAnamneseLinkAccessResponse creates a new PatientAnamneseLinkAccessResponse from a logged access
+func NewPatientAnamneseLinkAccessResponse(access model.PatientAnamneseLinkAccess) PatientAnamneseLinkAccessResponse {
+	return PatientAnamneseLinkAccessResponse{
+		ID:        access.ID,
+		Action:    access.Action,
+		IPAddress: access.IPAddress,
+		UserAgent: access.UserAgent,
+		CreatedAt: access.CreatedAt,
+	}
+}
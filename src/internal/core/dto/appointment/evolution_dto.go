@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
 	"github.com/google/uuid"
 	"time"
 )
@@ -9,6 +10,41 @@ import (
 type EvolutionRequest struct {
 	SessionID string `json:"session_id" binding:"required,uuid"`
 	Content   string `json:"content" binding:"required"`
+	// Progress on the goals of the active treatment plan of the patient
+	GoalProgress []EvolutionGoalProgressRequest `json:"goal_progress" binding:"omitempty,dive"`
+}
+
+// EvolutionGoalProgressRequest represents the progress on a treatment goal rated in an evolution
+type EvolutionGoalProgressRequest struct {
+	GoalID uuid.UUID `json:"goal_id" binding:"required"`
+	Rating *int      `json:"rating" binding:"required,min=0,max=10"`
+	Notes  *string   `json:"notes"`
+}
+
+// EvolutionGoalProgressResponse represents the progress on a treatment goal rated in an evolution
+type EvolutionGoalProgressResponse struct {
+	PlanID  uuid.UUID `json:"plan_id"`
+	GoalID  uuid.UUID `json:"goal_id"`
+	GoalKey uuid.UUID `json:"goal_key"`
+	Rating  int       `json:"rating"`
+	Notes   *string   `json:"notes"`
+	RatedAt time.Time `json:"rated_at"`
+}
+
+// NewEvolutionGoalProgressResponses creates the responses for the progress rated in an evolution
+func NewEvolutionGoalProgressResponses(progress []model.TreatmentGoalProgress) []EvolutionGoalProgressResponse {
+	responses := []EvolutionGoalProgressResponse{}
+	for _, rating := range progress {
+		responses = append(responses, EvolutionGoalProgressResponse{
+			PlanID:  rating.PlanID,
+			GoalID:  rating.GoalID,
+			GoalKey: rating.GoalKey,
+			Rating:  rating.Rating,
+			Notes:   rating.Notes,
+			RatedAt: rating.RatedAt,
+		})
+	}
+	return responses
 }
 
 // EvolutionResponse represents the response for an evolution
@@ -8,6 +8,7 @@ import (
 // PaymentRequest represents the request to create a new payment
 type PaymentRequest struct {
 	PatientID      *string   `json:"patient_id,omitempty" binding:"omitempty,uuid"`
+	PayerID        *string   `json:"payer_id,omitempty" binding:"omitempty,uuid"` // Financial responsible family member; defaults to the patient's only one
 	CostCenterID   string    `json:"cost_center_id" binding:"required,uuid"`
 	PaymentDate    time.Time `json:"payment_date" binding:"required"`
 	Amount         int64     `json:"amount" binding:"required,gt=0"`
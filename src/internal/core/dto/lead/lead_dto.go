@@ -8,57 +8,111 @@ import (
 
 // LeadRequest represents the request body for creating or updating a lead
 type LeadRequest struct {
-	FullName         string     `json:"full_name" binding:"required,min=2,max=100"`
-	Phone            *string    `json:"phone"`
-	Email            *string    `json:"email"`
-	BirthDate        *time.Time `json:"birth_date"`
-	ContactDate      time.Time  `json:"contact_date" binding:"required"`
-	Status           string     `json:"status" binding:"required,oneof=new in_analysis converted lost"` // TODO: Use constants from model.LeadStatus*
-	WasAttended      bool       `json:"was_attended"`
-	Notes            *string    `json:"notes"`
-	Origin           *string    `json:"origin"`
-	GdprBlockContact bool       `json:"gdpr_block_contact"`
+	FullName         string      `json:"full_name" binding:"required,min=2,max=100"`
+	Phone            *string     `json:"phone"`
+	Email            *string     `json:"email"`
+	Address          *AddressDTO `json:"address"`
+	BirthDate        *time.Time  `json:"birth_date"`
+	ContactDate      time.Time   `json:"contact_date" binding:"required"`
+	Status           string      `json:"status" binding:"required,oneof=new in_analysis converted lost"` // TODO: Use constants from model.LeadStatus*
+	WasAttended      bool        `json:"was_attended"`
+	Notes            *string     `json:"notes"`
+	Origin           *string     `json:"origin"`
+	GdprBlockContact bool        `json:"gdpr_block_contact"`
 }
 
 // LeadResponse represents the response body for a lead
 type LeadResponse struct {
-	ID               uuid.UUID  `json:"id"`
-	FullName         string     `json:"full_name"`
-	Phone            *string    `json:"phone"`
-	Email            *string    `json:"email"`
-	BirthDate        *time.Time `json:"birth_date"`
-	ContactDate      time.Time  `json:"contact_date"`
-	Status           string     `json:"status"`
-	WasAttended      bool       `json:"was_attended"`
-	ConvertedAt      *time.Time `json:"converted_at"`
-	Notes            *string    `json:"notes"`
-	Origin           *string    `json:"origin"`
-	GdprBlockContact bool       `json:"gdpr_block_contact"`
-	CreatedAt        time.Time  `json:"created_at"`
-	UpdatedAt        time.Time  `json:"updated_at"`
+	ID                   uuid.UUID  `json:"id"`
+	FullName             string     `json:"full_name"`
+	Phone                *string    `json:"phone"`
+	Email                *string    `json:"email"`
+	Address              AddressDTO `json:"address"`
+	BirthDate            *time.Time `json:"birth_date"`
+	ContactDate          time.Time  `json:"contact_date"`
+	Status               string     `json:"status"`
+	WasAttended          bool       `json:"was_attended"`
+	ConvertedAt          *time.Time `json:"converted_at"`
+	Notes                *string    `json:"notes"`
+	Origin               *string    `json:"origin"`
+	GdprBlockContact     bool       `json:"gdpr_block_contact"`
+	StageID              *uuid.UUID `json:"stage_id"`
+	LostReasonID         *uuid.UUID `json:"lost_reason_id"`
+	NextFollowUpAt       *time.Time `json:"next_follow_up_at"`
+	DuplicateOfLeadID    *uuid.UUID `json:"duplicate_of_lead_id,omitempty"`
+	DuplicateOfPatientID *uuid.UUID `json:"duplicate_of_patient_id,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
 }
 
 // NewLeadResponse creates a new LeadResponse from a Lead model
 func NewLeadResponse(lead model.Lead) LeadResponse {
 	return LeadResponse{
-		ID:               lead.ID,
-		FullName:         lead.FullName,
-		Phone:            lead.Phone,
-		Email:            lead.Email,
-		BirthDate:        lead.BirthDate,
-		ContactDate:      lead.ContactDate,
-		Status:           lead.Status,
-		WasAttended:      lead.WasAttended,
-		ConvertedAt:      lead.ConvertedAt,
-		Notes:            lead.Notes,
-		Origin:           lead.Origin,
-		GdprBlockContact: lead.GdprBlockContact,
-		CreatedAt:        lead.CreatedAt,
-		UpdatedAt:        lead.UpdatedAt,
+		ID:                   lead.ID,
+		FullName:             lead.FullName,
+		Phone:                lead.Phone,
+		Email:                lead.Email,
+		Address:              NewAddressDTO(lead.Address),
+		BirthDate:            lead.BirthDate,
+		ContactDate:          lead.ContactDate,
+		Status:               lead.Status,
+		WasAttended:          lead.WasAttended,
+		ConvertedAt:          lead.ConvertedAt,
+		Notes:                lead.Notes,
+		Origin:               lead.Origin,
+		GdprBlockContact:     lead.GdprBlockContact,
+		StageID:              lead.StageID,
+		LostReasonID:         lead.LostReasonID,
+		NextFollowUpAt:       lead.NextFollowUpAt,
+		DuplicateOfLeadID:    lead.DuplicateOfLeadID,
+		DuplicateOfPatientID: lead.DuplicateOfPatientID,
+		CreatedAt:            lead.CreatedAt,
+		UpdatedAt:            lead.UpdatedAt,
 	}
 }
 
-// ConvertLeadRequest represents the request body for converting a lead to a patient
+// ConvertLeadRequest represents the request body for converting a lead to a patient.
+// Name, phone, email and birth date default to the lead's; birth date is required if the lead has none.
 type ConvertLeadRequest struct {
-	CostCenterID uuid.UUID `json:"cost_center_id" binding:"required"`
+	CostCenterID          uuid.UUID   `json:"cost_center_id" binding:"required"`
+	FullName              *string     `json:"full_name" binding:"omitempty,min=2,max=100"`
+	SocialName            *string     `json:"social_name"`
+	BirthDate             *time.Time  `json:"birth_date"`
+	Document              *string     `json:"document"`
+	Phone                 *string     `json:"phone"`
+	Email                 *string     `json:"email"`
+	Gender                *string     `json:"gender"`
+	Address               *string     `json:"address"`
+	PostalAddress         *AddressDTO `json:"postal_address"` // Defaults to the lead's address
+	ResidesWith           *string     `json:"resides_with"`
+	EmergencyContactName  *string     `json:"emergency_contact_name"`
+	EmergencyContactPhone *string     `json:"emergency_contact_phone"`
+	Observation           *string     `json:"observation"`
+	DefaultRepasseType    *string     `json:"default_repasse_type" binding:"omitempty,oneof=percent fixed"`
+	DefaultRepasseValue   *int64      `json:"default_repasse_value"`
+
+	// AnamneseTemplateID attaches the template to be used for the patient's intake anamnese
+	AnamneseTemplateID *uuid.UUID `json:"anamnese_template_id"`
+
+	// FirstAppointment optionally schedules the first appointment with the new patient
+	FirstAppointment *ConvertLeadAppointmentRequest `json:"first_appointment"`
+}
+
+// ConvertLeadAppointmentRequest represents the first appointment scheduled during a lead conversion
+type ConvertLeadAppointmentRequest struct {
+	ProfessionalID     *uuid.UUID `json:"professional_id"`
+	ServiceTitle       string     `json:"service_title" binding:"required,min=2,max=100"`
+	StartTime          time.Time  `json:"start_time" binding:"required"`
+	EndTime            time.Time  `json:"end_time" binding:"required"`
+	Notes              string     `json:"notes" binding:"max=1000"`
+	CustomRepasseType  *string    `json:"custom_repasse_type" binding:"omitempty,oneof=percent fixed"`
+	CustomRepasseValue *int64     `json:"custom_repasse_value"`
+}
+
+// ConvertLeadResponse represents the result of a lead conversion
+type ConvertLeadResponse struct {
+	Message            string     `json:"message"`
+	PatientID          uuid.UUID  `json:"patient_id"`
+	AppointmentID      *uuid.UUID `json:"appointment_id"`
+	AnamneseTemplateID *uuid.UUID `json:"anamnese_template_id"`
 }
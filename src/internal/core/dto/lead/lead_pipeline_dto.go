@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// LeadStageRequest represents the request body for creating or updating a pipeline stage
+type LeadStageRequest struct {
+	Name     string `json:"name" binding:"required,min=2,max=50"`
+	Position int    `json:"position" binding:"min=0"`
+	Status   string `json:"status" binding:"required,oneof=new in_analysis converted lost"`
+	IsActive *bool  `json:"is_active"`
+}
+
+// LeadStageResponse represents the response body for a pipeline stage
+type LeadStageResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Position int       `json:"position"`
+	Status   string    `json:"status"`
+	IsActive bool      `json:"is_active"`
+}
+
+// NewLeadStageResponse creates a new LeadStageResponse from a LeadStage model
+func NewLeadStageResponse(stage model.LeadStage) LeadStageResponse {
+	return LeadStageResponse{
+		ID:       stage.ID,
+		Name:     stage.Name,
+		Position: stage.Position,
+		Status:   stage.Status,
+		IsActive: stage.IsActive,
+	}
+}
+
+// LeadLostReasonRequest represents the request body for creating or updating a lost reason
+type LeadLostReasonRequest struct {
+	Name     string `json:"name" binding:"required,min=2,max=100"`
+	IsActive *bool  `json:"is_active"`
+}
+
+// LeadLostReasonResponse represents the response body for a lost reason
+type LeadLostReasonResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	IsActive bool      `json:"is_active"`
+}
+
+// NewLeadLostReasonResponse creates a new LeadLostReasonResponse from a LeadLostReason model
+func NewLeadLostReasonResponse(reason model.LeadLostReason) LeadLostReasonResponse {
+	return LeadLostReasonResponse{
+		ID:       reason.ID,
+		Name:     reason.Name,
+		IsActive: reason.IsActive,
+	}
+}
+
+// MoveLeadStageRequest represents the request body for moving a lead to another pipeline stage
+type MoveLeadStageRequest struct {
+	StageID      uuid.UUID  `json:"stage_id" binding:"required"`
+	LostReasonID *uuid.UUID `json:"lost_reason_id"`
+}
+
+// LeadFollowUpRequest represents the request body for scheduling (or clearing, with null) the next follow-up
+type LeadFollowUpRequest struct {
+	NextFollowUpAt *time.Time `json:"next_follow_up_at"`
+}
+
+// LeadActivityRequest represents the request body for recording an interaction with a lead
+type LeadActivityRequest struct {
+	Type       string     `json:"type" binding:"required,oneof=call message email meeting"`
+	Outcome    string     `json:"outcome" binding:"required,oneof=reached no_answer left_message scheduled not_interested"`
+	Notes      *string    `json:"notes"`
+	OccurredAt *time.Time `json:"occurred_at"`
+	// NextFollowUpAt schedules the next contact; ClearFollowUp removes the current one
+	NextFollowUpAt *time.Time `json:"next_follow_up_at"`
+	ClearFollowUp  bool       `json:"clear_follow_up"`
+}
+
+// LeadActivityResponse represents the response body for a lead activity
+type LeadActivityResponse struct {
+	ID         uuid.UUID `json:"id"`
+	LeadID     uuid.UUID `json:"lead_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Type       string    `json:"type"`
+	Outcome    string    `json:"outcome"`
+	Notes      *string   `json:"notes"`
+	OccurredAt time.Time `json:"occurred_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewLeadActivityResponse creates a new LeadActivityResponse from a LeadActivity model
+func NewLeadActivityResponse(activity model.LeadActivity) LeadActivityResponse {
+	return LeadActivityResponse{
+		ID:         activity.ID,
+		LeadID:     activity.LeadID,
+		UserID:     activity.UserID,
+		Type:       activity.Type,
+		Outcome:    activity.Outcome,
+		Notes:      activity.Notes,
+		OccurredAt: activity.OccurredAt,
+		CreatedAt:  activity.CreatedAt,
+	}
+}
+
+// LeadFollowUpResponse represents a lead due for contact
+type LeadFollowUpResponse struct {
+	LeadResponse
+	LastActivityAt *time.Time `json:"last_activity_at"`
+}
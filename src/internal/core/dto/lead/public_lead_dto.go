@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// PublicLeadRequest represents a contact form submitted from the website
+type PublicLeadRequest struct {
+	FullName string  `json:"full_name" binding:"required,min=2,max=100"`
+	Phone    *string `json:"phone" binding:"omitempty,max=20"`
+	Email    *string `json:"email" binding:"omitempty,email,max=100"`
+	Message  *string `json:"message" binding:"omitempty,max=2000"`
+
+	// Attribution; utm_source and source may also be sent as query parameters
+	UtmSource   *string `json:"utm_source" binding:"omitempty,max=50"`
+	UtmMedium   *string `json:"utm_medium" binding:"omitempty,max=50"`
+	UtmCampaign *string `json:"utm_campaign" binding:"omitempty,max=100"`
+	Source      *string `json:"source" binding:"omitempty,max=50"`
+
+	// Consent
+	ConsentDataProcessing bool    `json:"consent_data_processing"`
+	ConsentContact        bool    `json:"consent_contact"`
+	PolicyVersion         *string `json:"policy_version" binding:"omitempty,max=50"`
+
+	// Anti-spam: Website is a hidden honeypot field and FormRenderedAt is when the form was shown
+	Website        string    `json:"website"`
+	FormRenderedAt time.Time `json:"form_rendered_at" binding:"required"`
+}
@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// CareEpisodeRequest represents the request body for opening an episode of care
+type CareEpisodeRequest struct {
+	StartDate *time.Time `json:"start_date"` // Defaults to now
+}
+
+// CareEpisodeCloseRequest represents the request body for closing an episode of care
+type CareEpisodeCloseRequest struct {
+	EndDate          *time.Time `json:"end_date"`                                                   // Defaults to now
+	Reason           string     `json:"reason" binding:"required,oneof=discharge referral dropout"` // Use constants from model.CareEpisodeEndReason*
+	ReferredTo       *string    `json:"referred_to" binding:"required_if=Reason referral,omitempty,max=150"`
+	DischargeSummary *string    `json:"discharge_summary"`
+}
+
+// CareEpisodeResponse represents the response body for an episode of care
+type CareEpisodeResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	PatientID        uuid.UUID  `json:"patient_id"`
+	Number           int        `json:"number"`
+	StartDate        time.Time  `json:"start_date"`
+	EndDate          *time.Time `json:"end_date"`
+	EndReason        *string    `json:"end_reason"`
+	ReferredTo       *string    `json:"referred_to"`
+	DischargeSummary *string    `json:"discharge_summary"`
+	IsOpen           bool       `json:"is_open"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// NewCareEpisodeResponse creates a new CareEpisodeResponse from a CareEpisode model
+func NewCareEpisodeResponse(episode model.CareEpisode) CareEpisodeResponse {
+	return CareEpisodeResponse{
+		ID:               episode.ID,
+		PatientID:        episode.PatientID,
+		Number:           episode.Number,
+		StartDate:        episode.StartDate,
+		EndDate:          episode.EndDate,
+		EndReason:        episode.EndReason,
+		ReferredTo:       episode.ReferredTo,
+		DischargeSummary: episode.DischargeSummary,
+		IsOpen:           episode.IsOpen(),
+		CreatedAt:        episode.CreatedAt,
+		UpdatedAt:        episode.UpdatedAt,
+	}
+}
+
+// CareEpisodeCloseResponse represents the response body for closing an episode of care
+type CareEpisodeCloseResponse struct {
+	Episode              CareEpisodeResponse `json:"episode"`
+	CanceledAppointments int64               `json:"canceled_appointments"` // Appointments that were still scheduled
+}
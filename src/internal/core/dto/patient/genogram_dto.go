@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// PatientFamilyLinkRequest represents the request body for linking two people of a patient's family tree.
+// Each end is the patient or one of the patient's family members.
+type PatientFamilyLinkRequest struct {
+	FromID uuid.UUID `json:"from_id" binding:"required"`
+	ToID   uuid.UUID `json:"to_id" binding:"required"`
+	Type   string    `json:"type" binding:"required,oneof=parent child spouse"` // "from is parent/child/spouse of to"
+	Notes  *string   `json:"notes"`
+}
+
+// PatientFamilyLinkResponse represents the response body for a link of a patient's family tree
+type PatientFamilyLinkResponse struct {
+	ID        uuid.UUID `json:"id"`
+	PatientID uuid.UUID `json:"patient_id"`
+	FromID    uuid.UUID `json:"from_id"`
+	ToID      uuid.UUID `json:"to_id"`
+	Type      string    `json:"type"`
+	Notes     *string   `json:"notes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewPatientFamilyLinkResponse creates a new PatientFamilyLinkResponse from a PatientFamilyLink model
+func NewPatientFamilyLinkResponse(link model.PatientFamilyLink) PatientFamilyLinkResponse {
+	return PatientFamilyLinkResponse{
+		ID:        link.ID,
+		PatientID: link.PatientID,
+		FromID:    link.FromID,
+		ToID:      link.ToID,
+		Type:      link.Type,
+		Notes:     link.Notes,
+		CreatedAt: link.CreatedAt,
+	}
+}
+
+// GenogramNodeResponse represents a person of the family tree
+type GenogramNodeResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	Gender       string     `json:"gender"` // male, female or unknown
+	BirthDate    *time.Time `json:"birth_date"`
+	DeathDate    *time.Time `json:"death_date"`
+	IsDeceased   bool       `json:"is_deceased"`
+	IsPatient    bool       `json:"is_patient"`
+	Relationship string     `json:"relationship,omitempty"` // Relationship to the patient
+	Notes        *string    `json:"notes"`
+}
+
+// GenogramEdgeResponse represents a link of the family tree
+type GenogramEdgeResponse struct {
+	From  uuid.UUID `json:"from"`
+	To    uuid.UUID `json:"to"`
+	Type  string    `json:"type"` // parent (from is parent of to), spouse, sibling or relationship
+	Label string    `json:"label,omitempty"`
+}
+
+// GenogramResponse represents the family tree of a patient as a graph
+type GenogramResponse struct {
+	PatientID uuid.UUID              `json:"patient_id"`
+	Nodes     []GenogramNodeResponse `json:"nodes"`
+	Edges     []GenogramEdgeResponse `json:"edges"`
+}
+
+// NewGenogramResponse creates a new GenogramResponse from a genogram
+func NewGenogramResponse(patientID uuid.UUID, genogram helper.Genogram) GenogramResponse {
+	response := GenogramResponse{
+		PatientID: patientID,
+		Nodes:     []GenogramNodeResponse{},
+		Edges:     []GenogramEdgeResponse{},
+	}
+	for _, node := range genogram.Nodes {
+		response.Nodes = append(response.Nodes, GenogramNodeResponse{
+			ID:           node.ID,
+			Name:         node.Name,
+			Gender:       node.Gender,
+			BirthDate:    node.BirthDate,
+			DeathDate:    node.DeathDate,
+			IsDeceased:   node.IsDeceased,
+			IsPatient:    node.IsPatient,
+			Relationship: node.Relationship,
+			Notes:        node.Notes,
+		})
+	}
+	for _, edge := range genogram.Edges {
+		response.Edges = append(response.Edges, GenogramEdgeResponse{
+			From:  edge.From,
+			To:    edge.To,
+			Type:  edge.Type,
+			Label: edge.Label,
+		})
+	}
+	return response
+}
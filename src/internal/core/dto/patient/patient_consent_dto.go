@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// PatientConsentRequest represents the request body for recording a signed consent
+type PatientConsentRequest struct {
+	GuardianID        *uuid.UUID `json:"guardian_id"`                                                       // Family member who signed; required for minors
+	Type              string     `json:"type" binding:"required,oneof=treatment data_processing recording"` // Use constants from model.ConsentType*
+	SignedAt          time.Time  `json:"signed_at" binding:"required"`
+	ValidUntil        *time.Time `json:"valid_until"`
+	DocumentReference string     `json:"document_reference" binding:"required,max=255"`
+	Notes             *string    `json:"notes"`
+}
+
+// PatientConsentResponse represents the response body for a patient consent
+type PatientConsentResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	PatientID         uuid.UUID  `json:"patient_id"`
+	GuardianID        *uuid.UUID `json:"guardian_id"`
+	Type              string     `json:"type"`
+	SignedAt          time.Time  `json:"signed_at"`
+	ValidUntil        *time.Time `json:"valid_until"`
+	DocumentReference string     `json:"document_reference"`
+	Notes             *string    `json:"notes"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+	IsValid           bool       `json:"is_valid"` // Signed, not revoked and not expired now
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// NewPatientConsentResponse creates a new PatientConsentResponse from a PatientConsent model
+func NewPatientConsentResponse(consent model.PatientConsent) PatientConsentResponse {
+	return PatientConsentResponse{
+		ID:                consent.ID,
+		PatientID:         consent.PatientID,
+		GuardianID:        consent.GuardianID,
+		Type:              consent.Type,
+		SignedAt:          consent.SignedAt,
+		ValidUntil:        consent.ValidUntil,
+		DocumentReference: consent.DocumentReference,
+		Notes:             consent.Notes,
+		RevokedAt:         consent.RevokedAt,
+		IsValid:           consent.IsValidAt(time.Now()),
+		CreatedAt:         consent.CreatedAt,
+	}
+}
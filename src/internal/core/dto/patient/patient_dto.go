@@ -17,6 +17,7 @@ type PatientRequest struct {
 	Email                 *string    `json:"email"`
 	Gender                *string    `json:"gender"`
 	Address               *string    `json:"address"`
+	PostalAddress         *AddressDTO `json:"postal_address"`
 	ResidesWith           *string    `json:"resides_with"`
 	EmergencyContactName  *string    `json:"emergency_contact_name"`
 	EmergencyContactPhone *string    `json:"emergency_contact_phone"`
@@ -39,6 +40,7 @@ type PatientResponse struct {
 	Email                 *string    `json:"email"`
 	Gender                *string    `json:"gender"`
 	Address               *string    `json:"address"`
+	PostalAddress         AddressDTO `json:"postal_address"`
 	ResidesWith           *string    `json:"resides_with"`
 	EmergencyContactName  *string    `json:"emergency_contact_name"`
 	EmergencyContactPhone *string    `json:"emergency_contact_phone"`
@@ -46,6 +48,9 @@ type PatientResponse struct {
 	DefaultRepasseType    *string    `json:"default_repasse_type"`
 	DefaultRepasseValue   *int64     `json:"default_repasse_value"`
 	IsActive              bool       `json:"is_active"`
+	LeadID                *uuid.UUID `json:"lead_id"`
+	Origin                *string    `json:"origin"`
+	AnamneseTemplateID    *uuid.UUID `json:"anamnese_template_id"`
 	CreatedAt             time.Time  `json:"created_at"`
 	UpdatedAt             time.Time  `json:"updated_at"`
 }
@@ -64,6 +69,7 @@ func NewPatientResponse(patient model.Patient) PatientResponse {
 		Email:                 patient.Email,
 		Gender:                patient.Gender,
 		Address:               patient.Address,
+		PostalAddress:         NewAddressDTO(patient.PostalAddress),
 		ResidesWith:           patient.ResidesWith,
 		EmergencyContactName:  patient.EmergencyContactName,
 		EmergencyContactPhone: patient.EmergencyContactPhone,
@@ -71,6 +77,9 @@ func NewPatientResponse(patient model.Patient) PatientResponse {
 		DefaultRepasseType:    patient.DefaultRepasseType,
 		DefaultRepasseValue:   patient.DefaultRepasseValue,
 		IsActive:              patient.IsActive,
+		LeadID:                patient.LeadID,
+		Origin:                patient.Origin,
+		AnamneseTemplateID:    patient.AnamneseTemplateID,
 		CreatedAt:             patient.CreatedAt,
 		UpdatedAt:             patient.UpdatedAt,
 	}
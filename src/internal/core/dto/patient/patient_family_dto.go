@@ -8,38 +8,68 @@ import (
 
 // PatientFamilyRequest represents the request body for creating or updating a patient family member
 type PatientFamilyRequest struct {
-	PatientID    uuid.UUID  `json:"patient_id" binding:"required"`
-	Relationship string     `json:"relationship" binding:"required"` // Use constants from model.Relationship*
-	Name         string     `json:"name" binding:"required,min=2,max=100"`
-	BirthDate    *time.Time `json:"birth_date"`
-	Schooling    *string    `json:"schooling"`
-	Occupation   *string    `json:"occupation"`
+	PatientID              uuid.UUID   `json:"patient_id" binding:"required"`
+	Relationship           string      `json:"relationship" binding:"required"` // Use constants from model.Relationship*
+	Name                   string      `json:"name" binding:"required,min=2,max=100"`
+	BirthDate              *time.Time  `json:"birth_date"`
+	Schooling              *string     `json:"schooling"`
+	Occupation             *string     `json:"occupation"`
+	Document               *string     `json:"document"`
+	Phone                  *string     `json:"phone"`
+	Email                  *string     `json:"email" binding:"omitempty,email"`
+	Address                *AddressDTO `json:"address"`
+	IsLegalResponsible     bool        `json:"is_legal_responsible"`
+	IsFinancialResponsible bool        `json:"is_financial_responsible"`
+	Gender                 *string     `json:"gender"`
+	IsDeceased             bool        `json:"is_deceased"`
+	DeathDate              *time.Time  `json:"death_date"`
+	Notes                  *string     `json:"notes"`
 }
 
 // PatientFamilyResponse represents the response body for a patient family member
 type PatientFamilyResponse struct {
-	ID           uuid.UUID  `json:"id"`
-	PatientID    uuid.UUID  `json:"patient_id"`
-	Relationship string     `json:"relationship"`
-	Name         string     `json:"name"`
-	BirthDate    *time.Time `json:"birth_date"`
-	Schooling    *string    `json:"schooling"`
-	Occupation   *string    `json:"occupation"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID                     uuid.UUID  `json:"id"`
+	PatientID              uuid.UUID  `json:"patient_id"`
+	Relationship           string     `json:"relationship"`
+	Name                   string     `json:"name"`
+	BirthDate              *time.Time `json:"birth_date"`
+	Schooling              *string    `json:"schooling"`
+	Occupation             *string    `json:"occupation"`
+	Document               *string    `json:"document"`
+	Phone                  *string    `json:"phone"`
+	Email                  *string    `json:"email"`
+	Address                AddressDTO `json:"address"`
+	IsLegalResponsible     bool       `json:"is_legal_responsible"`
+	IsFinancialResponsible bool       `json:"is_financial_responsible"`
+	Gender                 *string    `json:"gender"`
+	IsDeceased             bool       `json:"is_deceased"`
+	DeathDate              *time.Time `json:"death_date"`
+	Notes                  *string    `json:"notes"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
 }
 
 // NewPatientFamilyResponse creates a new PatientFamilyResponse from a PatientFamily model
 func NewPatientFamilyResponse(patientFamily model.PatientFamily) PatientFamilyResponse {
 	return PatientFamilyResponse{
-		ID:           patientFamily.ID,
-		PatientID:    patientFamily.PatientID,
-		Relationship: patientFamily.Relationship,
-		Name:         patientFamily.Name,
-		BirthDate:    patientFamily.BirthDate,
-		Schooling:    patientFamily.Schooling,
-		Occupation:   patientFamily.Occupation,
-		CreatedAt:    patientFamily.CreatedAt,
-		UpdatedAt:    patientFamily.UpdatedAt,
+		ID:                     patientFamily.ID,
+		PatientID:              patientFamily.PatientID,
+		Relationship:           patientFamily.Relationship,
+		Name:                   patientFamily.Name,
+		BirthDate:              patientFamily.BirthDate,
+		Schooling:              patientFamily.Schooling,
+		Occupation:             patientFamily.Occupation,
+		Document:               patientFamily.Document,
+		Phone:                  patientFamily.Phone,
+		Email:                  patientFamily.Email,
+		Address:                NewAddressDTO(patientFamily.Address),
+		IsLegalResponsible:     patientFamily.IsLegalResponsible,
+		IsFinancialResponsible: patientFamily.IsFinancialResponsible,
+		Gender:                 patientFamily.Gender,
+		IsDeceased:             patientFamily.IsDeceased,
+		DeathDate:              patientFamily.DeathDate,
+		Notes:                  patientFamily.Notes,
+		CreatedAt:              patientFamily.CreatedAt,
+		UpdatedAt:              patientFamily.UpdatedAt,
 	}
 }
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// PatientDuplicateResponse represents a pair of patients that probably are the same person
+type PatientDuplicateResponse struct {
+	Patient   PatientResponse `json:"patient"`   // Oldest record, suggested as survivor
+	Duplicate PatientResponse `json:"duplicate"` // Newer record, suggested to be merged
+	Reasons   []string        `json:"reasons"`
+	Score     float64         `json:"score"` // Name similarity, from 0 to 1
+}
+
+// PatientMergeRequest represents the request body for merging a duplicate into a patient
+type PatientMergeRequest struct {
+	DuplicateID uuid.UUID `json:"duplicate_id" binding:"required"`
+}
+
+// PatientMergeResponse represents the audit record of a patient merge
+type PatientMergeResponse struct {
+	ID              uuid.UUID        `json:"id"`
+	SurvivorID      uuid.UUID        `json:"survivor_id"`
+	MergedPatientID uuid.UUID        `json:"merged_patient_id"`
+	MergedSnapshot  json.RawMessage  `json:"merged_snapshot"`
+	MovedRecords    map[string]int64 `json:"moved_records"`
+	FilledFields    []string         `json:"filled_fields"`
+	MergedAt        time.Time        `json:"merged_at"`
+}
+
+// NewPatientMergeResponse creates a new PatientMergeResponse from a PatientMerge model
+func NewPatientMergeResponse(merge model.PatientMerge) PatientMergeResponse {
+	response := PatientMergeResponse{
+		ID:              merge.ID,
+		SurvivorID:      merge.SurvivorID,
+		MergedPatientID: merge.MergedPatientID,
+		MergedSnapshot:  json.RawMessage(merge.MergedSnapshot),
+		MovedRecords:    map[string]int64{},
+		FilledFields:    []string{},
+		MergedAt:        merge.MergedAt,
+	}
+	_ = json.Unmarshal([]byte(merge.MovedRecords), &response.MovedRecords)
+	if merge.FilledFields != nil && *merge.FilledFields != "" {
+		response.FilledFields = strings.Split(*merge.FilledFields, ",")
+	}
+	return response
+}
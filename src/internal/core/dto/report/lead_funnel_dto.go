@@ -0,0 +1,81 @@
+package dto
+
+import "github.com/LacirJR/psygrow-api/src/internal/core/port"
+
+// LeadFunnelTotals summarizes all the leads of the report
+type LeadFunnelTotals struct {
+	Leads          int64   `json:"leads"`
+	Converted      int64   `json:"converted"`
+	Lost           int64   `json:"lost"`
+	Open           int64   `json:"open"`
+	ConversionRate float64 `json:"conversion_rate"`
+	Revenue        int64   `json:"revenue"` // Cents
+}
+
+// LeadOriginFunnelResponse adds the conversion rate to an origin summary
+type LeadOriginFunnelResponse struct {
+	port.LeadOriginFunnel
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+// LeadStageFunnelResponse adds the share of leads lost from a stage
+type LeadStageFunnelResponse struct {
+	port.LeadStageFunnel
+	DropOffRate float64 `json:"drop_off_rate"`
+}
+
+// LeadMonthlyCohortResponse adds the conversion rate to a monthly cohort
+type LeadMonthlyCohortResponse struct {
+	port.LeadMonthlyCohort
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+// LeadFunnelResponse represents the lead funnel and acquisition report
+type LeadFunnelResponse struct {
+	StartDate        *string                     `json:"start_date"`
+	EndDate          *string                     `json:"end_date"`
+	Origins          []string                    `json:"origins"`
+	Totals           LeadFunnelTotals            `json:"totals"`
+	ByOrigin         []LeadOriginFunnelResponse  `json:"by_origin"`
+	TimeToConversion port.LeadConversionTime     `json:"time_to_conversion"`
+	Stages           []LeadStageFunnelResponse   `json:"stages"`
+	Cohorts          []LeadMonthlyCohortResponse `json:"cohorts"`
+}
+
+// Rate returns part/total rounded to four decimal places, or zero when total is zero
+func Rate(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part*10000/total) / 10000
+}
+
+// NewLeadFunnelResponse assembles the report from the repository results
+func NewLeadFunnelResponse(origins []port.LeadOriginFunnel, conversion port.LeadConversionTime, stages []port.LeadStageFunnel, cohorts []port.LeadMonthlyCohort) LeadFunnelResponse {
+	response := LeadFunnelResponse{
+		TimeToConversion: conversion,
+		ByOrigin:         make([]LeadOriginFunnelResponse, len(origins)),
+		Stages:           make([]LeadStageFunnelResponse, len(stages)),
+		Cohorts:          make([]LeadMonthlyCohortResponse, len(cohorts)),
+	}
+
+	for i, origin := range origins {
+		response.ByOrigin[i] = LeadOriginFunnelResponse{LeadOriginFunnel: origin, ConversionRate: Rate(origin.Converted, origin.Leads)}
+		response.Totals.Leads += origin.Leads
+		response.Totals.Converted += origin.Converted
+		response.Totals.Lost += origin.Lost
+		response.Totals.Revenue += origin.Revenue
+	}
+	response.Totals.Open = response.Totals.Leads - response.Totals.Converted - response.Totals.Lost
+	response.Totals.ConversionRate = Rate(response.Totals.Converted, response.Totals.Leads)
+
+	for i, stage := range stages {
+		response.Stages[i] = LeadStageFunnelResponse{LeadStageFunnel: stage, DropOffRate: Rate(stage.LostFrom, stage.Reached)}
+	}
+
+	for i, cohort := range cohorts {
+		response.Cohorts[i] = LeadMonthlyCohortResponse{LeadMonthlyCohort: cohort, ConversionRate: Rate(cohort.Converted, cohort.Leads)}
+	}
+
+	return response
+}
@@ -0,0 +1,202 @@
+package dto
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"math"
+	"time"
+)
+
+// ScaleResponse represents the response body for a psychometric scale. Items, options and bands
+// are only present when the scale is fetched alone.
+type ScaleResponse struct {
+	ID           uuid.UUID             `json:"id"`
+	Code         string                `json:"code"`
+	Name         string                `json:"name"`
+	Description  string                `json:"description"`
+	Instructions string                `json:"instructions"`
+	Scoring      string                `json:"scoring"`
+	MinScore     *float64              `json:"min_score,omitempty"`
+	MaxScore     *float64              `json:"max_score,omitempty"`
+	Items        []ScaleItemResponse   `json:"items,omitempty"`
+	Options      []ScaleOptionResponse `json:"options,omitempty"`
+	Bands        []ScaleBandResponse   `json:"bands,omitempty"`
+}
+
+// ScaleItemResponse represents an item of a psychometric scale
+type ScaleItemResponse struct {
+	ID            uuid.UUID `json:"id"`
+	ItemNumber    int       `json:"item_number"`
+	Text          string    `json:"text"`
+	ReverseScored bool      `json:"reverse_scored"`
+	Critical      bool      `json:"critical"`
+}
+
+// ScaleOptionResponse represents a response option of a psychometric scale
+type ScaleOptionResponse struct {
+	Value int    `json:"value"`
+	Label string `json:"label"`
+}
+
+// ScaleBandResponse represents a severity band of a psychometric scale
+type ScaleBandResponse struct {
+	MinScore float64 `json:"min_score"`
+	MaxScore float64 `json:"max_score"`
+	Severity string  `json:"severity"`
+}
+
+// NewScaleResponse creates a new ScaleResponse from a Scale model, with the score range the
+// scale can reach when given
+func NewScaleResponse(scale model.Scale, minScore *float64, maxScore *float64) ScaleResponse {
+	response := ScaleResponse{
+		ID:           scale.ID,
+		Code:         scale.Code,
+		Name:         scale.Name,
+		Description:  scale.Description,
+		Instructions: scale.Instructions,
+		Scoring:      scale.Scoring,
+		MinScore:     minScore,
+		MaxScore:     maxScore,
+	}
+	for _, item := range scale.Items {
+		response.Items = append(response.Items, ScaleItemResponse{
+			ID:            item.ID,
+			ItemNumber:    item.ItemNumber,
+			Text:          item.Text,
+			ReverseScored: item.ReverseScored,
+			Critical:      item.Critical,
+		})
+	}
+	for _, option := range scale.Options {
+		response.Options = append(response.Options, ScaleOptionResponse{Value: option.Value, Label: option.Label})
+	}
+	for _, band := range scale.Bands {
+		response.Bands = append(response.Bands, ScaleBandResponse{MinScore: band.MinScore, MaxScore: band.MaxScore, Severity: band.Severity})
+	}
+	return response
+}
+
+// ScaleApplicationRequest represents the request body for applying a scale to a patient
+type ScaleApplicationRequest struct {
+	ScaleID   uuid.UUID            `json:"scale_id" binding:"required"`
+	SessionID *uuid.UUID           `json:"session_id"`
+	AppliedAt *time.Time           `json:"applied_at"` // Defaults to the start of the session, or now
+	Notes     *string              `json:"notes"`
+	Answers   []ScaleAnswerRequest `json:"answers" binding:"required,min=1,dive"`
+}
+
+// ScaleAnswerRequest represents the option chosen for an item
+type ScaleAnswerRequest struct {
+	ItemID uuid.UUID `json:"item_id" binding:"required"`
+	Value  *int      `json:"value" binding:"required"`
+}
+
+// ScaleApplicationResponse represents the response body for a scale application. Answers are
+// only present when the application is fetched alone.
+type ScaleApplicationResponse struct {
+	ID        uuid.UUID             `json:"id"`
+	PatientID uuid.UUID             `json:"patient_id"`
+	SessionID *uuid.UUID            `json:"session_id"`
+	ScaleID   uuid.UUID             `json:"scale_id"`
+	ScaleCode string                `json:"scale_code"`
+	ScaleName string                `json:"scale_name"`
+	Score     float64               `json:"score"`
+	Severity  *string               `json:"severity"`
+	Flagged   bool                  `json:"flagged"` // Some critical item was answered above its lowest option
+	AppliedAt time.Time             `json:"applied_at"`
+	Notes     *string               `json:"notes"`
+	Answers   []ScaleAnswerResponse `json:"answers,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+// ScaleAnswerResponse represents the answer to an item of a scale application
+type ScaleAnswerResponse struct {
+	ItemID   uuid.UUID `json:"item_id"`
+	Value    int       `json:"value"`
+	Score    int       `json:"score"`
+	Critical bool      `json:"critical"`
+}
+
+// NewScaleApplicationResponse creates a new ScaleApplicationResponse from a ScaleApplication model
+func NewScaleApplicationResponse(application model.ScaleApplication) ScaleApplicationResponse {
+	response := ScaleApplicationResponse{
+		ID:        application.ID,
+		PatientID: application.PatientID,
+		SessionID: application.SessionID,
+		ScaleID:   application.ScaleID,
+		ScaleCode: application.Scale.Code,
+		ScaleName: application.Scale.Name,
+		Score:     application.Score,
+		Severity:  application.Severity,
+		Flagged:   application.Flagged,
+		AppliedAt: application.AppliedAt,
+		Notes:     application.Notes,
+		CreatedAt: application.CreatedAt,
+	}
+	for _, answer := range application.Answers {
+		response.Answers = append(response.Answers, ScaleAnswerResponse{
+			ItemID:   answer.ItemID,
+			Value:    answer.Value,
+			Score:    answer.Score,
+			Critical: answer.Critical,
+		})
+	}
+	return response
+}
+
+// ScaleTimelineResponse represents the scores of a patient on a scale over time
+type ScaleTimelineResponse struct {
+	ScaleID    uuid.UUID            `json:"scale_id"`
+	ScaleCode  string               `json:"scale_code"`
+	ScaleName  string               `json:"scale_name"`
+	Points     []ScaleTimelinePoint `json:"points"`      // Oldest first
+	FirstScore float64              `json:"first_score"` // Baseline of the treatment
+	LastScore  float64              `json:"last_score"`
+	Change     float64              `json:"change"` // Last score minus the first one
+}
+
+// ScaleTimelinePoint represents an application in a score timeline
+type ScaleTimelinePoint struct {
+	ApplicationID uuid.UUID  `json:"application_id"`
+	SessionID     *uuid.UUID `json:"session_id"`
+	AppliedAt     time.Time  `json:"applied_at"`
+	Score         float64    `json:"score"`
+	Severity      *string    `json:"severity"`
+	Flagged       bool       `json:"flagged"`
+}
+
+// NewScaleTimelineResponses groups the applications of a patient, most recent first, into one
+// timeline per scale
+func NewScaleTimelineResponses(applications []model.ScaleApplication) []ScaleTimelineResponse {
+	timelines := []ScaleTimelineResponse{}
+	index := make(map[uuid.UUID]int)
+
+	for i := len(applications) - 1; i >= 0; i-- {
+		application := applications[i]
+		position, ok := index[application.ScaleID]
+		if !ok {
+			position = len(timelines)
+			index[application.ScaleID] = position
+			timelines = append(timelines, ScaleTimelineResponse{
+				ScaleID:    application.ScaleID,
+				ScaleCode:  application.Scale.Code,
+				ScaleName:  application.Scale.Name,
+				FirstScore: application.Score,
+			})
+		}
+
+		timeline := &timelines[position]
+		timeline.Points = append(timeline.Points, ScaleTimelinePoint{
+			ApplicationID: application.ID,
+			SessionID:     application.SessionID,
+			AppliedAt:     application.AppliedAt,
+			Score:         application.Score,
+			Severity:      application.Severity,
+			Flagged:       application.Flagged,
+		})
+		timeline.LastScore = application.Score
+		timeline.Change = math.Round((timeline.LastScore-timeline.FirstScore)*100) / 100
+	}
+
+	return timelines
+}
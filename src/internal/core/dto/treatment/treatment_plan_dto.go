@@ -0,0 +1,199 @@
+package dto
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// TreatmentPlanRequest represents the request body for creating a treatment plan
+type TreatmentPlanRequest struct {
+	Title     string                 `json:"title" binding:"required,max=150"`
+	Summary   string                 `json:"summary"`
+	StartDate *time.Time             `json:"start_date"` // Defaults to now
+	Goals     []TreatmentGoalRequest `json:"goals" binding:"required,min=1,dive"`
+}
+
+// TreatmentPlanRevisionRequest represents the request body for revising a treatment plan. The
+// goals replace the ones of the current version; goals that keep the ID of a current goal keep
+// their progress history.
+type TreatmentPlanRevisionRequest struct {
+	Version int                    `json:"version" binding:"required"` // Current version being revised
+	Reason  string                 `json:"reason" binding:"required"`
+	Summary string                 `json:"summary"`
+	Goals   []TreatmentGoalRequest `json:"goals" binding:"required,min=1,dive"`
+}
+
+// TreatmentGoalRequest represents a goal of a treatment plan
+type TreatmentGoalRequest struct {
+	ID          *uuid.UUID `json:"id"` // Goal of the current version, on revisions
+	Description string     `json:"description" binding:"required,max=500"`
+	TargetDate  *time.Time `json:"target_date"`
+	Status      string     `json:"status" binding:"omitempty,oneof=pending in_progress achieved discontinued"` // Defaults to pending
+}
+
+// TreatmentPlanStatusRequest represents the request body for ending or resuming a treatment plan
+type TreatmentPlanStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active completed discontinued"`
+}
+
+// TreatmentPlanResponse represents the response body for a treatment plan. The version is only
+// present when the plan is fetched alone.
+type TreatmentPlanResponse struct {
+	ID             uuid.UUID                     `json:"id"`
+	PatientID      uuid.UUID                     `json:"patient_id"`
+	Title          string                        `json:"title"`
+	Status         string                        `json:"status"`
+	StartDate      time.Time                     `json:"start_date"`
+	EndedAt        *time.Time                    `json:"ended_at"`
+	CurrentVersion int                           `json:"current_version"`
+	Version        *TreatmentPlanVersionResponse `json:"version,omitempty"`
+	CreatedAt      time.Time                     `json:"created_at"`
+	UpdatedAt      time.Time                     `json:"updated_at"`
+}
+
+// TreatmentPlanVersionResponse represents a version of a treatment plan
+type TreatmentPlanVersionResponse struct {
+	Version        int                     `json:"version"`
+	Summary        string                  `json:"summary"`
+	RevisionReason *string                 `json:"revision_reason"`
+	CreatedBy      uuid.UUID               `json:"created_by"`
+	Goals          []TreatmentGoalResponse `json:"goals"`
+	CreatedAt      time.Time               `json:"created_at"`
+}
+
+// TreatmentGoalResponse represents a goal of a version of a treatment plan
+type TreatmentGoalResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	GoalKey     uuid.UUID  `json:"goal_key"` // Same on the copies of the goal across versions
+	Position    int        `json:"position"`
+	Description string     `json:"description"`
+	TargetDate  *time.Time `json:"target_date"`
+	Status      string     `json:"status"`
+}
+
+// NewTreatmentPlanResponse creates a new TreatmentPlanResponse from a TreatmentPlan model, with
+// one of its versions when given
+func NewTreatmentPlanResponse(plan model.TreatmentPlan, version *model.TreatmentPlanVersion) TreatmentPlanResponse {
+	response := TreatmentPlanResponse{
+		ID:             plan.ID,
+		PatientID:      plan.PatientID,
+		Title:          plan.Title,
+		Status:         plan.Status,
+		StartDate:      plan.StartDate,
+		EndedAt:        plan.EndedAt,
+		CurrentVersion: plan.CurrentVersion,
+		CreatedAt:      plan.CreatedAt,
+		UpdatedAt:      plan.UpdatedAt,
+	}
+	if version != nil {
+		versionResponse := NewTreatmentPlanVersionResponse(*version)
+		response.Version = &versionResponse
+	}
+	return response
+}
+
+// NewTreatmentPlanVersionResponse creates a new TreatmentPlanVersionResponse from a
+// TreatmentPlanVersion model
+func NewTreatmentPlanVersionResponse(version model.TreatmentPlanVersion) TreatmentPlanVersionResponse {
+	response := TreatmentPlanVersionResponse{
+		Version:        version.Version,
+		Summary:        version.Summary,
+		RevisionReason: version.RevisionReason,
+		CreatedBy:      version.CreatedBy,
+		Goals:          []TreatmentGoalResponse{},
+		CreatedAt:      version.CreatedAt,
+	}
+	for _, goal := range version.Goals {
+		response.Goals = append(response.Goals, NewTreatmentGoalResponse(goal))
+	}
+	return response
+}
+
+// NewTreatmentGoalResponse creates a new TreatmentGoalResponse from a TreatmentGoal model
+func NewTreatmentGoalResponse(goal model.TreatmentGoal) TreatmentGoalResponse {
+	return TreatmentGoalResponse{
+		ID:          goal.ID,
+		GoalKey:     goal.GoalKey,
+		Position:    goal.Position,
+		Description: goal.Description,
+		TargetDate:  goal.TargetDate,
+		Status:      goal.Status,
+	}
+}
+
+// TreatmentPlanSummaryResponse represents the progress on the goals of the current version of a
+// treatment plan over time
+type TreatmentPlanSummaryResponse struct {
+	PlanID         uuid.UUID                      `json:"plan_id"`
+	Title          string                         `json:"title"`
+	Status         string                         `json:"status"`
+	CurrentVersion int                            `json:"current_version"`
+	Ratings        int                            `json:"ratings"`
+	Goals          []TreatmentGoalSummaryResponse `json:"goals"`
+	Monthly        []MonthlyProgressResponse      `json:"monthly"` // Oldest month first, every goal included
+}
+
+// TreatmentGoalSummaryResponse represents the progress on a goal, including the ratings given on
+// earlier versions of the goal
+type TreatmentGoalSummaryResponse struct {
+	Goal        TreatmentGoalResponse       `json:"goal"`
+	Ratings     int                         `json:"ratings"`
+	FirstRating *int                        `json:"first_rating"`
+	LastRating  *int                        `json:"last_rating"`
+	Average     *float64                    `json:"average"`
+	Change      *int                        `json:"change"`  // Last rating minus the first one
+	Overdue     bool                        `json:"overdue"` // Target date passed without the goal being achieved or discontinued
+	Points      []GoalProgressPointResponse `json:"points"`  // Oldest first
+}
+
+// GoalProgressPointResponse represents a progress rating of a goal
+type GoalProgressPointResponse struct {
+	EvolutionID uuid.UUID `json:"evolution_id"`
+	RatedAt     time.Time `json:"rated_at"`
+	Rating      int       `json:"rating"`
+}
+
+// MonthlyProgressResponse represents the average progress rating of a month
+type MonthlyProgressResponse struct {
+	Month   string  `json:"month"`
+	Ratings int     `json:"ratings"`
+	Average float64 `json:"average"`
+}
+
+// NewTreatmentPlanSummaryResponse summarizes the progress ratings of a plan on the goals of the
+// given version
+func NewTreatmentPlanSummaryResponse(plan model.TreatmentPlan, version model.TreatmentPlanVersion, progress []model.TreatmentGoalProgress, now time.Time) TreatmentPlanSummaryResponse {
+	response := TreatmentPlanSummaryResponse{
+		PlanID:         plan.ID,
+		Title:          plan.Title,
+		Status:         plan.Status,
+		CurrentVersion: plan.CurrentVersion,
+		Ratings:        len(progress),
+		Goals:          []TreatmentGoalSummaryResponse{},
+		Monthly:        []MonthlyProgressResponse{},
+	}
+
+	for _, summary := range helper.SummarizeGoalProgress(version.Goals, progress, now) {
+		goal := TreatmentGoalSummaryResponse{
+			Goal:        NewTreatmentGoalResponse(summary.Goal),
+			Ratings:     len(summary.Points),
+			FirstRating: summary.FirstRating,
+			LastRating:  summary.LastRating,
+			Average:     summary.Average,
+			Change:      summary.Change,
+			Overdue:     summary.Overdue,
+			Points:      []GoalProgressPointResponse{},
+		}
+		for _, point := range summary.Points {
+			goal.Points = append(goal.Points, GoalProgressPointResponse{EvolutionID: point.EvolutionID, RatedAt: point.RatedAt, Rating: point.Rating})
+		}
+		response.Goals = append(response.Goals, goal)
+	}
+
+	for _, month := range helper.MonthlyGoalProgress(progress) {
+		response.Monthly = append(response.Monthly, MonthlyProgressResponse{Month: month.Month, Ratings: month.Ratings, Average: month.Average})
+	}
+	return response
+}
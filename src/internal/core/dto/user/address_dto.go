@@ -0,0 +1,40 @@
+package dto
+
+import "github.com/LacirJR/psygrow-api/src/internal/core/model"
+
+// AddressDTO represents a structured Brazilian postal address
+type AddressDTO struct {
+	CEP        *string `json:"cep"`
+	Street     *string `json:"street"`
+	Number     *string `json:"number"`
+	Complement *string `json:"complement"`
+	City       *string `json:"city"`
+	State      *string `json:"state"` // UF, e.g. "SP"
+}
+
+// ToModel converts the DTO to the embedded address model, treating nil as an empty address
+func (a *AddressDTO) ToModel() model.Address {
+	if a == nil {
+		return model.Address{}
+	}
+	return model.Address{
+		CEP:        a.CEP,
+		Street:     a.Street,
+		Number:     a.Number,
+		Complement: a.Complement,
+		City:       a.City,
+		State:      a.State,
+	}
+}
+
+// NewAddressDTO creates a new AddressDTO from an Address model
+func NewAddressDTO(address model.Address) AddressDTO {
+	return AddressDTO{
+		CEP:        address.CEP,
+		Street:     address.Street,
+		Number:     address.Number,
+		Complement: address.Complement,
+		City:       address.City,
+		State:      address.State,
+	}
+}
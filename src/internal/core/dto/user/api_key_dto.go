@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"time"
+)
+
+// APIKeyRequest represents the request body for creating an API key
+type APIKeyRequest struct {
+	Name        string     `json:"name" binding:"required,min=2,max=100"`
+	Permissions []string   `json:"permissions" binding:"required,min=1,dive,oneof=leads:create leads:read financial:read"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// APIKeyResponse represents an API key without its secret
+type APIKeyResponse struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Permissions []string   `json:"permissions"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// APIKeyCreatedResponse includes the full key, which is only returned at creation time
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// NewAPIKeyResponse creates a new APIKeyResponse from an APIKey model
+func NewAPIKeyResponse(k model.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:          k.ID.String(),
+		Name:        k.Name,
+		Prefix:      "psg_" + k.Prefix,
+		Permissions: k.PermissionList(),
+		LastUsedAt:  k.LastUsedAt,
+		ExpiresAt:   k.ExpiresAt,
+		RevokedAt:   k.RevokedAt,
+		CreatedAt:   k.CreatedAt,
+	}
+}
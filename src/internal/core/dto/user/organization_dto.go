@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"time"
+)
+
+// OrganizationRequest represents the request body for creating an organization
+type OrganizationRequest struct {
+	Name        string  `json:"name" binding:"required,min=2,max=100"`
+	Slug        string  `json:"slug" binding:"required,min=2,max=60"`
+	LeadOwnerID *string `json:"lead_owner_id" binding:"omitempty,uuid"`
+}
+
+// OrganizationResponse represents the response body for an organization
+type OrganizationResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	LeadOwnerID *string   `json:"lead_owner_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewOrganizationResponse creates a new OrganizationResponse from an Organization model
+func NewOrganizationResponse(o model.Organization) OrganizationResponse {
+	var leadOwnerID *string
+	if o.LeadOwnerID != nil {
+		id := o.LeadOwnerID.String()
+		leadOwnerID = &id
+	}
+
+	return OrganizationResponse{
+		ID:          o.ID.String(),
+		Name:        o.Name,
+		Slug:        o.Slug,
+		LeadOwnerID: leadOwnerID,
+		CreatedAt:   o.CreatedAt,
+	}
+}
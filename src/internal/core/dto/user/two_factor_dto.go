@@ -0,0 +1,38 @@
+package dto
+
+// TwoFactorLoginRequest represents the second login step, exchanging a challenge token for an access token
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required_without=RecoveryCode"`
+	RecoveryCode   string `json:"recovery_code" binding:"required_without=Code"`
+}
+
+// TwoFactorCodeRequest carries a TOTP code to confirm enrollment or sensitive 2FA operations
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// TwoFactorDisableRequest requires both the password and a current TOTP code
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// TwoFactorSetupResponse represents the pending TOTP secret returned during enrollment
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"otpauth_uri"`
+}
+
+// TwoFactorStatusResponse represents the 2FA state of the authenticated user
+type TwoFactorStatusResponse struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RemainingRecoveryCodes int64 `json:"remaining_recovery_codes"`
+}
+
+// TwoFactorPolicyRequest represents the request to enforce 2FA for an organization and/or role
+type TwoFactorPolicyRequest struct {
+	OrganizationID *string `json:"organization_id" binding:"omitempty,uuid"`
+	Role           *string `json:"role" binding:"omitempty,oneof=admin professional secretary viewer"`
+}
@@ -1,9 +1,50 @@
 package dto
 
 type UserRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
-	//Role     string    `json:"role" binding:"default: 'professional'"`
-	Phone *string `json:"phone"`
+	Name           string      `json:"name" binding:"required"`
+	Email          string      `json:"email" binding:"required,email"`
+	Password       string      `json:"password" binding:"required,min=8"`
+	Role           *string     `json:"role" binding:"omitempty,oneof=admin professional secretary viewer"`
+	OrganizationID *string     `json:"organization_id" binding:"omitempty,uuid"`
+	Phone          *string     `json:"phone"`
+	Address        *AddressDTO `json:"address"`
+}
+
+// UserInviteRequest represents the request to invite a user, who sets the password when accepting
+type UserInviteRequest struct {
+	Name           string      `json:"name" binding:"required"`
+	Email          string      `json:"email" binding:"required,email"`
+	Role           *string     `json:"role" binding:"omitempty,oneof=admin professional secretary viewer"`
+	OrganizationID *string     `json:"organization_id" binding:"omitempty,uuid"`
+	Phone          *string     `json:"phone"`
+	Address        *AddressDTO `json:"address"`
+}
+
+// AcceptInviteRequest represents the request to accept an invite and define the password
+type AcceptInviteRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// UserStatusRequest represents the request to activate or deactivate a user
+type UserStatusRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+// UserRoleRequest represents the request to change the role of a user
+type UserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin professional secretary viewer"`
+}
+
+// UserProfileRequest represents the request for users updating their own profile
+type UserProfileRequest struct {
+	Name    string      `json:"name" binding:"required"`
+	Phone   *string     `json:"phone"`
+	Address *AddressDTO `json:"address"`
+}
+
+// ChangePasswordRequest represents the request for users changing their own password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,nefield=CurrentPassword"`
 }
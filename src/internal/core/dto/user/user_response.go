@@ -6,17 +6,37 @@ import (
 )
 
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               string     `json:"id"`
+	OrganizationID   *string    `json:"organization_id"`
+	Name             string     `json:"name"`
+	Email            string     `json:"email"`
+	Role             string     `json:"role"`
+	Phone            *string    `json:"phone"`
+	Address          AddressDTO `json:"address"`
+	IsActive         bool       `json:"is_active"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	LastLoginAt      *time.Time `json:"last_login_at"`
+	CreatedAt        time.Time  `json:"created_at"`
 }
 
 func NewUserResponse(u model.User) UserResponse {
+	var organizationID *string
+	if u.OrganizationID != nil {
+		id := u.OrganizationID.String()
+		organizationID = &id
+	}
+
 	return UserResponse{
-		ID:        u.ID.String(),
-		Name:      u.Name,
-		Email:     u.Email,
-		CreatedAt: u.CreatedAt,
+		ID:               u.ID.String(),
+		OrganizationID:   organizationID,
+		Name:             u.Name,
+		Email:            u.Email,
+		Role:             u.Role,
+		Phone:            u.Phone,
+		Address:          NewAddressDTO(u.Address),
+		IsActive:         u.IsActive,
+		TwoFactorEnabled: u.TwoFactorEnabled,
+		LastLoginAt:      u.LastLoginAt,
+		CreatedAt:        u.CreatedAt,
 	}
 }
@@ -0,0 +1,271 @@
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
+	"github.com/google/uuid"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrAnswerRequired       = errors.New("resposta obrigatória")
+	ErrAnswerInvalidFieldID = errors.New("ID de campo inválido")
+	ErrAnswerUnknownField   = errors.New("campo não pertence ao modelo")
+	ErrAnswerInactiveField  = errors.New("campo inativo")
+	ErrAnswerDuplicated     = errors.New("campo respondido mais de uma vez")
+	ErrAnswerInvalidDate    = errors.New("data inválida, use AAAA-MM-DD")
+	ErrAnswerInvalidTime    = errors.New("data e hora inválidas, use RFC 3339")
+	ErrAnswerInvalidNumber  = errors.New("número inválido")
+	ErrAnswerInvalidBoolean = errors.New("valor inválido, use true ou false")
+	ErrAnswerInvalidOption  = errors.New("opção inválida")
+	ErrAnswerSingleOption   = errors.New("apenas uma opção pode ser escolhida")
+)
+
+// AnamneseAnswer is the answer to a field as sent by the client. Multiselect fields take the
+// chosen options in Values, or a JSON array in Value.
+type AnamneseAnswer struct {
+	FieldID uuid.UUID
+	Value   string
+	Values  []string
+}
+
+// ValidateAnamneseAnswers checks the answers against the fields of a template version and
+// returns them normalized, in field order: dates as AAAA-MM-DD, date-times as RFC 3339,
+// numbers and booleans in their canonical form, and options as written in the template.
+// Fields hidden by their visibility rules are skipped, and empty answers to optional fields
+// are dropped. Errors are keyed by field ID.
+func ValidateAnamneseAnswers(fields []*model.AnamneseField, answers []AnamneseAnswer) ([]AnamneseAnswer, validation.FieldErrors) {
+	return validateAnamneseAnswers(fields, answers, true)
+}
+
+// ValidateAnamneseDraft checks the answers of an anamnese that is still being filled in as
+// ValidateAnamneseAnswers does, except that required fields may be left unanswered
+func ValidateAnamneseDraft(fields []*model.AnamneseField, answers []AnamneseAnswer) ([]AnamneseAnswer, validation.FieldErrors) {
+	return validateAnamneseAnswers(fields, answers, false)
+}
+
+func validateAnamneseAnswers(fields []*model.AnamneseField, answers []AnamneseAnswer, requireAll bool) ([]AnamneseAnswer, validation.FieldErrors) {
+	errs := validation.FieldErrors{}
+
+	byID := make(map[uuid.UUID]*model.AnamneseField, len(fields))
+	for _, field := range fields {
+		byID[field.ID] = field
+	}
+
+	given := make(map[uuid.UUID]AnamneseAnswer, len(answers))
+	for _, answer := range answers {
+		key := answer.FieldID.String()
+		field, ok := byID[answer.FieldID]
+		switch {
+		case !ok:
+			errs.Add(key, ErrAnswerUnknownField)
+		case !field.FieldActive:
+			errs.Add(key, ErrAnswerInactiveField)
+		default:
+			if _, duplicated := given[answer.FieldID]; duplicated {
+				errs.Add(key, ErrAnswerDuplicated)
+				continue
+			}
+			given[answer.FieldID] = answer
+		}
+	}
+
+	ordered := append([]*model.AnamneseField(nil), fields...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].FieldNumber < ordered[j].FieldNumber })
+
+	parsed := make([]AnamneseAnswer, 0, len(ordered))
+	invalid := make(map[uuid.UUID]error)
+	for _, field := range ordered {
+		if !field.FieldActive || errs[field.ID.String()] != "" {
+			continue
+		}
+		answer, err := NormalizeAnamneseAnswer(field, given[field.ID])
+		if err != nil {
+			invalid[field.ID] = err
+			continue
+		}
+		parsed = append(parsed, answer)
+	}
+
+	// Hidden fields are neither required nor kept, even when their answer is invalid
+	visible := AnamneseVisibility(ordered, parsed)
+	for fieldID, err := range invalid {
+		if visible[fieldID] {
+			errs.Add(fieldID.String(), err)
+		}
+	}
+
+	var normalized []AnamneseAnswer
+	byField := make(map[uuid.UUID]AnamneseAnswer, len(parsed))
+	for _, answer := range parsed {
+		byField[answer.FieldID] = answer
+	}
+	for _, field := range ordered {
+		answer, ok := byField[field.ID]
+		if !ok || !visible[field.ID] {
+			continue
+		}
+		if answer.Value == "" && len(answer.Values) == 0 {
+			if field.FieldRequired && requireAll {
+				errs.Add(field.ID.String(), ErrAnswerRequired)
+			}
+			continue
+		}
+		normalized = append(normalized, answer)
+	}
+
+	return normalized, errs
+}
+
+// AnamneseCompletion returns the percentage, from 0 to 100, of the fields shown for the given
+// normalized answers that are answered. Without any field shown it is complete.
+func AnamneseCompletion(fields []*model.AnamneseField, answers []AnamneseAnswer) int {
+	answered := make(map[uuid.UUID]bool, len(answers))
+	for _, answer := range answers {
+		answered[answer.FieldID] = answer.Value != "" || len(answer.Values) > 0
+	}
+
+	shown, done := 0, 0
+	for fieldID, visible := range AnamneseVisibility(fields, answers) {
+		if !visible {
+			continue
+		}
+		shown++
+		if answered[fieldID] {
+			done++
+		}
+	}
+	if shown == 0 {
+		return 100
+	}
+	return done * 100 / shown
+}
+
+// NormalizeAnamneseAnswer parses an answer according to the type of its field. A blank answer
+// is returned empty and without error; whether it is required is left to the caller.
+func NormalizeAnamneseAnswer(field *model.AnamneseField, answer AnamneseAnswer) (AnamneseAnswer, error) {
+	result := AnamneseAnswer{FieldID: field.ID}
+	value := strings.TrimSpace(answer.Value)
+
+	if field.FieldType == model.AnamneseFieldTypeMultiselect {
+		values, err := multiselectValues(value, answer.Values)
+		if err != nil {
+			return result, err
+		}
+		for _, chosen := range values {
+			if !hasOption(field.Options, chosen) {
+				return result, ErrAnswerInvalidOption
+			}
+		}
+		// Each option once, in the order of the template
+		for _, option := range sortedOptions(field.Options) {
+			for _, chosen := range values {
+				if sameOption(option.OptionValue, chosen) {
+					result.Values = append(result.Values, option.OptionValue)
+					break
+				}
+			}
+		}
+		result.Value = strings.Join(result.Values, ", ")
+		return result, nil
+	}
+
+	if len(answer.Values) > 0 {
+		if value != "" || len(answer.Values) > 1 {
+			return result, ErrAnswerSingleOption
+		}
+		value = strings.TrimSpace(answer.Values[0])
+	}
+	if value == "" {
+		return result, nil
+	}
+
+	switch field.FieldType {
+	case model.AnamneseFieldTypeDate:
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			if date, err = time.Parse("02/01/2006", value); err != nil {
+				return result, ErrAnswerInvalidDate
+			}
+		}
+		result.Value = date.Format("2006-01-02")
+	case model.AnamneseFieldTypeDatetime:
+		datetime, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return result, ErrAnswerInvalidTime
+		}
+		result.Value = datetime.Format(time.RFC3339)
+	case model.AnamneseFieldTypeNumber:
+		// Brazilian decimal comma, without thousands separators
+		number, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		if err != nil {
+			return result, ErrAnswerInvalidNumber
+		}
+		result.Value = strconv.FormatFloat(number, 'f', -1, 64)
+	case model.AnamneseFieldTypeCheckbox:
+		switch NormalizeName(value) {
+		case "true", "1", "sim", "s", "yes":
+			result.Value = "true"
+		case "false", "0", "nao", "n", "no":
+			result.Value = "false"
+		default:
+			return result, ErrAnswerInvalidBoolean
+		}
+	case model.AnamneseFieldTypeSelect:
+		for _, option := range field.Options {
+			if sameOption(option.OptionValue, value) {
+				result.Value = option.OptionValue
+				return result, nil
+			}
+		}
+		return result, ErrAnswerInvalidOption
+	default:
+		result.Value = value
+	}
+	return result, nil
+}
+
+// multiselectValues reads the chosen options from Values or from a JSON array in Value,
+// dropping blanks
+func multiselectValues(value string, values []string) ([]string, error) {
+	if len(values) == 0 && value != "" {
+		if err := json.Unmarshal([]byte(value), &values); err != nil {
+			return nil, ErrAnswerInvalidOption
+		}
+	} else if value != "" {
+		return nil, ErrAnswerInvalidOption
+	}
+
+	var chosen []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			chosen = append(chosen, v)
+		}
+	}
+	return chosen, nil
+}
+
+func sortedOptions(options []model.AnamneseFieldOption) []model.AnamneseFieldOption {
+	sorted := append([]model.AnamneseFieldOption(nil), options...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].OptionOrder < sorted[j].OptionOrder })
+	return sorted
+}
+
+func hasOption(options []model.AnamneseFieldOption, value string) bool {
+	for _, option := range options {
+		if sameOption(option.OptionValue, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameOption compares option values ignoring case, accents and surrounding spaces
+func sameOption(option string, value string) bool {
+	return NormalizeName(option) == NormalizeName(value)
+}
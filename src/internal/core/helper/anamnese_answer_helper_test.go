@@ -0,0 +1,116 @@
+package helper
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateAnamneseAnswers(t *testing.T) {
+	field := func(number int, fieldType string, required bool, options ...string) *model.AnamneseField {
+		f := &model.AnamneseField{ID: uuid.New(), FieldNumber: number, FieldType: fieldType, FieldRequired: required, FieldActive: true}
+		for i, value := range options {
+			f.Options = append(f.Options, model.AnamneseFieldOption{ID: uuid.New(), OptionValue: value, OptionOrder: i})
+		}
+		return f
+	}
+
+	birth := field(1, model.AnamneseFieldTypeDate, true)
+	visit := field(2, model.AnamneseFieldTypeDatetime, false)
+	weight := field(3, model.AnamneseFieldTypeNumber, false)
+	smoker := field(4, model.AnamneseFieldTypeCheckbox, false)
+	mood := field(5, model.AnamneseFieldTypeSelect, true, "Estável", "Ansioso")
+	symptoms := field(6, model.AnamneseFieldTypeMultiselect, false, "Insônia", "Cefaleia", "Fadiga")
+	notes := field(7, model.AnamneseFieldTypeText, false)
+	inactive := field(8, model.AnamneseFieldTypeText, true)
+	inactive.FieldActive = false
+	fields := []*model.AnamneseField{notes, symptoms, mood, smoker, weight, visit, birth, inactive}
+
+	answers, errs := ValidateAnamneseAnswers(fields, []AnamneseAnswer{
+		{FieldID: symptoms.ID, Values: []string{"fadiga", " insonia ", "Fadiga"}},
+		{FieldID: birth.ID, Value: "04/03/2012"},
+		{FieldID: visit.ID, Value: "2026-10-19T14:30:00-03:00"},
+		{FieldID: weight.ID, Value: "72,50"},
+		{FieldID: smoker.ID, Value: "Não"},
+		{FieldID: mood.ID, Value: "estavel"},
+		{FieldID: notes.ID, Value: "  "},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, []AnamneseAnswer{
+		{FieldID: birth.ID, Value: "2012-03-04"},
+		{FieldID: visit.ID, Value: "2026-10-19T14:30:00-03:00"},
+		{FieldID: weight.ID, Value: "72.5"},
+		{FieldID: smoker.ID, Value: "false"},
+		{FieldID: mood.ID, Value: "Estável"},
+		{FieldID: symptoms.ID, Value: "Insônia, Fadiga", Values: []string{"Insônia", "Fadiga"}},
+	}, answers)
+
+	// Multiselect also takes a JSON array in the value
+	answers, errs = ValidateAnamneseAnswers([]*model.AnamneseField{symptoms}, []AnamneseAnswer{
+		{FieldID: symptoms.ID, Value: `["Cefaleia"]`},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"Cefaleia"}, answers[0].Values)
+
+	unknown := uuid.New()
+	_, errs = ValidateAnamneseAnswers(fields, []AnamneseAnswer{
+		{FieldID: visit.ID, Value: "19/10/2026 14:30"},
+		{FieldID: weight.ID, Value: "1.000,5"},
+		{FieldID: smoker.ID, Value: "talvez"},
+		{FieldID: mood.ID, Value: "Feliz"},
+		{FieldID: symptoms.ID, Values: []string{"Insônia", "Tontura"}},
+		{FieldID: notes.ID, Value: "a"},
+		{FieldID: notes.ID, Value: "b"},
+		{FieldID: inactive.ID, Value: "x"},
+		{FieldID: unknown, Value: "x"},
+	})
+	assert.Equal(t, map[string]string{
+		birth.ID.String():    ErrAnswerRequired.Error(),
+		visit.ID.String():    ErrAnswerInvalidTime.Error(),
+		weight.ID.String():   ErrAnswerInvalidNumber.Error(),
+		smoker.ID.String():   ErrAnswerInvalidBoolean.Error(),
+		mood.ID.String():     ErrAnswerInvalidOption.Error(),
+		symptoms.ID.String(): ErrAnswerInvalidOption.Error(),
+		notes.ID.String():    ErrAnswerDuplicated.Error(),
+		inactive.ID.String(): ErrAnswerInactiveField.Error(),
+		unknown.String():     ErrAnswerUnknownField.Error(),
+	}, map[string]string(errs))
+
+	_, errs = ValidateAnamneseAnswers([]*model.AnamneseField{mood}, []AnamneseAnswer{
+		{FieldID: mood.ID, Values: []string{"Estável", "Ansioso"}},
+	})
+	assert.Equal(t, ErrAnswerSingleOption.Error(), errs[mood.ID.String()])
+}
+
+func TestValidateAnamneseDraft(t *testing.T) {
+	medication := ruleField(1, model.AnamneseFieldTypeCheckbox, true)
+	medicationName := ruleField(2, model.AnamneseFieldTypeText, true)
+	showIf(medicationName, medication, model.AnamneseRuleOperatorEquals, "true")
+	symptoms := ruleField(3, model.AnamneseFieldTypeMultiselect, false, "Insônia", "Cefaleia")
+	sleepHours := ruleField(4, model.AnamneseFieldTypeNumber, true)
+	showIf(sleepHours, symptoms, model.AnamneseRuleOperatorContains, "Insônia")
+	fields := []*model.AnamneseField{medication, medicationName, symptoms, sleepHours}
+
+	// Drafts may leave required fields empty, and count them as missing
+	answers, errs := ValidateAnamneseDraft(fields, []AnamneseAnswer{
+		{FieldID: medication.ID, Value: "sim"},
+		{FieldID: symptoms.ID, Values: []string{"insonia"}},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, 50, AnamneseCompletion(fields, answers)) // Name and sleep hours are missing
+
+	// Hidden fields do not count
+	answers, errs = ValidateAnamneseDraft(fields, []AnamneseAnswer{
+		{FieldID: medication.ID, Value: "não"},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, 50, AnamneseCompletion(fields, answers)) // Symptoms are shown but unanswered
+
+	_, errs = ValidateAnamneseDraft(fields, []AnamneseAnswer{
+		{FieldID: sleepHours.ID, Value: "muitas"},
+		{FieldID: symptoms.ID, Values: []string{"insonia"}},
+	})
+	assert.Contains(t, errs, sleepHours.ID.String())
+	assert.NotContains(t, errs, medication.ID.String())
+}
@@ -0,0 +1,200 @@
+package helper
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
+	"github.com/google/uuid"
+	"strings"
+)
+
+var (
+	ErrRuleUnknownField = errors.New("a regra depende de um campo que não existe na versão")
+	ErrRuleCycle        = errors.New("as regras de exibição formam um ciclo")
+	ErrRuleOperator     = errors.New("operador não se aplica ao tipo do campo")
+	ErrRuleValue        = errors.New("valor da regra inválido para o campo")
+)
+
+// NormalizeAnamneseRule checks that a rule can be applied to the field it depends on and
+// returns its value normalized as an answer to that field would be
+func NormalizeAnamneseRule(dependsOn *model.AnamneseField, operator string, value string) (string, error) {
+	switch operator {
+	case model.AnamneseRuleOperatorAnswered, model.AnamneseRuleOperatorNotAnswered:
+		return "", nil
+	case model.AnamneseRuleOperatorContains:
+		if dependsOn.FieldType == model.AnamneseFieldTypeText {
+			if strings.TrimSpace(value) == "" {
+				return "", ErrRuleValue
+			}
+			return strings.TrimSpace(value), nil
+		}
+		if dependsOn.FieldType != model.AnamneseFieldTypeMultiselect {
+			return "", ErrRuleOperator
+		}
+		answer, err := NormalizeAnamneseAnswer(dependsOn, AnamneseAnswer{Values: []string{value}})
+		if err != nil || len(answer.Values) != 1 {
+			return "", ErrRuleValue
+		}
+		return answer.Values[0], nil
+	case model.AnamneseRuleOperatorEquals, model.AnamneseRuleOperatorNotEquals:
+		if dependsOn.FieldType == model.AnamneseFieldTypeMultiselect {
+			return "", ErrRuleOperator
+		}
+		answer, err := NormalizeAnamneseAnswer(dependsOn, AnamneseAnswer{Value: value})
+		if err != nil || answer.Value == "" {
+			return "", ErrRuleValue
+		}
+		return answer.Value, nil
+	}
+	return "", ErrRuleOperator
+}
+
+// CheckAnamneseRules checks the visibility rules of the fields of a version: each must depend
+// on another field of the version, apply to its type and not lead back to the field itself.
+// Errors are keyed by the ID of the field holding the rule.
+func CheckAnamneseRules(fields []*model.AnamneseField) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	byKey := make(map[uuid.UUID]*model.AnamneseField, len(fields))
+	for _, field := range fields {
+		byKey[field.FieldKey] = field
+	}
+
+	for _, field := range fields {
+		if field.VisibleIfFieldKey == nil {
+			continue
+		}
+		dependsOn, ok := byKey[*field.VisibleIfFieldKey]
+		if !ok {
+			errs.Add(field.ID.String(), ErrRuleUnknownField)
+			continue
+		}
+
+		var operator, value string
+		if field.VisibleIfOperator != nil {
+			operator = *field.VisibleIfOperator
+		}
+		if field.VisibleIfValue != nil {
+			value = *field.VisibleIfValue
+		}
+		if _, err := NormalizeAnamneseRule(dependsOn, operator, value); err != nil {
+			errs.Add(field.ID.String(), err)
+			continue
+		}
+
+		// Follow the chain of dependencies; reaching the field again closes a cycle
+		for next, steps := dependsOn, 0; next != nil && steps <= len(fields); steps++ {
+			if next.FieldKey == field.FieldKey {
+				errs.Add(field.ID.String(), ErrRuleCycle)
+				break
+			}
+			if next.VisibleIfFieldKey == nil {
+				break
+			}
+			next = byKey[*next.VisibleIfFieldKey]
+		}
+	}
+
+	return errs
+}
+
+// NormalizeAnamneseRules checks the visibility rules of the fields as CheckAnamneseRules does
+// and, when they are valid, stores each rule value as an answer to its field would be stored
+func NormalizeAnamneseRules(fields []*model.AnamneseField) validation.FieldErrors {
+	if errs := CheckAnamneseRules(fields); len(errs) > 0 {
+		return errs
+	}
+
+	byKey := make(map[uuid.UUID]*model.AnamneseField, len(fields))
+	for _, field := range fields {
+		byKey[field.FieldKey] = field
+	}
+	for _, field := range fields {
+		if field.VisibleIfFieldKey == nil || field.VisibleIfOperator == nil {
+			continue
+		}
+		var value string
+		if field.VisibleIfValue != nil {
+			value = *field.VisibleIfValue
+		}
+		value, _ = NormalizeAnamneseRule(byKey[*field.VisibleIfFieldKey], *field.VisibleIfOperator, value)
+		field.VisibleIfValue = &value
+	}
+	return nil
+}
+
+// AnamneseVisibility tells which fields are shown for the given normalized answers. A field
+// is shown when it is active and either has no rule or the field it depends on is shown and
+// its answer matches the rule.
+func AnamneseVisibility(fields []*model.AnamneseField, answers []AnamneseAnswer) map[uuid.UUID]bool {
+	byKey := make(map[uuid.UUID]*model.AnamneseField, len(fields))
+	for _, field := range fields {
+		byKey[field.FieldKey] = field
+	}
+	answerOf := make(map[uuid.UUID]AnamneseAnswer, len(answers))
+	for _, answer := range answers {
+		answerOf[answer.FieldID] = answer
+	}
+
+	visible := make(map[uuid.UUID]bool, len(fields))
+	resolving := make(map[uuid.UUID]bool)
+	var resolve func(field *model.AnamneseField) bool
+	resolve = func(field *model.AnamneseField) bool {
+		if shown, done := visible[field.ID]; done {
+			return shown
+		}
+		if resolving[field.ID] {
+			// Cycles are rejected when rules are saved; hide the fields rather than loop
+			return false
+		}
+		resolving[field.ID] = true
+
+		shown := field.FieldActive
+		if shown && field.VisibleIfFieldKey != nil {
+			dependsOn, ok := byKey[*field.VisibleIfFieldKey]
+			shown = ok && resolve(dependsOn) && anamneseRuleMatches(field, answerOf[dependsOn.ID])
+		}
+
+		visible[field.ID] = shown
+		return shown
+	}
+
+	for _, field := range fields {
+		resolve(field)
+	}
+	return visible
+}
+
+// anamneseRuleMatches evaluates the rule of a field against the normalized answer to the
+// field it depends on
+func anamneseRuleMatches(field *model.AnamneseField, answer AnamneseAnswer) bool {
+	var operator, value string
+	if field.VisibleIfOperator != nil {
+		operator = *field.VisibleIfOperator
+	}
+	if field.VisibleIfValue != nil {
+		value = *field.VisibleIfValue
+	}
+	answered := answer.Value != "" || len(answer.Values) > 0
+
+	switch operator {
+	case model.AnamneseRuleOperatorAnswered:
+		return answered
+	case model.AnamneseRuleOperatorNotAnswered:
+		return !answered
+	case model.AnamneseRuleOperatorEquals:
+		return answered && sameOption(answer.Value, value)
+	case model.AnamneseRuleOperatorNotEquals:
+		return answered && !sameOption(answer.Value, value)
+	case model.AnamneseRuleOperatorContains:
+		if answer.Values == nil {
+			return strings.Contains(NormalizeName(answer.Value), NormalizeName(value))
+		}
+		for _, chosen := range answer.Values {
+			if sameOption(chosen, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package helper
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ruleField(number int, fieldType string, required bool, options ...string) *model.AnamneseField {
+	id := uuid.New()
+	field := &model.AnamneseField{ID: id, FieldKey: id, FieldNumber: number, FieldType: fieldType, FieldRequired: required, FieldActive: true}
+	for i, value := range options {
+		field.Options = append(field.Options, model.AnamneseFieldOption{ID: uuid.New(), OptionValue: value, OptionOrder: i})
+	}
+	return field
+}
+
+func showIf(field *model.AnamneseField, dependsOn *model.AnamneseField, operator string, value string) {
+	field.VisibleIfFieldKey = &dependsOn.FieldKey
+	field.VisibleIfOperator = &operator
+	field.VisibleIfValue = &value
+}
+
+func TestValidateAnamneseAnswersWithRules(t *testing.T) {
+	medication := ruleField(1, model.AnamneseFieldTypeCheckbox, true)
+	medicationName := ruleField(2, model.AnamneseFieldTypeText, true)
+	showIf(medicationName, medication, model.AnamneseRuleOperatorEquals, "true")
+	dosage := ruleField(3, model.AnamneseFieldTypeNumber, true)
+	showIf(dosage, medicationName, model.AnamneseRuleOperatorAnswered, "")
+	symptoms := ruleField(4, model.AnamneseFieldTypeMultiselect, false, "Insônia", "Cefaleia")
+	sleepHours := ruleField(5, model.AnamneseFieldTypeNumber, true)
+	showIf(sleepHours, symptoms, model.AnamneseRuleOperatorContains, "Insônia")
+	fields := []*model.AnamneseField{medication, medicationName, dosage, symptoms, sleepHours}
+
+	assert.Empty(t, CheckAnamneseRules(fields))
+
+	// Hidden fields are not required and their answers, even invalid ones, are dropped
+	answers, errs := ValidateAnamneseAnswers(fields, []AnamneseAnswer{
+		{FieldID: medication.ID, Value: "não"},
+		{FieldID: dosage.ID, Value: "muito"},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, []AnamneseAnswer{{FieldID: medication.ID, Value: "false"}}, answers)
+
+	_, errs = ValidateAnamneseAnswers(fields, []AnamneseAnswer{
+		{FieldID: medication.ID, Value: "sim"},
+		{FieldID: medicationName.ID, Value: "Sertralina"},
+		{FieldID: symptoms.ID, Values: []string{"insonia"}},
+	})
+	assert.Equal(t, map[string]string{
+		dosage.ID.String():     ErrAnswerRequired.Error(),
+		sleepHours.ID.String(): ErrAnswerRequired.Error(),
+	}, map[string]string(errs))
+}
+
+func TestCheckAnamneseRules(t *testing.T) {
+	a := ruleField(1, model.AnamneseFieldTypeSelect, false, "Sim", "Não")
+	b := ruleField(2, model.AnamneseFieldTypeText, false)
+	c := ruleField(3, model.AnamneseFieldTypeCheckbox, false)
+	showIf(a, c, model.AnamneseRuleOperatorEquals, "sim")
+	showIf(b, a, model.AnamneseRuleOperatorNotEquals, "nao")
+	showIf(c, b, model.AnamneseRuleOperatorContains, "ansiedade")
+
+	errs := CheckAnamneseRules([]*model.AnamneseField{a, b, c})
+	assert.Equal(t, map[string]string{
+		a.ID.String(): ErrRuleCycle.Error(),
+		b.ID.String(): ErrRuleCycle.Error(),
+		c.ID.String(): ErrRuleCycle.Error(),
+	}, map[string]string(errs))
+
+	self := ruleField(4, model.AnamneseFieldTypeText, false)
+	showIf(self, self, model.AnamneseRuleOperatorAnswered, "")
+	assert.Equal(t, ErrRuleCycle.Error(), CheckAnamneseRules([]*model.AnamneseField{self})[self.ID.String()])
+
+	c.VisibleIfFieldKey = nil
+	missing := ruleField(5, model.AnamneseFieldTypeText, false)
+	showIf(missing, ruleField(6, model.AnamneseFieldTypeText, false), model.AnamneseRuleOperatorAnswered, "")
+	wrongOperator := ruleField(7, model.AnamneseFieldTypeText, false)
+	showIf(wrongOperator, c, model.AnamneseRuleOperatorContains, "x")
+	wrongValue := ruleField(8, model.AnamneseFieldTypeText, false)
+	showIf(wrongValue, a, model.AnamneseRuleOperatorEquals, "Talvez")
+
+	errs = CheckAnamneseRules([]*model.AnamneseField{a, b, c, missing, wrongOperator, wrongValue})
+	assert.Equal(t, map[string]string{
+		missing.ID.String():       ErrRuleUnknownField.Error(),
+		wrongOperator.ID.String(): ErrRuleOperator.Error(),
+		wrongValue.ID.String():    ErrRuleValue.Error(),
+	}, map[string]string(errs))
+
+	value, err := NormalizeAnamneseRule(a, model.AnamneseRuleOperatorEquals, "nao")
+	assert.NoError(t, err)
+	assert.Equal(t, "Não", value)
+}
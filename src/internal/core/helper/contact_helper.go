@@ -0,0 +1,19 @@
+package helper
+
+import "strings"
+
+// NormalizeEmail lowercases and trims an email for comparison
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// PhoneDigits keeps only the digits of a phone number for comparison
+func PhoneDigits(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,367 @@
+package helper
+
+import (
+	"fmt"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Edge types of a genogram. Parent edges go from the parent to the child.
+const (
+	GenogramEdgeParent       = model.FamilyLinkTypeParent
+	GenogramEdgeSpouse       = model.FamilyLinkTypeSpouse
+	GenogramEdgeSibling      = "sibling"
+	GenogramEdgeRelationship = "relationship" // Other relationship to the patient, named by the label
+)
+
+// Genders of a genogram node
+const (
+	GenogramGenderMale    = "male"
+	GenogramGenderFemale  = "female"
+	GenogramGenderUnknown = "unknown"
+)
+
+// GenogramNode is a person of the family tree: the patient or one of the family members
+type GenogramNode struct {
+	ID           uuid.UUID
+	Name         string
+	Gender       string
+	BirthDate    *time.Time
+	DeathDate    *time.Time
+	IsDeceased   bool
+	IsPatient    bool
+	Relationship string // Relationship to the patient; empty for the patient
+	Notes        *string
+}
+
+// GenogramEdge links two nodes of the family tree
+type GenogramEdge struct {
+	From  uuid.UUID
+	To    uuid.UUID
+	Type  string
+	Label string
+}
+
+// Genogram is the family tree of a patient as a graph
+type Genogram struct {
+	Nodes []GenogramNode
+	Edges []GenogramEdge
+}
+
+// BuildGenogram builds the family tree of a patient from the family members and the links
+// between them. The relationship of each member to the patient adds an edge unless a link
+// already connects them; links whose ends are not in the tree are ignored.
+func BuildGenogram(patient model.Patient, members []model.PatientFamily, links []model.PatientFamilyLink) Genogram {
+	var genogram Genogram
+	birthDate := patient.BirthDate
+
+	genogram.Nodes = append(genogram.Nodes, GenogramNode{
+		ID:        patient.ID,
+		Name:      patient.FullName,
+		Gender:    GenogramGender(patient.Gender, ""),
+		BirthDate: &birthDate,
+		IsPatient: true,
+	})
+	for _, member := range members {
+		genogram.Nodes = append(genogram.Nodes, GenogramNode{
+			ID:           member.ID,
+			Name:         member.Name,
+			Gender:       GenogramGender(member.Gender, member.Relationship),
+			BirthDate:    member.BirthDate,
+			DeathDate:    member.DeathDate,
+			IsDeceased:   member.IsDeceased || member.DeathDate != nil,
+			Relationship: member.Relationship,
+			Notes:        member.Notes,
+		})
+	}
+
+	inTree := make(map[uuid.UUID]bool, len(genogram.Nodes))
+	for _, node := range genogram.Nodes {
+		inTree[node.ID] = true
+	}
+
+	connected := make(map[[2]uuid.UUID]bool)
+	add := func(edge GenogramEdge) {
+		key := [2]uuid.UUID{edge.From, edge.To}
+		if edge.To.String() < edge.From.String() {
+			key = [2]uuid.UUID{edge.To, edge.From}
+		}
+		if connected[key] || !inTree[edge.From] || !inTree[edge.To] || edge.From == edge.To {
+			return
+		}
+		connected[key] = true
+		genogram.Edges = append(genogram.Edges, edge)
+	}
+
+	for _, link := range links {
+		add(GenogramEdge{From: link.FromID, To: link.ToID, Type: link.Type})
+	}
+
+	for _, member := range members {
+		switch member.Relationship {
+		case model.RelationshipFather, model.RelationshipMother:
+			add(GenogramEdge{From: member.ID, To: patient.ID, Type: GenogramEdgeParent})
+		case model.RelationshipChild:
+			add(GenogramEdge{From: patient.ID, To: member.ID, Type: GenogramEdgeParent})
+		case model.RelationshipSpouse:
+			add(GenogramEdge{From: patient.ID, To: member.ID, Type: GenogramEdgeSpouse})
+		case model.RelationshipSibling:
+			add(GenogramEdge{From: patient.ID, To: member.ID, Type: GenogramEdgeSibling})
+		default:
+			add(GenogramEdge{From: member.ID, To: patient.ID, Type: GenogramEdgeRelationship, Label: member.Relationship})
+		}
+	}
+
+	return genogram
+}
+
+// Connected reports whether an edge already links the two nodes, in either direction
+func (g Genogram) Connected(a, b uuid.UUID) bool {
+	for _, edge := range g.Edges {
+		if (edge.From == a && edge.To == b) || (edge.From == b && edge.To == a) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAncestor reports whether a chain of parent edges leads from the ancestor down to the person
+func (g Genogram) IsAncestor(ancestor, person uuid.UUID) bool {
+	visited := map[uuid.UUID]bool{person: true}
+	queue := []uuid.UUID{person}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.Edges {
+			if edge.Type != GenogramEdgeParent || edge.To != current || visited[edge.From] {
+				continue
+			}
+			if edge.From == ancestor {
+				return true
+			}
+			visited[edge.From] = true
+			queue = append(queue, edge.From)
+		}
+	}
+	return false
+}
+
+// GenogramGender maps a free-text gender to a genogram gender, falling back to the one
+// implied by the relationship to the patient
+func GenogramGender(gender *string, relationship string) string {
+	if gender != nil {
+		switch NormalizeName(*gender) {
+		case "m", "male", "man", "masculino", "homem":
+			return GenogramGenderMale
+		case "f", "female", "woman", "feminino", "mulher":
+			return GenogramGenderFemale
+		}
+	}
+
+	switch relationship {
+	case model.RelationshipFather:
+		return GenogramGenderMale
+	case model.RelationshipMother:
+		return GenogramGenderFemale
+	}
+	return GenogramGenderUnknown
+}
+
+// gedcomFamily is a FAM record: up to two partners and their children
+type gedcomFamily struct {
+	partners []int
+	children []int
+}
+
+// GEDCOM renders the genogram as a GEDCOM 5.5.1 file. Parents and spouses are grouped in
+// FAM records; siblings without known parents share a FAM record without partners. Only
+// the first two parents of a person are exported, and other relationships to the patient,
+// which GEDCOM cannot express, are left out.
+func (g Genogram) GEDCOM() string {
+	index := make(map[uuid.UUID]int, len(g.Nodes))
+	for i, node := range g.Nodes {
+		index[node.ID] = i
+	}
+
+	parents := make(map[int][]int)
+	for _, edge := range g.Edges {
+		if edge.Type == GenogramEdgeParent {
+			child := index[edge.To]
+			parents[child] = append(parents[child], index[edge.From])
+		}
+	}
+
+	var families []*gedcomFamily
+	byPartners := make(map[[2]int]*gedcomFamily)
+	family := func(partners []int) *gedcomFamily {
+		sort.Ints(partners)
+		key := [2]int{-1, -1}
+		copy(key[:], partners)
+		if existing, ok := byPartners[key]; ok {
+			return existing
+		}
+		f := &gedcomFamily{partners: partners}
+		byPartners[key] = f
+		families = append(families, f)
+		return f
+	}
+
+	childOf := make(map[int]*gedcomFamily)
+	for i := range g.Nodes {
+		if ps := parents[i]; len(ps) > 0 {
+			f := family(append([]int(nil), ps[:min(len(ps), 2)]...))
+			f.children = append(f.children, i)
+			childOf[i] = f
+		}
+	}
+
+	for _, edge := range g.Edges {
+		a, b := index[edge.From], index[edge.To]
+		switch edge.Type {
+		case GenogramEdgeSpouse:
+			family([]int{a, b})
+		case GenogramEdgeSibling:
+			switch {
+			case childOf[a] != nil && childOf[b] == nil:
+				childOf[a].children = append(childOf[a].children, b)
+				childOf[b] = childOf[a]
+			case childOf[b] != nil && childOf[a] == nil:
+				childOf[b].children = append(childOf[b].children, a)
+				childOf[a] = childOf[b]
+			case childOf[a] == nil && childOf[b] == nil:
+				f := &gedcomFamily{children: []int{a, b}}
+				families = append(families, f)
+				childOf[a], childOf[b] = f, f
+			}
+		}
+	}
+
+	familyIndex := make(map[*gedcomFamily]int, len(families))
+	spouseOf := make(map[int][]int)
+	for i, f := range families {
+		familyIndex[f] = i
+		for _, partner := range f.partners {
+			spouseOf[partner] = append(spouseOf[partner], i)
+		}
+	}
+
+	var b strings.Builder
+	line := func(level int, tag string, value string) {
+		if value == "" {
+			fmt.Fprintf(&b, "%d %s\n", level, tag)
+			return
+		}
+		fmt.Fprintf(&b, "%d %s %s\n", level, tag, value)
+	}
+
+	line(0, "HEAD", "")
+	line(1, "SOUR", "PSYGROW")
+	line(1, "GEDC", "")
+	line(2, "VERS", "5.5.1")
+	line(2, "FORM", "LINEAGE-LINKED")
+	line(1, "CHAR", "UTF-8")
+
+	for i, node := range g.Nodes {
+		line(0, gedcomPointer("I", i), "INDI")
+		line(1, "NAME", gedcomName(node.Name))
+		line(1, "SEX", gedcomSex(node.Gender))
+		if node.BirthDate != nil {
+			line(1, "BIRT", "")
+			line(2, "DATE", gedcomDate(*node.BirthDate))
+		}
+		if node.DeathDate != nil {
+			line(1, "DEAT", "")
+			line(2, "DATE", gedcomDate(*node.DeathDate))
+		} else if node.IsDeceased {
+			line(1, "DEAT", "Y")
+		}
+		if node.Notes != nil && *node.Notes != "" {
+			for j, text := range strings.Split(gedcomText(*node.Notes), "\n") {
+				if j == 0 {
+					line(1, "NOTE", text)
+				} else {
+					line(2, "CONT", text)
+				}
+			}
+		}
+		if f := childOf[i]; f != nil {
+			line(1, "FAMC", gedcomPointer("F", familyIndex[f]))
+		}
+		for _, j := range spouseOf[i] {
+			line(1, "FAMS", gedcomPointer("F", j))
+		}
+	}
+
+	for i, f := range families {
+		line(0, gedcomPointer("F", i), "FAM")
+		husband, wife := gedcomPartners(g.Nodes, f.partners)
+		if husband >= 0 {
+			line(1, "HUSB", gedcomPointer("I", husband))
+		}
+		if wife >= 0 {
+			line(1, "WIFE", gedcomPointer("I", wife))
+		}
+		for _, child := range f.children {
+			line(1, "CHIL", gedcomPointer("I", child))
+		}
+	}
+
+	line(0, "TRLR", "")
+	return b.String()
+}
+
+// gedcomPartners assigns the partners of a family to the HUSB and WIFE roles by gender,
+// keeping their order when it does not tell them apart. Missing roles are -1.
+func gedcomPartners(nodes []GenogramNode, partners []int) (int, int) {
+	husband, wife := -1, -1
+	switch len(partners) {
+	case 1:
+		if nodes[partners[0]].Gender == GenogramGenderFemale {
+			wife = partners[0]
+		} else {
+			husband = partners[0]
+		}
+	case 2:
+		husband, wife = partners[0], partners[1]
+		if nodes[husband].Gender == GenogramGenderFemale || nodes[wife].Gender == GenogramGenderMale {
+			husband, wife = wife, husband
+		}
+	}
+	return husband, wife
+}
+
+func gedcomSex(gender string) string {
+	switch gender {
+	case GenogramGenderMale:
+		return "M"
+	case GenogramGenderFemale:
+		return "F"
+	}
+	return "U"
+}
+
+func gedcomPointer(prefix string, i int) string {
+	return fmt.Sprintf("@%s%d@", prefix, i+1)
+}
+
+// gedcomName writes a name with its last word as the surname, as in "Maria da /Silva/"
+func gedcomName(name string) string {
+	words := strings.Fields(strings.ReplaceAll(gedcomText(name), "/", " "))
+	if len(words) < 2 {
+		return strings.Join(words, " ")
+	}
+	return strings.Join(words[:len(words)-1], " ") + " /" + words[len(words)-1] + "/"
+}
+
+func gedcomDate(date time.Time) string {
+	return strings.ToUpper(date.Format("2 Jan 2006"))
+}
+
+// gedcomText escapes the at signs, which start pointers in GEDCOM
+func gedcomText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "@", "@@")
+}
@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildGenogram(t *testing.T) {
+	patient := model.Patient{ID: uuid.New(), FullName: "Ana Souza", BirthDate: time.Date(2012, 3, 4, 0, 0, 0, 0, time.UTC), Gender: strPtr("Feminino")}
+	father := model.PatientFamily{ID: uuid.New(), PatientID: patient.ID, Relationship: model.RelationshipFather, Name: "Carlos Souza", IsDeceased: true}
+	mother := model.PatientFamily{ID: uuid.New(), PatientID: patient.ID, Relationship: model.RelationshipMother, Name: "Beatriz Lima", Notes: strPtr("Contato @ escola")}
+	brother := model.PatientFamily{ID: uuid.New(), PatientID: patient.ID, Relationship: model.RelationshipSibling, Name: "Pedro Souza"}
+	grandmother := model.PatientFamily{ID: uuid.New(), PatientID: patient.ID, Relationship: model.RelationshipGrandparent, Name: "Rosa Lima", Gender: strPtr("f")}
+
+	links := []model.PatientFamilyLink{
+		{FromID: father.ID, ToID: mother.ID, Type: model.FamilyLinkTypeSpouse},
+		{FromID: grandmother.ID, ToID: mother.ID, Type: model.FamilyLinkTypeParent},
+		{FromID: uuid.New(), ToID: mother.ID, Type: model.FamilyLinkTypeParent}, // Removed member
+	}
+
+	genogram := BuildGenogram(patient, []model.PatientFamily{father, mother, brother, grandmother}, links)
+
+	assert.Len(t, genogram.Nodes, 5)
+	assert.True(t, genogram.Nodes[0].IsPatient)
+	assert.Equal(t, GenogramGenderFemale, genogram.Nodes[0].Gender)
+	assert.Equal(t, GenogramGenderMale, genogram.Nodes[1].Gender)
+	assert.True(t, genogram.Nodes[1].IsDeceased)
+
+	assert.ElementsMatch(t, []GenogramEdge{
+		{From: father.ID, To: mother.ID, Type: GenogramEdgeSpouse},
+		{From: grandmother.ID, To: mother.ID, Type: GenogramEdgeParent},
+		{From: father.ID, To: patient.ID, Type: GenogramEdgeParent},
+		{From: mother.ID, To: patient.ID, Type: GenogramEdgeParent},
+		{From: patient.ID, To: brother.ID, Type: GenogramEdgeSibling},
+		// The grandparent is already linked to the tree, but not to the patient
+		{From: grandmother.ID, To: patient.ID, Type: GenogramEdgeRelationship, Label: model.RelationshipGrandparent},
+	}, genogram.Edges)
+
+	assert.True(t, genogram.Connected(mother.ID, father.ID))
+	assert.False(t, genogram.Connected(father.ID, brother.ID))
+	assert.True(t, genogram.IsAncestor(grandmother.ID, patient.ID))
+	assert.False(t, genogram.IsAncestor(patient.ID, grandmother.ID))
+
+	gedcom := genogram.GEDCOM()
+	assert.True(t, strings.HasPrefix(gedcom, "0 HEAD\n"))
+	assert.True(t, strings.HasSuffix(gedcom, "0 TRLR\n"))
+	assert.Contains(t, gedcom, "0 @I1@ INDI\n1 NAME Ana /Souza/\n1 SEX F\n1 BIRT\n2 DATE 4 MAR 2012\n")
+	assert.Contains(t, gedcom, "1 DEAT Y\n")
+	assert.Contains(t, gedcom, "1 NOTE Contato @@ escola\n")
+	// Parents of the patient, with the brother as a sibling, and the grandmother's family
+	assert.Contains(t, gedcom, "0 @F1@ FAM\n1 HUSB @I2@\n1 WIFE @I3@\n1 CHIL @I1@\n1 CHIL @I4@\n")
+	assert.Contains(t, gedcom, "0 @F2@ FAM\n1 WIFE @I5@\n1 CHIL @I3@\n")
+	assert.Equal(t, 2, strings.Count(gedcom, " FAM\n"))
+}
@@ -0,0 +1,124 @@
+package helper
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"sort"
+)
+
+// Reasons why two patients are considered duplicates
+const (
+	DuplicateReasonDocument      = "document"
+	DuplicateReasonEmail         = "email"
+	DuplicateReasonPhone         = "phone"
+	DuplicateReasonNameBirthDate = "name_birth_date"
+)
+
+// DuplicateNameThreshold is the minimum name similarity for patients born on the same day to be flagged
+const DuplicateNameThreshold = 0.85
+
+// PatientDuplicate is a pair of patients that probably represent the same person.
+// PatientID is always the oldest record of the pair.
+type PatientDuplicate struct {
+	PatientID   uuid.UUID
+	DuplicateID uuid.UUID
+	Reasons     []string
+	Score       float64 // Name similarity of the pair, from 0 to 1
+}
+
+// DocumentDigits keeps only the digits of a document such as a CPF for comparison
+func DocumentDigits(document string) string {
+	return PhoneDigits(document)
+}
+
+// NormalizePhone keeps the digits of a phone and drops the Brazilian country code
+// so that "+55 (11) 99999-0000" and "11999990000" compare as equal
+func NormalizePhone(phone string) string {
+	digits := PhoneDigits(phone)
+	if len(digits) > 11 && digits[:2] == "55" {
+		digits = digits[2:]
+	}
+	return digits
+}
+
+// FindDuplicatePatients compares the given patients and returns the pairs sharing a
+// document, email or phone, or having similar names and the same birth date
+func FindDuplicatePatients(patients []model.Patient) []PatientDuplicate {
+	sorted := make([]model.Patient, len(patients))
+	copy(sorted, patients)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	type pairKey struct{ a, b int }
+	pairs := make(map[pairKey]*PatientDuplicate)
+	var order []pairKey
+
+	flag := func(i, j int, reason string) {
+		key := pairKey{i, j}
+		pair, ok := pairs[key]
+		if !ok {
+			pair = &PatientDuplicate{
+				PatientID:   sorted[i].ID,
+				DuplicateID: sorted[j].ID,
+				Score:       NameSimilarity(sorted[i].FullName, sorted[j].FullName),
+			}
+			pairs[key] = pair
+			order = append(order, key)
+		}
+		for _, r := range pair.Reasons {
+			if r == reason {
+				return
+			}
+		}
+		pair.Reasons = append(pair.Reasons, reason)
+	}
+
+	// Exact matches on normalized contact data
+	matchers := []struct {
+		reason string
+		key    func(p model.Patient) string
+	}{
+		{DuplicateReasonDocument, func(p model.Patient) string { return DocumentDigits(valueOf(p.Document)) }},
+		{DuplicateReasonEmail, func(p model.Patient) string { return NormalizeEmail(valueOf(p.Email)) }},
+		{DuplicateReasonPhone, func(p model.Patient) string { return NormalizePhone(valueOf(p.Phone)) }},
+	}
+	for _, m := range matchers {
+		seen := make(map[string][]int)
+		for i, p := range sorted {
+			key := m.key(p)
+			if key == "" {
+				continue
+			}
+			for _, j := range seen[key] {
+				flag(j, i, m.reason)
+			}
+			seen[key] = append(seen[key], i)
+		}
+	}
+
+	// Fuzzy name match among patients born on the same day
+	byBirthDate := make(map[string][]int)
+	for i, p := range sorted {
+		key := p.BirthDate.Format("2006-01-02")
+		for _, j := range byBirthDate[key] {
+			if NameSimilarity(sorted[j].FullName, p.FullName) >= DuplicateNameThreshold {
+				flag(j, i, DuplicateReasonNameBirthDate)
+			}
+		}
+		byBirthDate[key] = append(byBirthDate[key], i)
+	}
+
+	duplicates := make([]PatientDuplicate, 0, len(order))
+	for _, key := range order {
+		duplicates = append(duplicates, *pairs[key])
+	}
+	return duplicates
+}
+
+func valueOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,41 @@
+package helper
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestFindDuplicatePatients(t *testing.T) {
+	birth := time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	original := model.Patient{ID: uuid.New(), FullName: "José da Silva", BirthDate: birth, Document: strPtr("123.456.789-09"), Phone: strPtr("+55 (11) 99999-0000"), CreatedAt: now.Add(-time.Hour)}
+	sameDocument := model.Patient{ID: uuid.New(), FullName: "J. Silva", BirthDate: birth.AddDate(1, 0, 0), Document: strPtr("12345678909"), CreatedAt: now}
+	sameNameAndBirth := model.Patient{ID: uuid.New(), FullName: "Jose da  Silva", BirthDate: birth, Phone: strPtr("11999990000"), CreatedAt: now.Add(time.Minute)}
+	unrelated := model.Patient{ID: uuid.New(), FullName: "Maria Souza", BirthDate: birth, Email: strPtr("maria@example.com"), CreatedAt: now}
+
+	duplicates := FindDuplicatePatients([]model.Patient{sameNameAndBirth, unrelated, sameDocument, original})
+
+	assert.Len(t, duplicates, 2)
+
+	byDuplicate := make(map[uuid.UUID]PatientDuplicate)
+	for _, d := range duplicates {
+		assert.Equal(t, original.ID, d.PatientID, "the oldest record must be the surviving candidate")
+		byDuplicate[d.DuplicateID] = d
+	}
+
+	assert.Equal(t, []string{DuplicateReasonDocument}, byDuplicate[sameDocument.ID].Reasons)
+	assert.ElementsMatch(t, []string{DuplicateReasonPhone, DuplicateReasonNameBirthDate}, byDuplicate[sameNameAndBirth.ID].Reasons)
+	assert.Equal(t, 1.0, byDuplicate[sameNameAndBirth.ID].Score)
+}
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "joao conceicao", NormalizeName("  João   Conceição "))
+}
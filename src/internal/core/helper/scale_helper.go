@@ -0,0 +1,144 @@
+package helper
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
+	"github.com/google/uuid"
+	"math"
+	"sort"
+)
+
+var (
+	ErrScaleItemMissing    = errors.New("item não respondido")
+	ErrScaleItemUnknown    = errors.New("item não pertence à escala")
+	ErrScaleItemDuplicated = errors.New("item respondido mais de uma vez")
+	ErrScaleInvalidValue   = errors.New("valor fora das opções da escala")
+)
+
+// ScaleItemAnswer is the option chosen for an item of a scale
+type ScaleItemAnswer struct {
+	ItemID uuid.UUID
+	Value  int
+}
+
+// ScaleResult is the outcome of a scale application
+type ScaleResult struct {
+	Score    float64
+	Severity *string // Nil when no band holds the score
+	Flagged  bool
+	Answers  []model.ScaleAnswer // In item order, without application ID
+}
+
+// ScoreScale checks that every item of the scale is answered once with one of its options and
+// computes the total score. Reverse-scored items count from the highest option down, and mean
+// scores are rounded to two decimals. Errors are keyed by item ID.
+func ScoreScale(scale *model.Scale, answers []ScaleItemAnswer) (ScaleResult, validation.FieldErrors) {
+	errs := validation.FieldErrors{}
+	result := ScaleResult{}
+
+	lowest, highest, ok := scaleOptionRange(scale.Options)
+	if !ok || len(scale.Items) == 0 {
+		errs.Add("scale", ErrScaleItemUnknown)
+		return result, errs
+	}
+	values := make(map[int]bool, len(scale.Options))
+	for _, option := range scale.Options {
+		values[option.Value] = true
+	}
+
+	items := make(map[uuid.UUID]*model.ScaleItem, len(scale.Items))
+	for i := range scale.Items {
+		items[scale.Items[i].ID] = &scale.Items[i]
+	}
+
+	given := make(map[uuid.UUID]int, len(answers))
+	for _, answer := range answers {
+		key := answer.ItemID.String()
+		switch _, known := items[answer.ItemID]; {
+		case !known:
+			errs.Add(key, ErrScaleItemUnknown)
+		case !values[answer.Value]:
+			errs.Add(key, ErrScaleInvalidValue)
+		default:
+			if _, duplicated := given[answer.ItemID]; duplicated {
+				errs.Add(key, ErrScaleItemDuplicated)
+				continue
+			}
+			given[answer.ItemID] = answer.Value
+		}
+	}
+
+	ordered := append([]model.ScaleItem(nil), scale.Items...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].ItemNumber < ordered[j].ItemNumber })
+
+	total := 0
+	for _, item := range ordered {
+		value, answered := given[item.ID]
+		if !answered {
+			if errs[item.ID.String()] == "" {
+				errs.Add(item.ID.String(), ErrScaleItemMissing)
+			}
+			continue
+		}
+
+		score := value
+		if item.ReverseScored {
+			score = lowest + highest - value
+		}
+		critical := item.Critical && value > lowest
+		result.Flagged = result.Flagged || critical
+		total += score
+
+		result.Answers = append(result.Answers, model.ScaleAnswer{
+			ItemID:   item.ID,
+			Value:    value,
+			Score:    score,
+			Critical: critical,
+		})
+	}
+	if len(errs) > 0 {
+		return ScaleResult{}, errs
+	}
+
+	result.Score = float64(total)
+	if scale.Scoring == model.ScaleScoringMean {
+		result.Score = math.Round(float64(total)/float64(len(ordered))*100) / 100
+	}
+	result.Severity = ScaleSeverity(scale, result.Score)
+
+	return result, nil
+}
+
+// ScaleSeverity returns the severity of the band holding the score, or nil when none does
+func ScaleSeverity(scale *model.Scale, score float64) *string {
+	for _, band := range scale.Bands {
+		if score >= band.MinScore && score <= band.MaxScore {
+			severity := band.Severity
+			return &severity
+		}
+	}
+	return nil
+}
+
+// ScaleScoreRange returns the lowest and highest total scores the scale can reach
+func ScaleScoreRange(scale *model.Scale) (float64, float64) {
+	lowest, highest, _ := scaleOptionRange(scale.Options)
+	if scale.Scoring == model.ScaleScoringMean {
+		return float64(lowest), float64(highest)
+	}
+	items := float64(len(scale.Items))
+	return float64(lowest) * items, float64(highest) * items
+}
+
+func scaleOptionRange(options []model.ScaleOption) (int, int, bool) {
+	if len(options) == 0 {
+		return 0, 0, false
+	}
+	lowest, highest := options[0].Value, options[0].Value
+	for _, option := range options[1:] {
+		lowest = min(lowest, option.Value)
+		highest = max(highest, option.Value)
+	}
+	return lowest, highest, true
+}
@@ -0,0 +1,92 @@
+package helper
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testScale(scoring string, items ...model.ScaleItem) *model.Scale {
+	scale := &model.Scale{Scoring: scoring, Items: items}
+	for value, label := range []string{"Nunca", "Às vezes", "Frequentemente", "Sempre"} {
+		scale.Options = append(scale.Options, model.ScaleOption{Value: value, Label: label})
+	}
+	scale.Bands = []model.ScaleBand{
+		{MinScore: 0, MaxScore: 4, Severity: "Leve"},
+		{MinScore: 5, MaxScore: 9, Severity: "Grave"},
+	}
+	return scale
+}
+
+func scaleItem(number int, reverse bool, critical bool) model.ScaleItem {
+	return model.ScaleItem{ID: uuid.New(), ItemNumber: number, ReverseScored: reverse, Critical: critical}
+}
+
+func TestScoreScale(t *testing.T) {
+	first := scaleItem(1, false, false)
+	reversed := scaleItem(2, true, false)
+	critical := scaleItem(3, false, true)
+	scale := testScale(model.ScaleScoringSum, critical, first, reversed)
+
+	result, errs := ScoreScale(scale, []ScaleItemAnswer{
+		{ItemID: reversed.ID, Value: 0},
+		{ItemID: first.ID, Value: 2},
+		{ItemID: critical.ID, Value: 1},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, 6.0, result.Score) // 2 + (3 - 0) + 1
+	assert.Equal(t, "Grave", *result.Severity)
+	assert.True(t, result.Flagged)
+	assert.Equal(t, []model.ScaleAnswer{
+		{ItemID: first.ID, Value: 2, Score: 2},
+		{ItemID: reversed.ID, Value: 0, Score: 3},
+		{ItemID: critical.ID, Value: 1, Score: 1, Critical: true},
+	}, result.Answers)
+
+	result, errs = ScoreScale(scale, []ScaleItemAnswer{
+		{ItemID: first.ID, Value: 0},
+		{ItemID: reversed.ID, Value: 3},
+		{ItemID: critical.ID, Value: 0},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, 0.0, result.Score)
+	assert.Equal(t, "Leve", *result.Severity)
+	assert.False(t, result.Flagged)
+
+	mean := testScale(model.ScaleScoringMean, first, reversed, critical)
+	result, errs = ScoreScale(mean, []ScaleItemAnswer{
+		{ItemID: first.ID, Value: 1},
+		{ItemID: reversed.ID, Value: 3},
+		{ItemID: critical.ID, Value: 1},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, 0.67, result.Score) // (1 + 0 + 1) / 3
+	assert.Equal(t, "Leve", *result.Severity)
+	assert.Nil(t, ScaleSeverity(mean, 4.5)) // Between the bands
+}
+
+func TestScoreScaleErrors(t *testing.T) {
+	first := scaleItem(1, false, false)
+	second := scaleItem(2, false, false)
+	third := scaleItem(3, false, false)
+	scale := testScale(model.ScaleScoringSum, first, second, third)
+	unknown := uuid.New()
+
+	_, errs := ScoreScale(scale, []ScaleItemAnswer{
+		{ItemID: first.ID, Value: 4},
+		{ItemID: second.ID, Value: 1},
+		{ItemID: second.ID, Value: 2},
+		{ItemID: unknown, Value: 1},
+	})
+	assert.Equal(t, map[string]string{
+		first.ID.String():  ErrScaleInvalidValue.Error(),
+		second.ID.String(): ErrScaleItemDuplicated.Error(),
+		third.ID.String():  ErrScaleItemMissing.Error(),
+		unknown.String():   ErrScaleItemUnknown.Error(),
+	}, map[string]string(errs))
+
+	low, high := ScaleScoreRange(scale)
+	assert.Equal(t, 0.0, low)
+	assert.Equal(t, 9.0, high)
+}
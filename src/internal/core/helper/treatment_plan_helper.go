@@ -0,0 +1,103 @@
+package helper
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"math"
+	"sort"
+	"time"
+)
+
+// GoalProgressPoint is a progress rating of a goal
+type GoalProgressPoint struct {
+	EvolutionID uuid.UUID
+	RatedAt     time.Time
+	Rating      int
+}
+
+// GoalProgressSummary aggregates the progress ratings of a goal across every version of its plan
+type GoalProgressSummary struct {
+	Goal        model.TreatmentGoal
+	FirstRating *int
+	LastRating  *int
+	Average     *float64
+	Change      *int // Last rating minus the first one
+	Overdue     bool // Target date passed without the goal being achieved or discontinued
+	Points      []GoalProgressPoint
+}
+
+// MonthlyProgress is the average of the progress ratings of every goal of a plan in a month
+type MonthlyProgress struct {
+	Month   string // AAAA-MM
+	Ratings int
+	Average float64
+}
+
+// SummarizeGoalProgress summarizes the progress of each goal, matching ratings given against
+// earlier versions of the goal by its key. Points are in the order they were rated.
+func SummarizeGoalProgress(goals []model.TreatmentGoal, progress []model.TreatmentGoalProgress, now time.Time) []GoalProgressSummary {
+	byKey := make(map[uuid.UUID][]model.TreatmentGoalProgress, len(goals))
+	for _, rating := range sortedProgress(progress) {
+		byKey[rating.GoalKey] = append(byKey[rating.GoalKey], rating)
+	}
+
+	summaries := make([]GoalProgressSummary, len(goals))
+	for i, goal := range goals {
+		summary := GoalProgressSummary{Goal: goal, Points: []GoalProgressPoint{}}
+
+		ratings := byKey[goal.GoalKey]
+		total := 0
+		for _, rating := range ratings {
+			summary.Points = append(summary.Points, GoalProgressPoint{
+				EvolutionID: rating.EvolutionID,
+				RatedAt:     rating.RatedAt,
+				Rating:      rating.Rating,
+			})
+			total += rating.Rating
+		}
+		if len(ratings) > 0 {
+			first, last := ratings[0].Rating, ratings[len(ratings)-1].Rating
+			average := roundHundredths(float64(total) / float64(len(ratings)))
+			change := last - first
+			summary.FirstRating, summary.LastRating, summary.Average, summary.Change = &first, &last, &average, &change
+		}
+
+		closed := goal.Status == model.TreatmentGoalStatusAchieved || goal.Status == model.TreatmentGoalStatusDiscontinued
+		summary.Overdue = !closed && goal.TargetDate != nil &&
+			goal.TargetDate.Format("2006-01-02") < now.Format("2006-01-02")
+
+		summaries[i] = summary
+	}
+	return summaries
+}
+
+// MonthlyGoalProgress averages the progress ratings of a plan by month, oldest month first
+func MonthlyGoalProgress(progress []model.TreatmentGoalProgress) []MonthlyProgress {
+	months := []MonthlyProgress{}
+	totals := []int{}
+	for _, rating := range sortedProgress(progress) {
+		month := rating.RatedAt.Format("2006-01")
+		if len(months) == 0 || months[len(months)-1].Month != month {
+			months = append(months, MonthlyProgress{Month: month})
+			totals = append(totals, 0)
+		}
+		last := len(months) - 1
+		months[last].Ratings++
+		totals[last] += rating.Rating
+	}
+
+	for i := range months {
+		months[i].Average = roundHundredths(float64(totals[i]) / float64(months[i].Ratings))
+	}
+	return months
+}
+
+func sortedProgress(progress []model.TreatmentGoalProgress) []model.TreatmentGoalProgress {
+	sorted := append([]model.TreatmentGoalProgress(nil), progress...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].RatedAt.Before(sorted[j].RatedAt) })
+	return sorted
+}
+
+func roundHundredths(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,62 @@
+package helper
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSummarizeGoalProgress(t *testing.T) {
+	now := time.Date(2026, 5, 10, 15, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	sleep := model.TreatmentGoal{ID: uuid.New(), GoalKey: uuid.New(), Status: model.TreatmentGoalStatusInProgress, TargetDate: &yesterday}
+	anxiety := model.TreatmentGoal{ID: uuid.New(), GoalKey: uuid.New(), Status: model.TreatmentGoalStatusAchieved, TargetDate: &yesterday}
+	today := now.Add(-time.Hour * 10)
+	social := model.TreatmentGoal{ID: uuid.New(), GoalKey: uuid.New(), Status: model.TreatmentGoalStatusPending, TargetDate: &today}
+
+	rate := func(goal model.TreatmentGoal, at time.Time, rating int) model.TreatmentGoalProgress {
+		// Ratings given against an earlier version keep the key but not the ID
+		return model.TreatmentGoalProgress{EvolutionID: uuid.New(), GoalID: uuid.New(), GoalKey: goal.GoalKey, RatedAt: at, Rating: rating}
+	}
+	march := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+	april := time.Date(2026, 4, 2, 10, 0, 0, 0, time.UTC)
+	progress := []model.TreatmentGoalProgress{
+		rate(sleep, april, 6),
+		rate(anxiety, march, 3),
+		rate(sleep, march, 2),
+		rate(anxiety, april.AddDate(0, 0, 7), 9),
+		rate(sleep, march.AddDate(0, 0, 7), 3),
+	}
+
+	summaries := SummarizeGoalProgress([]model.TreatmentGoal{sleep, anxiety, social}, progress, now)
+	assert.Len(t, summaries, 3)
+
+	assert.Equal(t, []int{2, 3, 6}, pointRatings(summaries[0].Points))
+	assert.Equal(t, 2, *summaries[0].FirstRating)
+	assert.Equal(t, 6, *summaries[0].LastRating)
+	assert.Equal(t, 3.67, *summaries[0].Average)
+	assert.Equal(t, 4, *summaries[0].Change)
+	assert.True(t, summaries[0].Overdue)
+
+	assert.Equal(t, 6, *summaries[1].Change)
+	assert.False(t, summaries[1].Overdue) // Achieved
+
+	assert.Empty(t, summaries[2].Points)
+	assert.Nil(t, summaries[2].Average)
+	assert.False(t, summaries[2].Overdue) // Due today
+
+	assert.Equal(t, []MonthlyProgress{
+		{Month: "2026-03", Ratings: 3, Average: 2.67},
+		{Month: "2026-04", Ratings: 2, Average: 7.5},
+	}, MonthlyGoalProgress(progress))
+}
+
+func pointRatings(points []GoalProgressPoint) []int {
+	ratings := make([]int, len(points))
+	for i, point := range points {
+		ratings[i] = point.Rating
+	}
+	return ratings
+}
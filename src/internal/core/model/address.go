@@ -0,0 +1,29 @@
+package model
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
+	"strings"
+)
+
+// Address is a structured Brazilian postal address, embedded with the "address_" column prefix
+type Address struct {
+	CEP        *string `gorm:"type:varchar(9)" validate:"omitempty,cep"`
+	Street     *string `gorm:"type:varchar(150)" validate:"omitempty,max=150"`
+	Number     *string `gorm:"type:varchar(20)" validate:"omitempty,max=20"`
+	Complement *string `gorm:"type:varchar(100)" validate:"omitempty,max=100"`
+	City       *string `gorm:"type:varchar(100)" validate:"omitempty,max=100"`
+	State      *string `gorm:"type:varchar(2)" validate:"omitempty,uf"`
+}
+
+// Normalize formats the CEP and the UF of the address and trims the other fields.
+// Invalid values are recorded in errs under the given JSON prefix.
+func (a *Address) Normalize(prefix string, errs validation.FieldErrors) {
+	errs.NormalizeOptional(prefix+".cep", &a.CEP, validation.NormalizeCEP)
+	errs.NormalizeOptional(prefix+".state", &a.State, validation.NormalizeUF)
+
+	trim := func(s string) (string, error) { return strings.TrimSpace(s), nil }
+	errs.NormalizeOptional(prefix+".street", &a.Street, trim)
+	errs.NormalizeOptional(prefix+".number", &a.Number, trim)
+	errs.NormalizeOptional(prefix+".complement", &a.Complement, trim)
+	errs.NormalizeOptional(prefix+".city", &a.City, trim)
+}
@@ -7,9 +7,10 @@ import (
 
 // AnamneseTemplate represents a template for anamnesis configured by each client
 type AnamneseTemplate struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	Title     string    `gorm:"type:varchar(100);not null"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Title      string    `gorm:"type:varchar(100);not null"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null"`           // Nil for the built-in templates
+	BuiltInKey *string   `gorm:"type:varchar(50);uniqueIndex"` // Set on the starter templates shipped with the API
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
 }
@@ -7,15 +7,20 @@ import (
 
 // AnamneseField represents custom fields that make up the anamnesis template
 type AnamneseField struct {
-	ID            uuid.UUID             `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	FieldNumber   int                   `gorm:"not null"`
-	FieldType     string                `gorm:"type:varchar(50);not null"` // date, datetime, text, number, checkbox, select, multiselect
-	FieldTitle    string                `gorm:"type:varchar(255);not null"`
-	FieldRequired bool                  `gorm:"default:false"`
-	FieldActive   bool                  `gorm:"default:true"`
-	UserID        uuid.UUID             `gorm:"type:uuid;not null"`
-	AnamneseID    uuid.UUID             `gorm:"type:uuid;not null"`
-	Options       []AnamneseFieldOption `gorm:"foreignKey:AnamneseFieldID"`
-	CreatedAt     time.Time             `gorm:"autoCreateTime"`
-	UpdatedAt     time.Time             `gorm:"autoUpdateTime"`
+	ID                uuid.UUID             `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	FieldNumber       int                   `gorm:"not null"`
+	FieldType         string                `gorm:"type:varchar(50);not null"` // date, datetime, text, number, checkbox, select, multiselect
+	FieldTitle        string                `gorm:"type:varchar(255);not null"`
+	FieldRequired     bool                  `gorm:"default:false"`
+	FieldActive       bool                  `gorm:"default:true"`
+	UserID            uuid.UUID             `gorm:"type:uuid;not null"`
+	AnamneseID        uuid.UUID             `gorm:"type:uuid;not null"`
+	VersionID         uuid.UUID             `gorm:"type:uuid;index"` // Template version the field belongs to
+	FieldKey          uuid.UUID             `gorm:"type:uuid;index"` // Same on the copies of the field across versions
+	VisibleIfFieldKey *uuid.UUID            `gorm:"type:uuid"`       // Shown only when the answer to the field with this key matches the rule
+	VisibleIfOperator *string               `gorm:"type:varchar(20)"`
+	VisibleIfValue    *string               `gorm:"type:varchar(255)"`
+	Options           []AnamneseFieldOption `gorm:"foreignKey:AnamneseFieldID"`
+	CreatedAt         time.Time             `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time             `gorm:"autoUpdateTime"`
 }
@@ -10,7 +10,8 @@ type AnamneseFieldOption struct {
 	ID              uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
 	AnamneseFieldID uuid.UUID `gorm:"type:uuid;not null"` // Foreign key to AnamneseField
 	OptionValue     string    `gorm:"type:varchar(255);not null"`
-	OptionOrder     int       `gorm:"not null"` // To maintain the order of options
+	OptionOrder     int       `gorm:"not null"`  // To maintain the order of options
+	OptionKey       uuid.UUID `gorm:"type:uuid"` // Same on the copies of the option across versions
 	CreatedAt       time.Time `gorm:"autoCreateTime"`
 	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
 }
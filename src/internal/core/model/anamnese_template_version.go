@@ -0,0 +1,25 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// AnamneseTemplateVersion is a version of the fields of an anamnese template. Only the draft
+// version is edited; publishing it freezes its fields and options, and patient anamneses are
+// always answered against a published version.
+type AnamneseTemplateVersion struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	TemplateID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_anamnese_template_version"`
+	Version     int       `gorm:"not null;uniqueIndex:idx_anamnese_template_version"`
+	Status      string    `gorm:"type:varchar(20);not null"` // Use constants from model.AnamneseVersionStatus*
+	PublishedAt *time.Time
+	Fields      []AnamneseField `gorm:"foreignKey:VersionID"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime"`
+}
+
+// IsDraft reports whether the version can still be edited
+func (v *AnamneseTemplateVersion) IsDraft() bool {
+	return v.Status == AnamneseVersionStatusDraft
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// FinancialReadRoles are the roles allowed to read financial data, with a JWT or an API key
+var FinancialReadRoles = []string{UserRoleAdmin, UserRoleProfessional}
+
+// apiKeyPermissionRoles restricts permissions to the roles allowed to act on the same data; unlisted permissions are granted to every role
+var apiKeyPermissionRoles = map[string][]string{
+	APIKeyPermissionFinancialRead: FinancialReadRoles,
+}
+
+// RoleGrantsAPIKeyPermission reports whether a user with the role may hold an API key with the permission
+func RoleGrantsAPIKeyPermission(role string, permission string) bool {
+	roles, restricted := apiKeyPermissionRoles[permission]
+	if !restricted {
+		return true
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey authenticates server-to-server integrations on behalf of its owner.
+// Only the SHA-256 hash of the key is stored; the prefix identifies the key in listings and lookups.
+type APIKey struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index" validate:"required"`
+	Name        string    `gorm:"type:varchar(100);not null" validate:"required,min=2,max=100"`
+	Prefix      string    `gorm:"type:varchar(16);not null;uniqueIndex" validate:"required"`
+	KeyHash     string    `gorm:"type:varchar(64);not null" validate:"required"`
+	Permissions string    `gorm:"type:varchar(255);not null" validate:"required"` // Space-separated, e.g. "leads:create financial:read"
+	LastUsedAt  *time.Time
+	ExpiresAt   *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (k *APIKey) Validate() error {
+	validate := validator.New()
+	return validate.Struct(k)
+}
+
+// PermissionList returns the permissions granted to the key
+func (k *APIKey) PermissionList() []string {
+	return strings.Fields(k.Permissions)
+}
+
+// PermissionListForRole returns the permissions granted to the key that the owner's current role still allows
+func (k *APIKey) PermissionListForRole(role string) []string {
+	permissions := []string{}
+	for _, p := range k.PermissionList() {
+		if RoleGrantsAPIKeyPermission(role, p) {
+			permissions = append(permissions, p)
+		}
+	}
+	return permissions
+}
+
+// HasPermission reports whether the key was granted the permission
+func (k *APIKey) HasPermission(permission string) bool {
+	for _, p := range k.PermissionList() {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUsable reports whether the key is neither revoked nor expired at the given time
+func (k *APIKey) IsUsable(at time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || k.ExpiresAt.After(at)
+}
@@ -11,6 +11,7 @@ type Appointment struct {
 	ID                 uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
 	UserID             uuid.UUID  `gorm:"type:uuid;not null;index" validate:"required"`
 	PatientID          uuid.UUID  `gorm:"type:uuid;not null;index" validate:"required"`
+	EpisodeID          *uuid.UUID `gorm:"type:uuid;index"`                                           // Episode of care of the patient the appointment belongs to
 	CustomRepasseType  *string    `gorm:"type:varchar(20)" validate:"omitempty,oneof=percent fixed"` // Use constants from model package
 	CustomRepasseValue *int64     `gorm:"type:bigint"`                                               // Stored as cents or basis points (for percent)
 	ProfessionalID     uuid.UUID  `gorm:"type:uuid;not null;index" validate:"required"`
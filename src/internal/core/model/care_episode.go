@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// CareEpisode is a period during which a patient is under care, from the first appointment to
+// the discharge, referral or dropout. A patient who returns later starts a new episode.
+type CareEpisode struct {
+	ID               uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index"`
+	PatientID        uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Number           int        `gorm:"not null"` // Sequence of the episode among the ones of the patient
+	StartDate        time.Time  `gorm:"not null"`
+	EndDate          *time.Time // Empty while the episode is open
+	EndReason        *string    `gorm:"type:varchar(20)"`  // Use constants from model.CareEpisodeEndReason*
+	ReferredTo       *string    `gorm:"type:varchar(150)"` // Professional or service the patient was referred to
+	DischargeSummary *string    `gorm:"type:text"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime"`
+}
+
+// IsOpen reports whether the patient is still under care in the episode
+func (e *CareEpisode) IsOpen() bool {
+	return e.EndDate == nil
+}
@@ -43,6 +43,23 @@ const (
 	LeadStatusLost       = "lost"
 )
 
+// LeadActivityType defines the lead activity type constants
+const (
+	LeadActivityTypeCall    = "call"
+	LeadActivityTypeMessage = "message"
+	LeadActivityTypeEmail   = "email"
+	LeadActivityTypeMeeting = "meeting"
+)
+
+// LeadActivityOutcome defines the lead activity outcome constants
+const (
+	LeadActivityOutcomeReached       = "reached"
+	LeadActivityOutcomeNoAnswer      = "no_answer"
+	LeadActivityOutcomeLeftMessage   = "left_message"
+	LeadActivityOutcomeScheduled     = "scheduled"
+	LeadActivityOutcomeNotInterested = "not_interested"
+)
+
 // PatientFamilyRelationship defines the relationship constants
 const (
 	RelationshipFather      = "father"
@@ -54,3 +71,110 @@ const (
 	RelationshipSibling     = "sibling"
 	RelationshipOther       = "other"
 )
+
+// PatientFamilyLinkType defines the link type constants. A parent link goes from the parent to the child.
+const (
+	FamilyLinkTypeParent = "parent"
+	FamilyLinkTypeSpouse = "spouse"
+)
+
+// PatientConsentType defines the consent type constants
+const (
+	ConsentTypeTreatment      = "treatment"
+	ConsentTypeDataProcessing = "data_processing"
+	ConsentTypeRecording      = "recording"
+)
+
+// AgeOfMajority is the age from which a patient consents for themselves
+const AgeOfMajority = 18
+
+// AnamneseVersionStatus defines the anamnese template version status constants
+const (
+	AnamneseVersionStatusDraft     = "draft"
+	AnamneseVersionStatusPublished = "published"
+)
+
+// PatientAnamneseStatus defines the patient anamnese status constants. Finalized anamneses are locked.
+const (
+	PatientAnamneseStatusDraft     = "draft"
+	PatientAnamneseStatusFinalized = "finalized"
+)
+
+// PatientAnamneseLinkAction defines the actions logged on self-service anamnese links
+const (
+	PatientAnamneseLinkActionView     = "view"
+	PatientAnamneseLinkActionSubmit   = "submit"
+	PatientAnamneseLinkActionRejected = "rejected" // Expired, used or revoked link
+)
+
+// AnamneseFieldType defines the anamnese field type constants
+const (
+	AnamneseFieldTypeDate        = "date"
+	AnamneseFieldTypeDatetime    = "datetime"
+	AnamneseFieldTypeText        = "text"
+	AnamneseFieldTypeNumber      = "number"
+	AnamneseFieldTypeCheckbox    = "checkbox"
+	AnamneseFieldTypeSelect      = "select"
+	AnamneseFieldTypeMultiselect = "multiselect"
+)
+
+// AnamneseRuleOperator defines the operators of the anamnese field visibility rules
+const (
+	AnamneseRuleOperatorEquals      = "equals"
+	AnamneseRuleOperatorNotEquals   = "not_equals"
+	AnamneseRuleOperatorContains    = "contains"
+	AnamneseRuleOperatorAnswered    = "answered"
+	AnamneseRuleOperatorNotAnswered = "not_answered"
+)
+
+// ScaleScoring defines how the item scores of a psychometric scale make up its total score
+const (
+	ScaleScoringSum  = "sum"
+	ScaleScoringMean = "mean"
+)
+
+// TreatmentPlanStatus defines the treatment plan status constants. A patient has at most one active plan.
+const (
+	TreatmentPlanStatusActive       = "active"
+	TreatmentPlanStatusCompleted    = "completed"
+	TreatmentPlanStatusDiscontinued = "discontinued"
+)
+
+// TreatmentGoalStatus defines the treatment goal status constants
+const (
+	TreatmentGoalStatusPending      = "pending"
+	TreatmentGoalStatusInProgress   = "in_progress"
+	TreatmentGoalStatusAchieved     = "achieved"
+	TreatmentGoalStatusDiscontinued = "discontinued"
+)
+
+// UserRole defines the user role constants
+const (
+	UserRoleAdmin        = "admin"
+	UserRoleProfessional = "professional"
+	UserRoleSecretary    = "secretary"
+	UserRoleViewer       = "viewer"
+)
+
+// LoginAttemptReason defines why a login attempt failed
+const (
+	LoginAttemptReasonInvalidCredentials = "invalid_credentials"
+	LoginAttemptReasonInactiveAccount    = "inactive_account"
+	LoginAttemptReasonInvalidTwoFactor   = "invalid_two_factor"
+	LoginAttemptReasonThrottled          = "throttled"
+)
+
+// APIKeyPermission defines the permissions that can be granted to an API key
+const (
+	APIKeyPermissionLeadsCreate   = "leads:create"
+	APIKeyPermissionLeadsRead     = "leads:read"
+	APIKeyPermissionFinancialRead = "financial:read"
+)
+
+// CareEpisodeEndReason defines why an episode of care ended
+const (
+	CareEpisodeEndReasonDischarge = "discharge"
+	CareEpisodeEndReasonReferral  = "referral"
+	CareEpisodeEndReasonDropout   = "dropout"
+	CareEpisodeEndReasonOther     = "other" // Patient deactivated without a reason
+)
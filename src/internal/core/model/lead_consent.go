@@ -0,0 +1,18 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// LeadConsent records the consent given by a person when submitting the public contact form
+type LeadConsent struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	LeadID         uuid.UUID `gorm:"type:uuid;not null;index"`
+	DataProcessing bool      `gorm:"not null"` // Agreement to the processing of the submitted data
+	Contact        bool      `gorm:"not null"` // Agreement to be contacted by the clinic
+	PolicyVersion  *string   `gorm:"type:varchar(50)"`
+	IPAddress      string    `gorm:"type:varchar(45);not null"`
+	UserAgent      *string   `gorm:"type:varchar(255)"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"time"
+)
+
+// DefaultLeadStages are created with every user, in pipeline order
+var DefaultLeadStages = []struct {
+	Name   string
+	Status string
+}{
+	{"Novo", LeadStatusNew},
+	{"Primeiro contato", LeadStatusInAnalysis},
+	{"Triagem agendada", LeadStatusInAnalysis},
+	{"Convertido", LeadStatusConverted},
+	{"Perdido", LeadStatusLost},
+}
+
+// DefaultLeadLostReasons are created with every user
+var DefaultLeadLostReasons = []string{
+	"Valor da sessão",
+	"Horário incompatível",
+	"Sem retorno do contato",
+	"Optou por outro profissional",
+	"Demanda fora do perfil de atendimento",
+}
+
+// LeadStage is a step of a user's lead pipeline. Each stage maps to one of the base lead statuses,
+// so reports and conversion keep working regardless of how the pipeline is customized.
+// Stage names are unique per user.
+type LeadStage struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" validate:"required"`
+	Name      string    `gorm:"type:varchar(50);not null" validate:"required,min=2,max=50"`
+	Position  int       `gorm:"not null;default:0" validate:"min=0"`
+	Status    string    `gorm:"type:varchar(20);not null" validate:"required,oneof=new in_analysis converted lost"`
+	IsActive  bool      `gorm:"default:true"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// Validate performs validation on the LeadStage struct
+func (s *LeadStage) Validate() error {
+	validate := validator.New()
+	return validate.Struct(s)
+}
+
+// LeadLostReason classifies why a lead did not become a patient. Names are unique per user.
+type LeadLostReason struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" validate:"required"`
+	Name      string    `gorm:"type:varchar(100);not null" validate:"required,min=2,max=100"`
+	IsActive  bool      `gorm:"default:true"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// Validate performs validation on the LeadLostReason struct
+func (r *LeadLostReason) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// LeadActivity is a contact attempt or interaction with a lead
+type LeadActivity struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	LeadID     uuid.UUID `gorm:"type:uuid;not null;index" validate:"required"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null" validate:"required"`
+	Type       string    `gorm:"type:varchar(20);not null" validate:"required,oneof=call message email meeting"`
+	Outcome    string    `gorm:"type:varchar(20);not null" validate:"required,oneof=reached no_answer left_message scheduled not_interested"`
+	Notes      *string   `gorm:"type:text"`
+	OccurredAt time.Time `gorm:"not null;index" validate:"required"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// Validate performs validation on the LeadActivity struct
+func (a *LeadActivity) Validate() error {
+	validate := validator.New()
+	return validate.Struct(a)
+}
+
+// LeadStageTransition records a lead moving between pipeline stages, used to measure stage drop-off
+type LeadStageTransition struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	LeadID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	FromStageID *uuid.UUID `gorm:"type:uuid;index"`
+	ToStageID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null"`
+	ChangedAt   time.Time  `gorm:"not null"`
+}
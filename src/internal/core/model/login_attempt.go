@@ -0,0 +1,25 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// LoginAttempt records a failed authentication so the account owner can review suspicious activity
+type LoginAttempt struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    *uuid.UUID `gorm:"type:uuid;index"` // Nil when the email does not belong to any user
+	Email     string     `gorm:"type:varchar(100);not null;index"`
+	IPAddress string     `gorm:"type:varchar(45);not null;index"`
+	UserAgent string     `gorm:"type:varchar(255)"`
+	Reason    string     `gorm:"type:varchar(50);not null"` // Use constants from model package
+	CreatedAt time.Time  `gorm:"autoCreateTime;index"`
+}
+
+// LoginThrottle holds the failure counters of a throttling key (an email or an IP address)
+type LoginThrottle struct {
+	Key           string    `gorm:"type:varchar(150);primaryKey"`
+	Failures      int       `gorm:"not null;default:0"`
+	LastFailureAt time.Time `gorm:"not null"`
+	BlockedUntil  *time.Time
+}
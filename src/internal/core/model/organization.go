@@ -0,0 +1,23 @@
+package model
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Organization groups users (professionals, secretaries, admins) working under the same clinic or practice
+type Organization struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name        string     `gorm:"type:varchar(100);not null" validate:"required,min=2,max=100"`
+	Slug        string     `gorm:"type:varchar(60);uniqueIndex;not null" validate:"required,min=2,max=60"`
+	LeadOwnerID *uuid.UUID `gorm:"type:uuid"` // Receives leads from the public contact form; capture is disabled when empty
+	CreatedAt   time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime"`
+}
+
+// Validate performs validation on the Organization struct
+func (o *Organization) Validate() error {
+	validate := validator.New()
+	return validate.Struct(o)
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"time"
@@ -15,28 +16,35 @@ type Patient struct {
 	FullName              string     `gorm:"type:varchar(100);not null" validate:"required,min=2,max=100"`
 	SocialName            *string    `gorm:"type:varchar(100)"`
 	BirthDate             time.Time  `gorm:"type:date;not null" validate:"required"`
-	Document              *string    `gorm:"type:varchar(20)"`
-	Phone                 *string    `gorm:"type:varchar(20)"`
+	Document              *string    `gorm:"type:varchar(20)" validate:"omitempty,cpf"`
+	Phone                 *string    `gorm:"type:varchar(20)" validate:"omitempty,br_phone"`
 	Email                 *string    `gorm:"type:varchar(100)"`
 	Gender                *string    `gorm:"type:varchar(20)"`
-	Address               *string    `gorm:"type:varchar(255)"`
+	Address               *string    `gorm:"type:varchar(255)"` // Free-text address kept from before the structured one
+	PostalAddress         Address    `gorm:"embedded;embeddedPrefix:address_"`
 	ResidesWith           *string    `gorm:"type:varchar(100)"`
 	EmergencyContactName  *string    `gorm:"type:varchar(100)"`
-	EmergencyContactPhone *string    `gorm:"type:varchar(20)"`
+	EmergencyContactPhone *string    `gorm:"type:varchar(20)" validate:"omitempty,br_phone"`
 	Observation           *string    `gorm:"type:text"`
 	DefaultRepasseType    *string    `gorm:"type:varchar(20)" validate:"omitempty,oneof=percent fixed"`
 	DefaultRepasseValue   *int64     `gorm:"type:bigint"` // Stored as cents or basis points (for percent)
 	IsActive              bool       `gorm:"column:active;default:true"`
+	LeadID                *uuid.UUID `gorm:"type:uuid;index"`  // Lead this patient was converted from
+	Origin                *string    `gorm:"type:varchar(50)"` // Acquisition origin carried over from the lead
+	AnamneseTemplateID    *uuid.UUID `gorm:"type:uuid"`        // Template chosen for the intake anamnese
 	CreatedAt             time.Time  `gorm:"autoCreateTime"`
 	UpdatedAt             time.Time  `gorm:"autoUpdateTime"`
 }
 
 // Validate performs validation on the Patient struct
 func (p *Patient) Validate() error {
-	validate := validator.New()
+	validate, err := newValidator()
+	if err != nil {
+		return err
+	}
 
 	// Custom validation for DefaultRepasseValue based on DefaultRepasseType
-	err := validate.RegisterValidation("default_repasse_value_valid", func(fl validator.FieldLevel) bool {
+	err = validate.RegisterValidation("default_repasse_value_valid", func(fl validator.FieldLevel) bool {
 		// Get the parent struct
 		patient, ok := fl.Parent().Interface().(Patient)
 		if !ok {
@@ -69,3 +77,60 @@ func (p *Patient) Validate() error {
 
 	return validate.Struct(p)
 }
+
+// NormalizeContact normalizes the CPF to digits, the phones to E.164 and the postal address,
+// returning the fields that could not be normalized
+func (p *Patient) NormalizeContact() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	errs.NormalizeOptional("document", &p.Document, validation.NormalizeCPF)
+	errs.NormalizeOptional("phone", &p.Phone, validation.NormalizePhone)
+	errs.NormalizeOptional("emergency_contact_phone", &p.EmergencyContactPhone, validation.NormalizePhone)
+	p.PostalAddress.Normalize("postal_address", errs)
+	return errs
+}
+
+// IsMinorAt reports whether the patient is under the age of majority at the given time
+func (p *Patient) IsMinorAt(t time.Time) bool {
+	return p.BirthDate.AddDate(AgeOfMajority, 0, 0).After(t)
+}
+
+// FillMissingFrom copies the optional fields that are empty on the patient from another
+// record of the same person and returns the names of the filled fields
+func (p *Patient) FillMissingFrom(other Patient) []string {
+	var filled []string
+	fill := func(name string, dst **string, src *string) {
+		if (*dst == nil || **dst == "") && src != nil && *src != "" {
+			value := *src
+			*dst = &value
+			filled = append(filled, name)
+		}
+	}
+
+	fill("social_name", &p.SocialName, other.SocialName)
+	fill("document", &p.Document, other.Document)
+	fill("phone", &p.Phone, other.Phone)
+	fill("email", &p.Email, other.Email)
+	fill("gender", &p.Gender, other.Gender)
+	fill("address", &p.Address, other.Address)
+	fill("resides_with", &p.ResidesWith, other.ResidesWith)
+	fill("emergency_contact_name", &p.EmergencyContactName, other.EmergencyContactName)
+	fill("emergency_contact_phone", &p.EmergencyContactPhone, other.EmergencyContactPhone)
+	fill("observation", &p.Observation, other.Observation)
+	fill("origin", &p.Origin, other.Origin)
+
+	if p.PostalAddress == (Address{}) && other.PostalAddress != (Address{}) {
+		p.PostalAddress = other.PostalAddress
+		filled = append(filled, "postal_address")
+	}
+
+	if p.LeadID == nil && other.LeadID != nil {
+		p.LeadID = other.LeadID
+		filled = append(filled, "lead_id")
+	}
+	if p.AnamneseTemplateID == nil && other.AnamneseTemplateID != nil {
+		p.AnamneseTemplateID = other.AnamneseTemplateID
+		filled = append(filled, "anamnese_template_id")
+	}
+
+	return filled
+}
@@ -7,11 +7,19 @@ import (
 
 // PatientAnamnese represents a filled response for a patient based on a template
 type PatientAnamnese struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	PatientID  uuid.UUID `gorm:"type:uuid;not null"`
-	AnamneseID uuid.UUID `gorm:"type:uuid;not null"`
-	UserID     uuid.UUID `gorm:"type:uuid;not null"`
-	AnsweredAt time.Time `gorm:"not null"`
-	CreatedAt  time.Time `gorm:"autoCreateTime"`
-	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+	ID                uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	PatientID         uuid.UUID  `gorm:"type:uuid;not null"`
+	AnamneseID        uuid.UUID  `gorm:"type:uuid;not null"`
+	TemplateVersionID uuid.UUID  `gorm:"type:uuid;index"` // Published version the answers refer to
+	UserID            uuid.UUID  `gorm:"type:uuid;not null"`
+	Status            string     `gorm:"type:varchar(20);not null;default:'finalized'"`
+	AnsweredAt        time.Time  `gorm:"not null"`
+	FinalizedAt       *time.Time // Answers can no longer change after it
+	CreatedAt         time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime"`
+}
+
+// IsDraft reports whether the answers can still be changed
+func (pa *PatientAnamnese) IsDraft() bool {
+	return pa.Status == PatientAnamneseStatusDraft
 }
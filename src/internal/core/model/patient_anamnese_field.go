@@ -11,6 +11,7 @@ type PatientAnamneseField struct {
 	PatientAnamneseID uuid.UUID `gorm:"type:uuid;not null"`
 	FieldID           uuid.UUID `gorm:"type:uuid;not null"`
 	Value             string    `gorm:"type:text"`
+	Values            *string   `gorm:"type:jsonb"` // Chosen options of a multiselect field, as a JSON array
 	CreatedAt         time.Time `gorm:"autoCreateTime"`
 	UpdatedAt         time.Time `gorm:"autoUpdateTime"`
 }
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// PatientAnamneseFieldHistory records a change to the answer of a field of a patient anamnese.
+// Empty values mean the field had no answer before or was cleared.
+type PatientAnamneseFieldHistory struct {
+	ID                uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	PatientAnamneseID uuid.UUID `gorm:"type:uuid;not null;index"`
+	FieldID           uuid.UUID `gorm:"type:uuid;not null"`
+	PreviousValue     string    `gorm:"type:text"`
+	PreviousValues    *string   `gorm:"type:jsonb"`
+	Value             string    `gorm:"type:text"`
+	Values            *string   `gorm:"type:jsonb"`
+	ChangedBy         uuid.UUID `gorm:"type:uuid;not null"`
+	ChangedAt         time.Time `gorm:"not null"`
+}
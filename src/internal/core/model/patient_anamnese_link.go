@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// PatientAnamneseLink lets a patient, or a guardian, fill an anamnese from their own device
+// without an account. The link is scoped to one patient and one template version, can be used
+// once and only the SHA-256 hash of its token is stored.
+type PatientAnamneseLink struct {
+	ID                uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID            uuid.UUID `gorm:"type:uuid;not null;index"` // Professional who sent the link
+	PatientID         uuid.UUID `gorm:"type:uuid;not null;index"`
+	AnamneseID        uuid.UUID `gorm:"type:uuid;not null"`
+	TemplateVersionID uuid.UUID `gorm:"type:uuid;not null"`
+	TokenHash         string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt         time.Time `gorm:"not null"`
+	UsedAt            *time.Time
+	PatientAnamneseID *uuid.UUID `gorm:"type:uuid"` // Draft created from the answers sent through the link
+	RevokedAt         *time.Time
+	CreatedAt         time.Time `gorm:"autoCreateTime"`
+}
+
+// IsUsableAt reports whether answers can still be sent through the link at the given time
+func (l *PatientAnamneseLink) IsUsableAt(t time.Time) bool {
+	return l.UsedAt == nil && l.RevokedAt == nil && t.Before(l.ExpiresAt)
+}
+
+// PatientAnamneseLinkAccess records each use of a self-service anamnese link, including the
+// attempts made after it could no longer be used
+type PatientAnamneseLinkAccess struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	LinkID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Action    string    `gorm:"type:varchar(20);not null"` // Use constants from model package
+	IPAddress string    `gorm:"type:varchar(45);not null"`
+	UserAgent string    `gorm:"type:varchar(255)"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"time"
+)
+
+// PatientConsent records a consent signed for a patient. For minors it is signed by a
+// family member who is legal responsible for the patient.
+type PatientConsent struct {
+	ID                uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	PatientID         uuid.UUID  `gorm:"type:uuid;not null;index" validate:"required"`
+	GuardianID        *uuid.UUID `gorm:"type:uuid;index"` // Family member who signed; empty when signed by the patient
+	Type              string     `gorm:"type:varchar(30);not null" validate:"required,oneof=treatment data_processing recording"`
+	SignedAt          time.Time  `gorm:"not null" validate:"required"`
+	ValidUntil        *time.Time
+	DocumentReference string  `gorm:"type:varchar(255);not null" validate:"required,max=255"` // Location or identifier of the signed document
+	Notes             *string `gorm:"type:text"`
+	RevokedAt         *time.Time
+	CreatedAt         time.Time `gorm:"autoCreateTime"`
+}
+
+// Validate performs validation on the PatientConsent struct
+func (pc *PatientConsent) Validate() error {
+	validate := validator.New()
+	return validate.Struct(pc)
+}
+
+// IsValidAt reports whether the consent is signed, not revoked and not expired at the given time
+func (pc *PatientConsent) IsValidAt(t time.Time) bool {
+	if pc.SignedAt.After(t) {
+		return false
+	}
+	if pc.RevokedAt != nil && !pc.RevokedAt.After(t) {
+		return false
+	}
+	return pc.ValidUntil == nil || pc.ValidUntil.After(t)
+}
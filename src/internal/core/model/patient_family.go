@@ -1,27 +1,50 @@
 package model
 
 import (
-	"github.com/go-playground/validator/v10"
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
 	"github.com/google/uuid"
 	"time"
 )
 
 // PatientFamily represents a family member of a patient
 type PatientFamily struct {
-	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	PatientID    uuid.UUID  `gorm:"type:uuid;not null;index" validate:"required"`
-	Patient      Patient    `gorm:"foreignKey:PatientID"`
-	Relationship string     `gorm:"type:varchar(50);not null" validate:"required"`
-	Name         string     `gorm:"type:varchar(100);not null" validate:"required,min=2,max=100"`
-	BirthDate    *time.Time `gorm:"type:date"`
-	Schooling    *string    `gorm:"type:varchar(50)"`
-	Occupation   *string    `gorm:"type:varchar(100)"`
-	CreatedAt    time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt    time.Time  `gorm:"autoUpdateTime"`
+	ID                     uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	PatientID              uuid.UUID  `gorm:"type:uuid;not null;index" validate:"required"`
+	Patient                Patient    `gorm:"foreignKey:PatientID"`
+	Relationship           string     `gorm:"type:varchar(50);not null" validate:"required"`
+	Name                   string     `gorm:"type:varchar(100);not null" validate:"required,min=2,max=100"`
+	BirthDate              *time.Time `gorm:"type:date"`
+	Schooling              *string    `gorm:"type:varchar(50)"`
+	Occupation             *string    `gorm:"type:varchar(100)"`
+	Document               *string    `gorm:"type:varchar(20)" validate:"omitempty,cpf"`
+	Phone                  *string    `gorm:"type:varchar(20)" validate:"omitempty,br_phone"`
+	Email                  *string    `gorm:"type:varchar(100)" validate:"omitempty,email"`
+	Address                Address    `gorm:"embedded;embeddedPrefix:address_"`
+	IsLegalResponsible     bool       `gorm:"not null;default:false"` // May sign consents on behalf of a minor patient
+	IsFinancialResponsible bool       `gorm:"not null;default:false"` // Pays for the patient's treatment
+	Gender                 *string    `gorm:"type:varchar(20)"`
+	IsDeceased             bool       `gorm:"not null;default:false"`
+	DeathDate              *time.Time `gorm:"type:date"`
+	Notes                  *string    `gorm:"type:text"`
+	CreatedAt              time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt              time.Time  `gorm:"autoUpdateTime"`
 }
 
 // Validate performs validation on the PatientFamily struct
 func (pf *PatientFamily) Validate() error {
-	validate := validator.New()
+	validate, err := newValidator()
+	if err != nil {
+		return err
+	}
 	return validate.Struct(pf)
 }
+
+// NormalizeContact normalizes the CPF to digits, the phone to E.164 and the address,
+// returning the fields that could not be normalized
+func (pf *PatientFamily) NormalizeContact() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	errs.NormalizeOptional("document", &pf.Document, validation.NormalizeCPF)
+	errs.NormalizeOptional("phone", &pf.Phone, validation.NormalizePhone)
+	pf.Address.Normalize("address", errs)
+	return errs
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// PatientFamilyLink links two people of a patient's family tree. Each end is either the
+// patient or one of the patient's family members.
+type PatientFamilyLink struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	PatientID uuid.UUID `gorm:"type:uuid;not null;index"`
+	FromID    uuid.UUID `gorm:"type:uuid;not null;index"`  // Parent on parent links
+	ToID      uuid.UUID `gorm:"type:uuid;not null;index"`  // Child on parent links
+	Type      string    `gorm:"type:varchar(20);not null"` // Use constants from model.FamilyLinkType*
+	Notes     *string   `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
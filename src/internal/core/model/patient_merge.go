@@ -0,0 +1,18 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// PatientMerge records the merge of a duplicate patient into the surviving one
+type PatientMerge struct {
+	ID              uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID          uuid.UUID `gorm:"type:uuid;not null;index"`
+	SurvivorID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	MergedPatientID uuid.UUID `gorm:"type:uuid;not null;index"` // ID of the removed duplicate
+	MergedSnapshot  string    `gorm:"type:jsonb;not null"`      // Duplicate patient as it was before the merge
+	MovedRecords    string    `gorm:"type:jsonb;not null"`      // Number of rows repointed per table
+	FilledFields    *string   `gorm:"type:text"`                // Survivor fields filled from the duplicate, comma separated
+	MergedAt        time.Time `gorm:"autoCreateTime"`
+}
@@ -11,6 +11,7 @@ type Payment struct {
 	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
 	UserID       uuid.UUID  `gorm:"type:uuid;not null;index" validate:"required"`
 	PatientID    *uuid.UUID `gorm:"type:uuid;index"`
+	PayerID      *uuid.UUID `gorm:"type:uuid;index"` // Family member financially responsible for the patient
 	CostCenterID uuid.UUID  `gorm:"type:uuid;not null;index" validate:"required"`
 	CostCenter   CostCenter `gorm:"foreignKey:CostCenterID"`
 	PaymentDate  time.Time  `gorm:"not null" validate:"required"`
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Scale is a standardized psychometric instrument, such as the PHQ-9 or the GAD-7. Scales are
+// shipped with the API, and every item is answered with one of the response options of the scale.
+type Scale struct {
+	ID           uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Code         string        `gorm:"type:varchar(30);uniqueIndex;not null"`
+	Name         string        `gorm:"type:varchar(100);not null"`
+	Description  string        `gorm:"type:text"`
+	Instructions string        `gorm:"type:text"`
+	Scoring      string        `gorm:"type:varchar(20);not null"` // Use constants from model.ScaleScoring*
+	Items        []ScaleItem   `gorm:"foreignKey:ScaleID"`
+	Options      []ScaleOption `gorm:"foreignKey:ScaleID"`
+	Bands        []ScaleBand   `gorm:"foreignKey:ScaleID"`
+	CreatedAt    time.Time     `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time     `gorm:"autoUpdateTime"`
+}
+
+// ScaleItem is a question of a psychometric scale
+type ScaleItem struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ScaleID       uuid.UUID `gorm:"type:uuid;not null;index"`
+	ItemNumber    int       `gorm:"not null"`
+	Text          string    `gorm:"type:varchar(500);not null"`
+	ReverseScored bool      `gorm:"default:false"` // Scored from the highest option down
+	Critical      bool      `gorm:"default:false"` // Answers above the lowest option are flagged for follow-up
+}
+
+// ScaleOption is a response option shared by the items of a psychometric scale
+type ScaleOption struct {
+	ID      uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ScaleID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Value   int       `gorm:"not null"`
+	Label   string    `gorm:"type:varchar(100);not null"`
+}
+
+// ScaleBand is the severity of the total scores of a psychometric scale between MinScore and
+// MaxScore, both inclusive
+type ScaleBand struct {
+	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ScaleID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	MinScore float64   `gorm:"not null"`
+	MaxScore float64   `gorm:"not null"`
+	Severity string    `gorm:"type:varchar(50);not null"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ScaleApplication is a psychometric scale answered by a patient, optionally during a session.
+// Its score is computed by the API from the answers.
+type ScaleApplication struct {
+	ID        uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    uuid.UUID     `gorm:"type:uuid;not null;index"`
+	PatientID uuid.UUID     `gorm:"type:uuid;not null;index"`
+	SessionID *uuid.UUID    `gorm:"type:uuid;index"`
+	ScaleID   uuid.UUID     `gorm:"type:uuid;not null;index"`
+	Scale     Scale         `gorm:"foreignKey:ScaleID"`
+	Score     float64       `gorm:"not null"`
+	Severity  *string       `gorm:"type:varchar(50)"` // Empty when no band of the scale holds the score
+	Flagged   bool          `gorm:"default:false"`    // Some critical item was answered above its lowest option
+	AppliedAt time.Time     `gorm:"not null;index"`
+	Notes     *string       `gorm:"type:text"`
+	Answers   []ScaleAnswer `gorm:"foreignKey:ApplicationID"`
+	CreatedAt time.Time     `gorm:"autoCreateTime"`
+}
+
+// ScaleAnswer is the answer to an item of a scale application
+type ScaleAnswer struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ApplicationID uuid.UUID `gorm:"type:uuid;not null;index"`
+	ItemID        uuid.UUID `gorm:"type:uuid;not null"`
+	Value         int       `gorm:"not null"` // Option chosen
+	Score         int       `gorm:"not null"` // Value after reverse scoring
+	Critical      bool      `gorm:"default:false"`
+}
@@ -13,6 +13,7 @@ type Session struct {
 	Appointment    Appointment `gorm:"foreignKey:AppointmentID"`
 	UserID         uuid.UUID   `gorm:"type:uuid;not null;index" validate:"required"`
 	PatientID      uuid.UUID   `gorm:"type:uuid;not null;index" validate:"required"`
+	EpisodeID      *uuid.UUID  `gorm:"type:uuid;index"` // Episode of care of the appointment
 	ProfessionalID uuid.UUID   `gorm:"type:uuid;not null;index" validate:"required"`
 	StartTime      time.Time   `gorm:"not null" validate:"required"`
 	EndTime        time.Time   `gorm:"not null" validate:"required,gtfield=StartTime"`
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// SigningKey is an asymmetric key used to sign access tokens, identified by the JWT "kid" header.
+// Retired keys stop signing but keep verifying until ExpiresAt, so tokens issued before a rotation remain valid.
+type SigningKey struct {
+	ID         string    `gorm:"type:varchar(64);primaryKey"` // Key ID published in the JWKS
+	Algorithm  string    `gorm:"type:varchar(10);not null"`   // RS256 or EdDSA
+	PrivateKey string    `gorm:"type:text;not null"`          // PKCS#8 PEM
+	PublicKey  string    `gorm:"type:text;not null"`          // PKIX PEM
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	RetiredAt  *time.Time
+	ExpiresAt  *time.Time `gorm:"index"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// TreatmentPlan is the plan agreed for the treatment of a patient. Its content lives in
+// versions: every revision creates a new version and earlier versions are kept unchanged.
+type TreatmentPlan struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index"`
+	PatientID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Title          string     `gorm:"type:varchar(150);not null"`
+	Status         string     `gorm:"type:varchar(20);not null"` // Use constants from model.TreatmentPlanStatus*
+	StartDate      time.Time  `gorm:"not null"`
+	EndedAt        *time.Time // Set when the plan is completed or discontinued
+	CurrentVersion int        `gorm:"not null"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
+}
+
+// IsActive reports whether the plan is still being followed
+func (p *TreatmentPlan) IsActive() bool {
+	return p.Status == TreatmentPlanStatusActive
+}
+
+// TreatmentPlanVersion is the content of a treatment plan as written in one of its revisions
+type TreatmentPlanVersion struct {
+	ID             uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	PlanID         uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_treatment_plan_version"`
+	Version        int             `gorm:"not null;uniqueIndex:idx_treatment_plan_version"`
+	Summary        string          `gorm:"type:text"`
+	RevisionReason *string         `gorm:"type:text"` // Empty on the first version
+	CreatedBy      uuid.UUID       `gorm:"type:uuid;not null"`
+	Goals          []TreatmentGoal `gorm:"foreignKey:VersionID"`
+	CreatedAt      time.Time       `gorm:"autoCreateTime"`
+}
+
+// TreatmentGoal is a goal of a version of a treatment plan
+type TreatmentGoal struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	VersionID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	GoalKey     uuid.UUID  `gorm:"type:uuid;not null;index"` // Same on the copies of the goal across versions
+	Position    int        `gorm:"not null"`
+	Description string     `gorm:"type:varchar(500);not null"`
+	TargetDate  *time.Time `gorm:"type:date"`
+	Status      string     `gorm:"type:varchar(20);not null"` // Use constants from model.TreatmentGoalStatus*
+}
+
+// TreatmentGoalProgress is the progress on a goal rated in an evolution, from 0 (none) to 10
+// (goal reached)
+type TreatmentGoalProgress struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	EvolutionID uuid.UUID `gorm:"type:uuid;not null;index"`
+	PlanID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	GoalID      uuid.UUID `gorm:"type:uuid;not null"`       // Goal of the version current when rated
+	GoalKey     uuid.UUID `gorm:"type:uuid;not null;index"` // Follows the goal across revisions
+	Rating      int       `gorm:"not null"`
+	Notes       *string   `gorm:"type:text"`
+	RatedAt     time.Time `gorm:"not null"` // Start of the session of the evolution
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// UserRecoveryCode is a single-use code that replaces the TOTP code when the authenticator is unavailable.
+// Only the SHA-256 hash of the code is stored.
+type UserRecoveryCode struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	CodeHash  string    `gorm:"type:varchar(64);not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TwoFactorPolicy makes two-factor authentication mandatory for an organization, a role, or both.
+// A nil OrganizationID or Role acts as a wildcard, so a policy with both nil applies to every user.
+type TwoFactorPolicy struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid;index"`
+	Role           *string    `gorm:"type:varchar(50);index"`
+	CreatedBy      uuid.UUID  `gorm:"type:uuid;not null"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime"`
+}
+
+// AppliesTo reports whether the policy covers the given user
+func (p *TwoFactorPolicy) AppliesTo(user *User) bool {
+	if p.OrganizationID != nil && (user.OrganizationID == nil || *user.OrganizationID != *p.OrganizationID) {
+		return false
+	}
+	if p.Role != nil && *p.Role != user.Role {
+		return false
+	}
+	return true
+}
@@ -1,19 +1,55 @@
 package model
 
 import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
 	"github.com/google/uuid"
 	"time"
 )
 
 type User struct {
-	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	Name         string    `gorm:"type:varchar(100);not null"`
-	Email        string    `gorm:"type:varchar(100);uniqueIndex;not null"`
-	PasswordHash string    `gorm:"type:varchar(255);not null"`
-	Role         string    `gorm:"type:varchar(50);default:professional;not null"`
-	Phone        *string   `gorm:"type:varchar(20)"`
-	IsActive     bool      `gorm:"default:true"`
-	CreatedAt    time.Time `gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
-	LastLoginAt  *time.Time
+	ID                   uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrganizationID       *uuid.UUID `gorm:"type:uuid;index"`
+	Name                 string     `gorm:"type:varchar(100);not null"`
+	Email                string     `gorm:"type:varchar(100);uniqueIndex;not null"`
+	PasswordHash         string     `gorm:"type:varchar(255);not null"`
+	Role                 string     `gorm:"type:varchar(50);default:professional;not null"`
+	Phone                *string    `gorm:"type:varchar(20)" validate:"omitempty,br_phone"`
+	Address              Address    `gorm:"embedded;embeddedPrefix:address_"`
+	IsActive             bool       `gorm:"default:true"`
+	TwoFactorEnabled     bool       `gorm:"default:false"`
+	TwoFactorSecret      *string    `gorm:"type:varchar(128)"` // Encrypted base32 TOTP secret, pending until confirmed
+	TwoFactorConfirmedAt *time.Time
+	TwoFactorLastStep    int64      `gorm:"default:0"` // Last accepted TOTP time step, prevents code reuse
+	TokensRevokedAt      *time.Time // Tokens issued before this instant are rejected
+	CreatedAt            time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt            time.Time  `gorm:"autoUpdateTime"`
+	LastLoginAt          *time.Time
+}
+
+// Validate performs validation on the User struct
+func (u *User) Validate() error {
+	validate, err := newValidator()
+	if err != nil {
+		return err
+	}
+	return validate.Struct(u)
+}
+
+// NormalizeContact normalizes the phone to E.164 and the address, returning the fields that could not be normalized
+func (u *User) NormalizeContact() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	errs.NormalizeOptional("phone", &u.Phone, validation.NormalizePhone)
+	u.Address.Normalize("address", errs)
+	return errs
+}
+
+// RevokeTokens invalidates every token issued to the user until now
+func (u *User) RevokeTokens(now time.Time) {
+	u.TokensRevokedAt = &now
+}
+
+// AcceptsTokenIssuedAt reports whether a token issued at the given instant was not revoked.
+// Token timestamps have second precision, so tokens issued in the same second as the revocation are kept.
+func (u *User) AcceptsTokenIssuedAt(issuedAt time.Time) bool {
+	return u.TokensRevokedAt == nil || issuedAt.Unix() >= u.TokensRevokedAt.Unix()
 }
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// UserInvite allows an invited user to define the password and activate the account.
+// Only the SHA-256 hash of the invite token is stored.
+type UserInvite struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	User       User      `gorm:"foreignKey:UserID"`
+	TokenHash  string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	InvitedBy  uuid.UUID `gorm:"type:uuid;not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	AcceptedAt *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+// newValidator creates a validator aware of the Brazilian document, phone and address tags
+func newValidator() (*validator.Validate, error) {
+	validate := validator.New()
+	if err := validation.Register(validate); err != nil {
+		return nil, err
+	}
+	return validate, nil
+}
@@ -1,21 +1,80 @@
 package port
 
-import "github.com/LacirJR/psygrow-api/src/internal/core/model"
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+)
+
+// AnamneseOptionInUseError is returned when an option chosen in patient answers would be
+// removed
+type AnamneseOptionInUseError struct {
+	OptionID uuid.UUID
+}
+
+func (e *AnamneseOptionInUseError) Error() string {
+	return "option " + e.OptionID.String() + " was chosen in patient answers"
+}
+
+// ErrFieldOrderIncomplete is returned when the fields of a version are reordered without
+// listing every one of them
+var ErrFieldOrderIncomplete = errors.New("every field of the version must be listed")
+
+// ErrPatientAnamneseFinalized is returned when the answers of an anamnese that was finalized
+// meanwhile are changed
+var ErrPatientAnamneseFinalized = errors.New("patient anamnese is already finalized")
+
+// AnamneseTemplateCopy is a template to create with its version and the fields of the version
+type AnamneseTemplateCopy struct {
+	Template *model.AnamneseTemplate
+	Version  *model.AnamneseTemplateVersion
+	Fields   []*model.AnamneseField
+}
 
 type AnamneseTemplateRepository interface {
 	Save(template *model.AnamneseTemplate) error
 	FindByID(id string) (*model.AnamneseTemplate, error)
 	FindByUserID(userID string) ([]*model.AnamneseTemplate, error)
 	Update(template *model.AnamneseTemplate) error
+	// Delete deletes a template along with its versions, fields and options
 	Delete(id string) error
+	// CreateWithVersion creates a template with a version and its fields and options in a
+	// single transaction
+	CreateWithVersion(template *model.AnamneseTemplate, version *model.AnamneseTemplateVersion, fields []*model.AnamneseField) error
+	// CreateCopies creates every given template with its version, fields and options in a
+	// single transaction
+	CreateCopies(copies []AnamneseTemplateCopy) error
+	// FindBuiltIn finds the starter templates shipped with the API
+	FindBuiltIn() ([]*model.AnamneseTemplate, error)
+	FindByBuiltInKey(key string) (*model.AnamneseTemplate, error)
+}
+
+type AnamneseTemplateVersionRepository interface {
+	FindByID(id string) (*model.AnamneseTemplateVersion, error)
+	// FindByIDWithFields finds a version with its fields and their options, in order
+	FindByIDWithFields(id string) (*model.AnamneseTemplateVersion, error)
+	// FindByTemplateID finds the versions of a template, most recent first
+	FindByTemplateID(templateID string) ([]*model.AnamneseTemplateVersion, error)
+	FindDraft(templateID string) (*model.AnamneseTemplateVersion, error)
+	FindLatestPublished(templateID string) (*model.AnamneseTemplateVersion, error)
+	// FindOrCreateDraft returns the draft version of a template, creating it as a copy of the
+	// latest published version when there is none
+	FindOrCreateDraft(templateID string) (*model.AnamneseTemplateVersion, error)
+	// Publish freezes a draft version, making it the version new anamneses are answered against
+	Publish(version *model.AnamneseTemplateVersion) error
 }
 
 type AnamneseFieldRepository interface {
 	Save(field *model.AnamneseField) error
 	FindByID(id string) (*model.AnamneseField, error)
-	FindByAnamneseID(anamneseID string) ([]*model.AnamneseField, error)
+	FindByVersionID(versionID string) ([]*model.AnamneseField, error)
+	// FindByKey finds the copy of a field in the given version
+	FindByKey(versionID string, fieldKey uuid.UUID) (*model.AnamneseField, error)
 	Update(field *model.AnamneseField) error
 	Delete(id string) error
+	// Reorder numbers the fields of a version in the given order in a single transaction. It
+	// fails with ErrFieldOrderIncomplete unless every field of the version is given.
+	Reorder(versionID uuid.UUID, fieldIDs []uuid.UUID) error
 }
 
 type AnamneseFieldOptionRepository interface {
@@ -26,16 +85,62 @@ type AnamneseFieldOptionRepository interface {
 	Update(option *model.AnamneseFieldOption) error
 	Delete(id string) error
 	DeleteByAnamneseFieldID(anamneseFieldID string) error
+	// Reorder orders the options of a field as given in a single transaction
+	Reorder(anamneseFieldID uuid.UUID, optionIDs []uuid.UUID) error
+	// DeleteUnused deletes an option unless it was chosen in patient answers, in which case it
+	// fails with an AnamneseOptionInUseError
+	DeleteUnused(option *model.AnamneseFieldOption) error
+	// ReplaceByAnamneseFieldID saves the given options and deletes every other option of the
+	// field in a single transaction. It fails with an AnamneseOptionInUseError when an option to
+	// delete was chosen in patient answers.
+	ReplaceByAnamneseFieldID(anamneseFieldID uuid.UUID, options []*model.AnamneseFieldOption) error
+}
+
+// PatientAnamneseAnswerChanges are the answers of an anamnese to save and remove, with the
+// history of the change
+type PatientAnamneseAnswerChanges struct {
+	Saved   []*model.PatientAnamneseField
+	Removed []uuid.UUID
+	History []*model.PatientAnamneseFieldHistory
 }
 
 type PatientAnamneseRepository interface {
 	Save(patientAnamnese *model.PatientAnamnese) error
+	// SaveWithFields creates the anamnese and its answers in a single transaction
+	SaveWithFields(patientAnamnese *model.PatientAnamnese, fields []*model.PatientAnamneseField) error
+	Update(patientAnamnese *model.PatientAnamnese) error
+	// UpdateAnswers locks a draft anamnese, reloads its answers and saves the changes computed
+	// from them by fn, with their history, in a single transaction. It fails with
+	// ErrPatientAnamneseFinalized once the anamnese is finalized, and with the error of fn.
+	UpdateAnswers(patientAnamnese *model.PatientAnamnese, fn func(stored []*model.PatientAnamneseField) (PatientAnamneseAnswerChanges, error)) error
+	// Finalize locks a draft anamnese, reloads its answers and, once check accepts them, saves
+	// the anamnese with its answers locked in a single transaction. It fails with
+	// ErrPatientAnamneseFinalized when the anamnese was finalized meanwhile, and with the error
+	// of check.
+	Finalize(patientAnamnese *model.PatientAnamnese, check func(stored []*model.PatientAnamneseField) error) error
 	FindByID(id string) (*model.PatientAnamnese, error)
 	FindByPatientID(patientID string) ([]*model.PatientAnamnese, error)
 	FindByUserID(userID string) ([]*model.PatientAnamnese, error)
+	CountByTemplateID(templateID string) (int64, error)
 }
 
 type PatientAnamneseFieldRepository interface {
 	Save(field *model.PatientAnamneseField) error
 	FindByPatientAnamneseID(patientAnamneseID string) ([]*model.PatientAnamneseField, error)
+	FindHistoryByPatientAnamneseID(patientAnamneseID string) ([]*model.PatientAnamneseFieldHistory, error)
+}
+
+type PatientAnamneseLinkRepository interface {
+	Save(link *model.PatientAnamneseLink) error
+	FindByID(id uuid.UUID, userID uuid.UUID) (*model.PatientAnamneseLink, error)
+	FindByPatient(patientID uuid.UUID, userID uuid.UUID) ([]model.PatientAnamneseLink, error)
+	// FindByTokenHash finds a link by its token hash, whether it can still be used or not
+	FindByTokenHash(tokenHash string) (*model.PatientAnamneseLink, error)
+	// Consume marks the link as used and creates the draft anamnese with its answers in a single
+	// transaction. It fails with gorm.ErrRecordNotFound when the link expired or was used or
+	// revoked meanwhile.
+	Consume(link *model.PatientAnamneseLink, patientAnamnese *model.PatientAnamnese, fields []*model.PatientAnamneseField) error
+	Revoke(link *model.PatientAnamneseLink) error
+	LogAccess(access *model.PatientAnamneseLinkAccess) error
+	FindAccesses(linkID uuid.UUID) ([]model.PatientAnamneseLinkAccess, error)
 }
@@ -0,0 +1,27 @@
+package port
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+type APIKeyRepository interface {
+	// Save stores a new API key
+	Save(key *model.APIKey) error
+
+	// FindByPrefix finds an API key by its public prefix
+	FindByPrefix(prefix string) (*model.APIKey, error)
+
+	// FindByIDAndUserID finds an API key owned by the user
+	FindByIDAndUserID(id uuid.UUID, userID uuid.UUID) (*model.APIKey, error)
+
+	// FindByUserID finds all API keys owned by the user, newest first
+	FindByUserID(userID uuid.UUID) ([]model.APIKey, error)
+
+	// Revoke disables an API key
+	Revoke(id uuid.UUID, revokedAt time.Time) error
+
+	// TouchLastUsed records the last use of an API key, writing at most once per interval
+	TouchLastUsed(id uuid.UUID, at time.Time, interval time.Duration) error
+}
@@ -5,7 +5,7 @@ import "github.com/LacirJR/psygrow-api/src/internal/core/model"
 type AppointmentRepository interface {
 	Save(appointment *model.Appointment) error
 	FindByID(id string) (*model.Appointment, error)
-	FindByUserID(userID string) ([]*model.Appointment, error)
+	List(userID string, query ListQuery) (Page[model.Appointment], error)
 	FindByPatientID(patientID string) ([]*model.Appointment, error)
 	FindByProfessionalID(professionalID string) ([]*model.Appointment, error)
 	Update(appointment *model.Appointment) error
@@ -16,13 +16,16 @@ type SessionRepository interface {
 	Save(session *model.Session) error
 	FindByID(id string) (*model.Session, error)
 	FindByAppointmentID(appointmentID string) (*model.Session, error)
-	FindByUserID(userID string) ([]*model.Session, error)
+	List(userID string, query ListQuery) (Page[model.Session], error)
 	FindByPatientID(patientID string) ([]*model.Session, error)
 	FindByProfessionalID(professionalID string) ([]*model.Session, error)
 }
 
 type EvolutionRepository interface {
 	Save(evolution *model.Evolution) error
+	// SaveWithGoalProgress saves an evolution with the progress it rates on treatment goals in a
+	// single transaction
+	SaveWithGoalProgress(evolution *model.Evolution, progress []model.TreatmentGoalProgress) error
 	FindByID(id string) (*model.Evolution, error)
 	FindBySessionID(sessionID string) (*model.Evolution, error)
 	FindByUserID(userID string) ([]*model.Evolution, error)
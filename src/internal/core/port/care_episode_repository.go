@@ -0,0 +1,54 @@
+package port
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrCareEpisodeAlreadyOpen = errors.New("patient already has an open care episode")
+	ErrCareEpisodeClosed      = errors.New("care episode is already closed")
+)
+
+type CareEpisodeRepository interface {
+	// Open opens an episode for a patient, numbered after their previous ones, and reactivates
+	// the patient in a single transaction. It fails with ErrCareEpisodeAlreadyOpen when the
+	// patient has an open episode.
+	Open(episode *model.CareEpisode) error
+
+	// FindOrOpen finds the open episode of a patient, opening one that starts at the given time
+	// when there is none
+	FindOrOpen(userID uuid.UUID, patientID uuid.UUID, start time.Time) (*model.CareEpisode, error)
+
+	// Schedule creates an appointment attached to the open episode of its patient, opening one
+	// that starts with the appointment when there is none, in a single transaction
+	Schedule(appointment *model.Appointment) error
+
+	// FindByID finds an episode of a patient
+	FindByID(id uuid.UUID, patientID uuid.UUID) (*model.CareEpisode, error)
+
+	// FindByPatient finds the episodes of a patient, most recent first
+	FindByPatient(patientID uuid.UUID) ([]model.CareEpisode, error)
+
+	// Close ends an open episode, deactivates its patient and cancels the appointments of the
+	// patient still scheduled from now on, in a single transaction. It returns the number of
+	// canceled appointments and fails with ErrCareEpisodeClosed when the episode was closed
+	// meanwhile.
+	Close(episode *model.CareEpisode) (int64, error)
+
+	// Deactivate deactivates a patient, closes their open episode, if any, for the given reason
+	// and cancels their appointments still scheduled from now on, in a single transaction. It
+	// returns the number of canceled appointments.
+	Deactivate(patientID uuid.UUID, reason string) (int64, error)
+
+	// CreatePatient creates a patient and, when they are active, opens their first episode
+	// starting now, in a single transaction
+	CreatePatient(patient *model.Patient) error
+
+	// UpdatePatient saves a patient in a single transaction with the episode change their
+	// activation implies: deactivating them works as Deactivate, while reactivating them opens a
+	// new episode. It returns the number of canceled appointments.
+	UpdatePatient(patient *model.Patient, wasActive bool, reason string) (int64, error)
+}
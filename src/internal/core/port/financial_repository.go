@@ -5,7 +5,7 @@ import "github.com/LacirJR/psygrow-api/src/internal/core/model"
 type CostCenterRepository interface {
 	Save(costCenter *model.CostCenter) error
 	FindByID(id string) (*model.CostCenter, error)
-	FindByUserID(userID string) ([]*model.CostCenter, error)
+	List(userID string, query ListQuery) (Page[model.CostCenter], error)
 	Update(costCenter *model.CostCenter) error
 	Delete(id string) error
 }
@@ -13,7 +13,7 @@ type CostCenterRepository interface {
 type PaymentRepository interface {
 	Save(payment *model.Payment) error
 	FindByID(id string) (*model.Payment, error)
-	FindByUserID(userID string) ([]*model.Payment, error)
+	List(userID string, query ListQuery) (Page[model.Payment], error)
 	FindByPatientID(patientID string) ([]*model.Payment, error)
 	FindByCostCenterID(costCenterID string) ([]*model.Payment, error)
 }
@@ -28,7 +28,7 @@ type PaymentAppointmentRepository interface {
 type RepasseRepository interface {
 	Save(repasse *model.Repasse) error
 	FindByID(id string) (*model.Repasse, error)
-	FindByUserID(userID string) ([]*model.Repasse, error)
+	List(userID string, query ListQuery) (Page[model.Repasse], error)
 	FindByAppointmentID(appointmentID string) (*model.Repasse, error)
 	FindByCostCenterID(costCenterID string) ([]*model.Repasse, error)
 	FindByStatus(status string) ([]*model.Repasse, error)
@@ -0,0 +1,73 @@
+package port
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+type LeadStageRepository interface {
+	// Create creates a new pipeline stage
+	Create(stage *model.LeadStage) error
+
+	// CreateDefaults creates the default pipeline of a user, skipping the stage names it already has
+	CreateDefaults(userID uuid.UUID) error
+
+	// FindByID finds a pipeline stage by ID
+	FindByID(id uuid.UUID, userID uuid.UUID) (*model.LeadStage, error)
+
+	// FindAll finds all pipeline stages for a user ordered by position
+	FindAll(userID uuid.UUID) ([]model.LeadStage, error)
+
+	// NameTaken reports whether another stage of the user already has the name
+	NameTaken(userID uuid.UUID, name string, exceptID uuid.UUID) (bool, error)
+
+	// Update updates a pipeline stage
+	Update(stage *model.LeadStage) error
+
+	// Delete deletes a pipeline stage
+	Delete(id uuid.UUID, userID uuid.UUID) error
+
+	// CountLeads counts the leads currently in the stage
+	CountLeads(id uuid.UUID) (int64, error)
+
+	// MoveLead saves the lead in its new stage and records the transition in one transaction
+	MoveLead(lead *model.Lead, transition *model.LeadStageTransition) error
+}
+
+type LeadLostReasonRepository interface {
+	// Create creates a new lost reason
+	Create(reason *model.LeadLostReason) error
+
+	// CreateDefaults creates the default lost reasons of a user, skipping the names it already has
+	CreateDefaults(userID uuid.UUID) error
+
+	// FindByID finds a lost reason by ID
+	FindByID(id uuid.UUID, userID uuid.UUID) (*model.LeadLostReason, error)
+
+	// FindAll finds all lost reasons for a user
+	FindAll(userID uuid.UUID) ([]model.LeadLostReason, error)
+
+	// NameTaken reports whether another lost reason of the user already has the name
+	NameTaken(userID uuid.UUID, name string, exceptID uuid.UUID) (bool, error)
+
+	// Update updates a lost reason
+	Update(reason *model.LeadLostReason) error
+
+	// Delete deletes a lost reason
+	Delete(id uuid.UUID, userID uuid.UUID) error
+
+	// CountLeads counts the leads classified with the reason
+	CountLeads(id uuid.UUID) (int64, error)
+}
+
+type LeadActivityRepository interface {
+	// CreateWithLead records an activity and saves the changes it caused on the lead in one transaction
+	CreateWithLead(activity *model.LeadActivity, lead *model.Lead) error
+
+	// List lists the activities of a lead, most recent first, filtered by type, outcome and date
+	List(leadID uuid.UUID, query ListQuery) (Page[model.LeadActivity], error)
+
+	// FindLastByLeadIDs finds the time of the most recent activity of each lead
+	FindLastByLeadIDs(leadIDs []uuid.UUID) (map[uuid.UUID]time.Time, error)
+}
@@ -0,0 +1,67 @@
+package port
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// LeadFunnelFilter restricts the funnel report to leads contacted in [StartDate, EndDate) and to the given origins
+type LeadFunnelFilter struct {
+	UserID    uuid.UUID
+	StartDate *time.Time
+	EndDate   *time.Time
+	Origins   []string
+}
+
+// LeadOriginFunnel summarizes the leads of one origin
+type LeadOriginFunnel struct {
+	Origin              string   `json:"origin"`
+	Leads               int64    `json:"leads"`
+	Converted           int64    `json:"converted"`
+	Lost                int64    `json:"lost"`
+	AvgDaysToConversion *float64 `json:"avg_days_to_conversion"`
+	Revenue             int64    `json:"revenue"` // Cents
+}
+
+// LeadConversionTime summarizes the days between ContactDate and ConvertedAt of converted leads
+type LeadConversionTime struct {
+	Conversions int64    `json:"conversions"`
+	AvgDays     *float64 `json:"avg_days"`
+	MedianDays  *float64 `json:"median_days"`
+	MinDays     *float64 `json:"min_days"`
+	MaxDays     *float64 `json:"max_days"`
+}
+
+// LeadStageFunnel measures how many leads reached a pipeline stage and how many were lost from it
+type LeadStageFunnel struct {
+	StageID  uuid.UUID `json:"stage_id"`
+	Name     string    `json:"name"`
+	Position int       `json:"position"`
+	Status   string    `json:"status"`
+	Reached  int64     `json:"reached"`
+	Current  int64     `json:"current"`
+	LostFrom int64     `json:"lost_from"`
+}
+
+// LeadMonthlyCohort summarizes the leads first contacted in a month
+type LeadMonthlyCohort struct {
+	Month     string `json:"month"` // YYYY-MM
+	Leads     int64  `json:"leads"`
+	Converted int64  `json:"converted"`
+	Lost      int64  `json:"lost"`
+	Revenue   int64  `json:"revenue"` // Cents
+}
+
+type LeadReportRepository interface {
+	// FunnelByOrigin counts leads, conversions, losses and revenue per origin
+	FunnelByOrigin(filter LeadFunnelFilter) ([]LeadOriginFunnel, error)
+
+	// ConversionTime computes the time to conversion statistics
+	ConversionTime(filter LeadFunnelFilter) (*LeadConversionTime, error)
+
+	// StageFunnel measures reach and drop-off for each pipeline stage of the user
+	StageFunnel(filter LeadFunnelFilter) ([]LeadStageFunnel, error)
+
+	// MonthlyCohorts groups leads by the month of their contact date
+	MonthlyCohorts(filter LeadFunnelFilter) ([]LeadMonthlyCohort, error)
+}
@@ -1,11 +1,17 @@
 package port
 
 import (
+	"errors"
 	"github.com/LacirJR/psygrow-api/src/internal/core/model"
 	"github.com/google/uuid"
 	"time"
 )
 
+var (
+	ErrLeadAlreadyConverted = errors.New("lead already converted")
+	ErrLeadContactBlocked   = errors.New("lead blocked for contact")
+)
+
 type LeadRepository interface {
 	// Create creates a new lead
 	Create(lead *model.Lead) error
@@ -13,8 +19,8 @@ type LeadRepository interface {
 	// FindByID finds a lead by ID
 	FindByID(id uuid.UUID, userID uuid.UUID) (*model.Lead, error)
 
-	// FindAll finds all leads for a user
-	FindAll(userID uuid.UUID, limit, offset int) ([]model.Lead, error)
+	// List lists the leads of a user, filtered by status, origin, was_attended, stage_id and contact date
+	List(userID uuid.UUID, query ListQuery) (Page[model.Lead], error)
 
 	// Update updates a lead
 	Update(lead *model.Lead) error
@@ -22,21 +28,16 @@ type LeadRepository interface {
 	// Delete deletes a lead
 	Delete(id uuid.UUID, userID uuid.UUID) error
 
-	// ConvertToPatient converts a lead to a patient
-	ConvertToPatient(leadID uuid.UUID, userID uuid.UUID, costCenterID uuid.UUID) (*model.Patient, error)
-
-	// FindByStatus finds leads by status
-	FindByStatus(userID uuid.UUID, status string, limit, offset int) ([]model.Lead, error)
-
-	// FindByOrigin finds leads by origin
-	FindByOrigin(userID uuid.UUID, origin string, limit, offset int) ([]model.Lead, error)
+	// ConvertToPatient creates the patient with their first episode of care (and optionally the first appointment) and
+	// marks the lead as converted in one transaction. It fails with ErrLeadAlreadyConverted or ErrLeadContactBlocked.
+	ConvertToPatient(leadID uuid.UUID, userID uuid.UUID, patient *model.Patient, appointment *model.Appointment) error
 
-	// FindByContactDate finds leads by contact date range
-	FindByContactDate(userID uuid.UUID, startDate, endDate time.Time, limit, offset int) ([]model.Lead, error)
+	// FindByContact finds the most recent lead matching the email or the phone digits
+	FindByContact(userID uuid.UUID, email string, phoneDigits string) (*model.Lead, error)
 
-	// FindByWasAttended finds leads by was_attended flag
-	FindByWasAttended(userID uuid.UUID, wasAttended bool, limit, offset int) ([]model.Lead, error)
+	// ListDueFollowUps lists open leads whose next follow-up is due until the given time, oldest first
+	ListDueFollowUps(userID uuid.UUID, until time.Time, query ListQuery) (Page[model.Lead], error)
 
-	// Count counts all leads for a user
-	Count(userID uuid.UUID) (int64, error)
+	// CreateWithConsent creates a lead and the consent given when it was captured in one transaction
+	CreateWithConsent(lead *model.Lead, consent *model.LeadConsent) error
 }
@@ -0,0 +1,38 @@
+package port
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSort   = errors.New("invalid sort field")
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+// Sort orders accepted by ListQuery
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// ListQuery describes the page requested from a list method. Each list declares which sort
+// fields and filters it accepts and which date column the From/To range applies to.
+type ListQuery struct {
+	Cursor    string // Opaque cursor returned as NextCursor by the previous page
+	Limit     int
+	SortBy    string // Empty for the list's default sort
+	SortOrder string // SortAsc, SortDesc or empty for the list's default order
+	From      *time.Time
+	To        *time.Time        // Exclusive
+	Filters   map[string]string // Raw values by filter name; names unknown to the list are ignored
+}
+
+// Page is one page of a list, with the cursor of the next page (empty on the last one)
+// and the number of rows matching the filters across all pages
+type Page[T any] struct {
+	Data       []T
+	NextCursor string
+	Total      int64
+}
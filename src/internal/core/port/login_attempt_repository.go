@@ -0,0 +1,27 @@
+package port
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+)
+
+type LoginAttemptRepository interface {
+	// Save records a failed login attempt
+	Save(attempt *model.LoginAttempt) error
+
+	// List lists the failed attempts against a user's account, most recent first, filtered by reason and date
+	List(userID uuid.UUID, query ListQuery) (Page[model.LoginAttempt], error)
+}
+
+// LoginThrottleStore keeps the failure counters used to throttle logins.
+// Implementations must apply Update atomically per key.
+type LoginThrottleStore interface {
+	// Get returns the state of the key, or nil when there is none
+	Get(key string) (*model.LoginThrottle, error)
+
+	// Update applies fn to the state of the key (a zero state when absent) and stores the result
+	Update(key string, fn func(state *model.LoginThrottle)) (*model.LoginThrottle, error)
+
+	// Delete clears the state of the key
+	Delete(key string) error
+}
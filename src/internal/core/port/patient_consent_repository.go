@@ -0,0 +1,25 @@
+package port
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PatientConsentRepository interface {
+	// Create creates a new patient consent
+	Create(consent *model.PatientConsent) error
+
+	// FindByID finds a consent of a patient by ID
+	FindByID(id uuid.UUID, patientID uuid.UUID) (*model.PatientConsent, error)
+
+	// FindByPatient finds all consents of a patient, most recently signed first
+	FindByPatient(patientID uuid.UUID) ([]model.PatientConsent, error)
+
+	// Update updates a patient consent
+	Update(consent *model.PatientConsent) error
+
+	// FindValidByGuardian finds a consent of the given type valid at the given time and signed by
+	// a family member who is still legal responsible for the patient
+	FindValidByGuardian(patientID uuid.UUID, consentType string, at time.Time) (*model.PatientConsent, error)
+}
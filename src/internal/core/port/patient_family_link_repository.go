@@ -0,0 +1,20 @@
+package port
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+)
+
+type PatientFamilyLinkRepository interface {
+	// Create creates a new link between two people of a patient's family tree
+	Create(link *model.PatientFamilyLink) error
+
+	// FindByID finds a link of a patient's family tree by ID
+	FindByID(id uuid.UUID, patientID uuid.UUID) (*model.PatientFamilyLink, error)
+
+	// FindByPatient finds all links of a patient's family tree
+	FindByPatient(patientID uuid.UUID) ([]model.PatientFamilyLink, error)
+
+	// Delete deletes a link
+	Delete(id uuid.UUID) error
+}
@@ -18,9 +18,12 @@ type PatientFamilyRepository interface {
 	// Update updates a patient family member
 	Update(patientFamily *model.PatientFamily) error
 
-	// Delete deletes a patient family member
+	// Delete deletes a patient family member along with the links of the family tree to them
 	Delete(id uuid.UUID) error
 
 	// FindByRelationship finds family members by relationship type
 	FindByRelationship(patientID uuid.UUID, relationship string) ([]model.PatientFamily, error)
+
+	// FindFinancialResponsibles finds the family members financially responsible for a patient
+	FindFinancialResponsibles(patientID uuid.UUID) ([]model.PatientFamily, error)
 }
@@ -3,8 +3,24 @@ package port
 import (
 	"github.com/LacirJR/psygrow-api/src/internal/core/model"
 	"github.com/google/uuid"
+	"time"
 )
 
+// PatientSearchFilter combines the criteria of a patient search. Nil or empty fields are ignored.
+type PatientSearchFilter struct {
+	UserID            uuid.UUID
+	Query             string // Part of the name or social name, accent and case insensitive
+	Document          string // CPF digits
+	Phone             string // E.164 number, or the trailing digits of a number
+	IsActive          *bool
+	CostCenterID      *uuid.UUID
+	BornBefore        *time.Time // Born on or before this date (minimum age)
+	BornAfter         *time.Time // Born after this date (maximum age)
+	LastSessionBefore *time.Time
+	LastSessionAfter  *time.Time
+	HasOpenBalance    *bool // Has done appointments not linked to any payment
+}
+
 type PatientRepository interface {
 	// Create creates a new patient
 	Create(patient *model.Patient) error
@@ -12,8 +28,8 @@ type PatientRepository interface {
 	// FindByID finds a patient by ID
 	FindByID(id uuid.UUID, userID uuid.UUID) (*model.Patient, error)
 
-	// FindAll finds all patients for a user
-	FindAll(userID uuid.UUID, limit, offset int) ([]model.Patient, error)
+	// List lists the patients of a user, filtered by active, cost_center_id and creation date
+	List(userID uuid.UUID, query ListQuery) (Page[model.Patient], error)
 
 	// Update updates a patient
 	Update(patient *model.Patient) error
@@ -21,27 +37,23 @@ type PatientRepository interface {
 	// Delete deletes a patient
 	Delete(id uuid.UUID, userID uuid.UUID) error
 
-	// FindByName finds patients by name (partial match)
-	FindByName(userID uuid.UUID, name string, limit, offset int) ([]model.Patient, error)
-
-	// FindByEmail finds a patient by email
-	FindByEmail(userID uuid.UUID, email string) (*model.Patient, error)
+	// FindByContact finds a patient matching the email or the phone digits
+	FindByContact(userID uuid.UUID, email string, phoneDigits string) (*model.Patient, error)
 
-	// FindByPhone finds a patient by phone
-	FindByPhone(userID uuid.UUID, phone string) (*model.Patient, error)
+	// FindAllByUser finds every patient of a user, without pagination
+	FindAllByUser(userID uuid.UUID) ([]model.Patient, error)
 
-	// FindByDocument finds a patient by document
-	FindByDocument(userID uuid.UUID, document string) (*model.Patient, error)
-
-	// FindByCostCenter finds patients by cost center
-	FindByCostCenter(userID uuid.UUID, costCenterID uuid.UUID, limit, offset int) ([]model.Patient, error)
-
-	// FindActive finds active patients
-	FindActive(userID uuid.UUID, limit, offset int) ([]model.Patient, error)
+	// Search finds a page of the patients matching all the filters, sorted by name, birth_date,
+	// created_at or last_session (patients never attended come last)
+	Search(filter PatientSearchFilter, query ListQuery) (Page[model.Patient], error)
+}
 
-	// FindInactive finds inactive patients
-	FindInactive(userID uuid.UUID, limit, offset int) ([]model.Patient, error)
+// PatientMergeRepository merges duplicate patients and keeps the audit of the merges
+type PatientMergeRepository interface {
+	// Merge moves every record of the duplicate patient to the survivor, fills the survivor's
+	// empty fields from the duplicate and removes it, all in one transaction
+	Merge(userID uuid.UUID, survivorID uuid.UUID, duplicateID uuid.UUID) (*model.PatientMerge, error)
 
-	// Count counts all patients for a user
-	Count(userID uuid.UUID) (int64, error)
+	// List lists the merges made by a user, most recent first, filtered by survivor_id and merge date
+	List(userID uuid.UUID, query ListQuery) (Page[model.PatientMerge], error)
 }
@@ -0,0 +1,31 @@
+package port
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+)
+
+type ScaleRepository interface {
+	// Create creates a scale with its items, options and bands
+	Create(scale *model.Scale) error
+
+	// FindAll finds all scales, without their items, options and bands
+	FindAll() ([]model.Scale, error)
+
+	// FindByID finds a scale with its items, options and bands, in order
+	FindByID(id uuid.UUID) (*model.Scale, error)
+
+	FindByCode(code string) (*model.Scale, error)
+}
+
+type ScaleApplicationRepository interface {
+	// Create creates an application with its answers in a single transaction
+	Create(application *model.ScaleApplication) error
+
+	// FindByID finds an application of a patient with its scale and answers
+	FindByID(id uuid.UUID, patientID uuid.UUID) (*model.ScaleApplication, error)
+
+	// FindByPatient finds the applications of a patient with their scale, most recent first.
+	// A nil scale ID finds the applications of every scale.
+	FindByPatient(patientID uuid.UUID, scaleID *uuid.UUID) ([]model.ScaleApplication, error)
+}
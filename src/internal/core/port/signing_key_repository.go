@@ -0,0 +1,17 @@
+package port
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"time"
+)
+
+type SigningKeyRepository interface {
+	// Save stores a new signing key
+	Save(key *model.SigningKey) error
+
+	// FindUsable finds the keys that have not expired at the given time, newest first
+	FindUsable(at time.Time) ([]model.SigningKey, error)
+
+	// Retire stops a key from signing and schedules its removal from verification
+	Retire(id string, retiredAt time.Time, expiresAt time.Time) error
+}
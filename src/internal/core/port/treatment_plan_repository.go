@@ -0,0 +1,51 @@
+package port
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTreatmentPlanOutdated is returned when a plan is revised from a version that is no
+	// longer the current one
+	ErrTreatmentPlanOutdated = errors.New("treatment plan was revised meanwhile")
+	// ErrTreatmentPlanActive is returned when a plan would become active while the patient
+	// already has an active plan
+	ErrTreatmentPlanActive = errors.New("patient already has an active treatment plan")
+)
+
+type TreatmentPlanRepository interface {
+	// Create creates a plan with its first version and goals in a single transaction. It fails
+	// with ErrTreatmentPlanActive when the patient already has an active plan.
+	Create(plan *model.TreatmentPlan, version *model.TreatmentPlanVersion) error
+
+	// FindByID finds a plan of a patient
+	FindByID(id uuid.UUID, patientID uuid.UUID) (*model.TreatmentPlan, error)
+
+	// FindByPatient finds the plans of a patient, most recent first
+	FindByPatient(patientID uuid.UUID) ([]model.TreatmentPlan, error)
+
+	// FindActiveByPatient finds the plan of a patient that is still active
+	FindActiveByPatient(patientID uuid.UUID) (*model.TreatmentPlan, error)
+
+	// FindVersion finds a version of a plan with its goals, in order
+	FindVersion(planID uuid.UUID, version int) (*model.TreatmentPlanVersion, error)
+
+	// FindVersions finds the versions of a plan with their goals, most recent first
+	FindVersions(planID uuid.UUID) ([]model.TreatmentPlanVersion, error)
+
+	// Revise creates a new version of a plan and makes it the current one. It fails with
+	// ErrTreatmentPlanOutdated when the plan was revised since it was read.
+	Revise(plan *model.TreatmentPlan, version *model.TreatmentPlanVersion) error
+
+	// UpdateStatus updates the status of a plan and when it ended. It fails with
+	// ErrTreatmentPlanActive when a plan is resumed while the patient has another active plan.
+	UpdateStatus(plan *model.TreatmentPlan) error
+
+	// FindProgressByPlan finds the progress ratings of a plan, oldest first
+	FindProgressByPlan(planID uuid.UUID) ([]model.TreatmentGoalProgress, error)
+
+	// FindProgressByEvolution finds the progress ratings of an evolution
+	FindProgressByEvolution(evolutionID uuid.UUID) ([]model.TreatmentGoalProgress, error)
+}
@@ -0,0 +1,37 @@
+package port
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+)
+
+type RecoveryCodeRepository interface {
+	// ReplaceForUser deletes every recovery code of the user and stores the new ones
+	ReplaceForUser(userID uuid.UUID, codes []*model.UserRecoveryCode) error
+
+	// Consume marks an unused code matching the hash as used, returning false when none matches
+	Consume(userID uuid.UUID, codeHash string) (bool, error)
+
+	// CountUnused counts the recovery codes still available to the user
+	CountUnused(userID uuid.UUID) (int64, error)
+
+	// DeleteForUser removes every recovery code of the user
+	DeleteForUser(userID uuid.UUID) error
+}
+
+type TwoFactorPolicyRepository interface {
+	Save(policy *model.TwoFactorPolicy) error
+	FindAll() ([]model.TwoFactorPolicy, error)
+	Delete(id uuid.UUID) error
+
+	// IsRequiredFor reports whether any policy makes 2FA mandatory for the user
+	IsRequiredFor(user *model.User) (bool, error)
+}
+
+type OrganizationRepository interface {
+	Save(organization *model.Organization) error
+	FindByID(id uuid.UUID) (*model.Organization, error)
+	FindBySlug(slug string) (*model.Organization, error)
+	FindAll() ([]model.Organization, error)
+	Update(organization *model.Organization) error
+}
@@ -1,8 +1,42 @@
 package port
 
-import "github.com/LacirJR/psygrow-api/src/internal/core/model"
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/google/uuid"
+)
+
+// UserFilter narrows user listings; nil or empty fields are ignored
+type UserFilter struct {
+	Role           *string
+	IsActive       *bool
+	OrganizationID *uuid.UUID
+	Search         string // Partial match on name or email
+}
 
 type UserRepository interface {
+	// Save creates the user and its default lead pipeline in a single transaction
 	Save(user *model.User) error
 	FindByEmail(email string) (*model.User, error)
+	FindByID(id uuid.UUID) (*model.User, error)
+	// FindAll finds every user matching the filter, without pagination
+	FindAll(filter UserFilter) ([]model.User, error)
+
+	// List lists the users matching the filter, sorted by name (default), email, created_at or last_login_at
+	List(filter UserFilter, query ListQuery) (Page[model.User], error)
+	Update(user *model.User) error
+
+	// ConsumeTwoFactorStep records a TOTP time step as used, unless the user already used it or a
+	// later one. It reports whether the step was recorded, so each code is accepted only once.
+	ConsumeTwoFactorStep(userID uuid.UUID, step int64) (bool, error)
+}
+
+type UserInviteRepository interface {
+	// SaveWithUser creates the invited user, its default lead pipeline and the invite in a single transaction
+	SaveWithUser(user *model.User, invite *model.UserInvite) error
+
+	// FindPendingByTokenHash finds an invite not yet accepted by its token hash
+	FindPendingByTokenHash(tokenHash string) (*model.UserInvite, error)
+
+	// Accept marks the invite as accepted and saves the activated user in a single transaction
+	Accept(invite *model.UserInvite, user *model.User) error
 }
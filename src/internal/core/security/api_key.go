@@ -0,0 +1,44 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+)
+
+// TokenTypeAPIKey identifies requests authenticated with an API key instead of a JWT
+const TokenTypeAPIKey = "api_key"
+
+// apiKeyScheme starts every API key so leaked keys are easy to recognize
+const apiKeyScheme = "psg"
+
+// GenerateAPIKey returns a new API key in the form psg_<prefix>_<secret> and its prefix.
+// The prefix is stored in clear text for lookup and display; the full key is shown only once.
+func GenerateAPIKey() (key string, prefix string, err error) {
+	raw := make([]byte, 4)
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	prefix = hex.EncodeToString(raw)
+
+	secret, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+	return apiKeyScheme + "_" + prefix + "_" + secret, prefix, nil
+}
+
+// APIKeyPrefix extracts the prefix from an API key, reporting false if the key is malformed
+func APIKeyPrefix(key string) (string, bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyScheme || len(parts[1]) != 8 || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// VerifyAPIKey compares a presented key with the stored hash in constant time
+func VerifyAPIKey(key string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashOpaqueToken(key)), []byte(hash)) == 1
+}
@@ -0,0 +1,25 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAndVerifyAPIKey(t *testing.T) {
+	key, prefix, err := GenerateAPIKey()
+	assert.NoError(t, err)
+
+	parsed, ok := APIKeyPrefix(key)
+	assert.True(t, ok)
+	assert.Equal(t, prefix, parsed)
+
+	hash := HashOpaqueToken(key)
+	assert.True(t, VerifyAPIKey(key, hash))
+	assert.False(t, VerifyAPIKey(key+"x", hash))
+
+	for _, malformed := range []string{"", "psg_", "abc_12345678_secret", "psg_123_secret", "psg_12345678_"} {
+		_, ok := APIKeyPrefix(malformed)
+		assert.False(t, ok, malformed)
+	}
+}
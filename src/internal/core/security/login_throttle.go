@@ -0,0 +1,155 @@
+package security
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"strings"
+	"time"
+)
+
+// LoginThrottleRule configures the backoff and lockout applied to one kind of key
+type LoginThrottleRule struct {
+	FreeFailures    int           // Failures tolerated before any delay is imposed
+	BaseDelay       time.Duration // Delay after the first failure beyond FreeFailures, doubled at each new failure
+	MaxDelay        time.Duration // Upper bound of the exponential delay
+	LockoutAfter    int           // Failures that trigger a temporary lockout
+	LockoutDuration time.Duration
+	ResetAfter      time.Duration // Failures older than this are forgotten
+}
+
+// DefaultEmailThrottleRule protects a single account against password guessing
+var DefaultEmailThrottleRule = LoginThrottleRule{
+	FreeFailures:    3,
+	BaseDelay:       time.Second,
+	MaxDelay:        time.Minute * 5,
+	LockoutAfter:    10,
+	LockoutDuration: time.Minute * 15,
+	ResetAfter:      time.Hour,
+}
+
+// DefaultIPThrottleRule protects against one client spraying many accounts
+var DefaultIPThrottleRule = LoginThrottleRule{
+	FreeFailures:    20,
+	BaseDelay:       time.Second,
+	MaxDelay:        time.Minute * 5,
+	LockoutAfter:    100,
+	LockoutDuration: time.Minute * 30,
+	ResetAfter:      time.Hour,
+}
+
+// LoginThrottler tracks failed logins per email and per IP address
+type LoginThrottler struct {
+	store     port.LoginThrottleStore
+	emailRule LoginThrottleRule
+	ipRule    LoginThrottleRule
+}
+
+// NewLoginThrottler creates a new LoginThrottler
+func NewLoginThrottler(store port.LoginThrottleStore, emailRule, ipRule LoginThrottleRule) *LoginThrottler {
+	return &LoginThrottler{store: store, emailRule: emailRule, ipRule: ipRule}
+}
+
+// Attempt admits a login attempt and counts it as a failure for both the email and the IP
+// address, unless one of them is blocked. It returns how long the caller must wait before trying
+// again, or zero when the attempt is admitted. Counting before the credentials are checked makes
+// parallel attempts see each other; attempts that turn out valid are given back by Release or
+// RegisterSuccess.
+func (t *LoginThrottler) Attempt(email, ip string, now time.Time) (time.Duration, error) {
+	emailKey := emailThrottleKey(email)
+	wait, err := t.attempt(emailKey, t.emailRule, now)
+	if err != nil || wait > 0 {
+		return wait, err
+	}
+
+	wait, err = t.attempt(ipThrottleKey(ip), t.ipRule, now)
+	if err != nil || wait > 0 {
+		// The refused attempt must not count against the email
+		if _, releaseErr := t.store.Update(emailKey, t.emailRule.release); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+		return wait, err
+	}
+	return 0, nil
+}
+
+// Release gives back the attempt of a login that did not fail, such as a password accepted while
+// the second factor is still pending
+func (t *LoginThrottler) Release(email, ip string) error {
+	if _, err := t.store.Update(emailThrottleKey(email), t.emailRule.release); err != nil {
+		return err
+	}
+	_, err := t.store.Update(ipThrottleKey(ip), t.ipRule.release)
+	return err
+}
+
+// RegisterSuccess clears the counters of the email and gives back the attempt to the IP address.
+// The other IP counters are kept so a valid account cannot be used to reset the limit of a client
+// spraying other accounts.
+func (t *LoginThrottler) RegisterSuccess(email, ip string) error {
+	if err := t.store.Delete(emailThrottleKey(email)); err != nil {
+		return err
+	}
+	_, err := t.store.Update(ipThrottleKey(ip), t.ipRule.release)
+	return err
+}
+
+// attempt checks and counts an attempt against a key in a single store update
+func (t *LoginThrottler) attempt(key string, rule LoginThrottleRule, now time.Time) (time.Duration, error) {
+	var wait time.Duration
+	_, err := t.store.Update(key, func(state *model.LoginThrottle) {
+		if state.BlockedUntil != nil && now.Before(*state.BlockedUntil) {
+			wait = state.BlockedUntil.Sub(now)
+			return
+		}
+		rule.apply(now)(state)
+	})
+	return wait, err
+}
+
+func (r LoginThrottleRule) apply(now time.Time) func(state *model.LoginThrottle) {
+	return func(state *model.LoginThrottle) {
+		stillBlocked := state.BlockedUntil != nil && now.Before(*state.BlockedUntil)
+		if !stillBlocked && now.Sub(state.LastFailureAt) > r.ResetAfter {
+			state.Failures = 0
+		}
+
+		state.Failures++
+		state.LastFailureAt = now
+
+		var blockFor time.Duration
+		switch {
+		case state.Failures >= r.LockoutAfter:
+			blockFor = r.LockoutDuration
+		case state.Failures > r.FreeFailures:
+			blockFor = r.BaseDelay << uint(state.Failures-r.FreeFailures-1)
+			if blockFor > r.MaxDelay || blockFor <= 0 {
+				blockFor = r.MaxDelay
+			}
+		default:
+			state.BlockedUntil = nil
+			return
+		}
+
+		blockedUntil := now.Add(blockFor)
+		state.BlockedUntil = &blockedUntil
+	}
+}
+
+// release undoes the count of an attempt, lifting the delay when it falls back within the free
+// failures
+func (r LoginThrottleRule) release(state *model.LoginThrottle) {
+	if state.Failures > 0 {
+		state.Failures--
+	}
+	if state.Failures <= r.FreeFailures {
+		state.BlockedUntil = nil
+	}
+}
+
+func emailThrottleKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,126 @@
+package security
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryThrottleStore is an in-memory port.LoginThrottleStore used to test the throttler
+type memoryThrottleStore struct {
+	mu     sync.Mutex
+	states map[string]model.LoginThrottle
+}
+
+func newMemoryThrottleStore() *memoryThrottleStore {
+	return &memoryThrottleStore{states: make(map[string]model.LoginThrottle)}
+}
+
+func (m *memoryThrottleStore) Get(key string) (*model.LoginThrottle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[key]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+func (m *memoryThrottleStore) Update(key string, fn func(state *model.LoginThrottle)) (*model.LoginThrottle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[key]
+	if !ok {
+		state = model.LoginThrottle{Key: key}
+	}
+	fn(&state)
+	m.states[key] = state
+	return &state, nil
+}
+
+func (m *memoryThrottleStore) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.states, key)
+	return nil
+}
+
+var testThrottleRule = LoginThrottleRule{
+	FreeFailures:    2,
+	BaseDelay:       time.Second,
+	MaxDelay:        time.Second * 4,
+	LockoutAfter:    6,
+	LockoutDuration: time.Minute,
+	ResetAfter:      time.Hour,
+}
+
+func TestLoginThrottlerBackoffAndLockout(t *testing.T) {
+	throttler := NewLoginThrottler(newMemoryThrottleStore(), testThrottleRule, DefaultIPThrottleRule)
+	now := time.Now()
+
+	// The free failures are admitted right away
+	for i := 0; i < testThrottleRule.FreeFailures; i++ {
+		wait, err := throttler.Attempt("User@Example.com", "10.0.0.1", now)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), wait)
+	}
+
+	// Each further failure is admitted once the previous delay has passed, and delays the next one
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, time.Minute}
+	for _, delay := range expected {
+		wait, err := throttler.Attempt("user@example.com", "10.0.0.1", now)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), wait)
+
+		wait, err = throttler.Attempt("user@example.com", "10.0.0.1", now)
+		assert.NoError(t, err)
+		assert.Equal(t, delay, wait)
+		now = now.Add(delay)
+	}
+
+	assert.NoError(t, throttler.RegisterSuccess("user@example.com", "10.0.0.1"))
+	wait, _ := throttler.Attempt("user@example.com", "10.0.0.1", now)
+	assert.Equal(t, time.Duration(0), wait)
+}
+
+func TestLoginThrottlerParallelAttempts(t *testing.T) {
+	throttler := NewLoginThrottler(newMemoryThrottleStore(), testThrottleRule, DefaultIPThrottleRule)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	admitted := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait, err := throttler.Attempt("user@example.com", "10.0.0.1", now)
+			assert.NoError(t, err)
+			if wait == 0 {
+				mu.Lock()
+				admitted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Only the free failures and the one that triggers the delay get through
+	assert.Equal(t, testThrottleRule.FreeFailures+1, admitted)
+}
+
+func TestLoginThrottlerRelease(t *testing.T) {
+	throttler := NewLoginThrottler(newMemoryThrottleStore(), testThrottleRule, DefaultIPThrottleRule)
+	now := time.Now()
+
+	// Attempts given back, such as passwords accepted pending 2FA, never build up a delay
+	for i := 0; i < 5; i++ {
+		wait, err := throttler.Attempt("user@example.com", "10.0.0.1", now)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), wait)
+		assert.NoError(t, throttler.Release("user@example.com", "10.0.0.1"))
+	}
+}
@@ -0,0 +1,83 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// encryptedSecretPrefix marks secrets encrypted by EncryptSecret. Secrets stored before
+// encryption at rest have no prefix.
+const encryptedSecretPrefix = "enc:v1:"
+
+var (
+	ErrSecretKeyMissing   = errors.New("secret encryption key not configured")
+	ErrMalformedSecretBox = errors.New("malformed encrypted secret")
+)
+
+// SecretKey derives the AES-256 key used to encrypt secrets at rest from a configured passphrase
+func SecretKey(passphrase string) []byte {
+	if passphrase == "" {
+		return nil
+	}
+	key := sha256.Sum256([]byte(passphrase))
+	return key[:]
+}
+
+// IsEncryptedSecret reports whether a stored secret was encrypted by EncryptSecret
+func IsEncryptedSecret(stored string) bool {
+	return strings.HasPrefix(stored, encryptedSecretPrefix)
+}
+
+// EncryptSecret encrypts a secret with AES-256-GCM for storage
+func EncryptSecret(plain string, key []byte) (string, error) {
+	gcm, err := secretCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return encryptedSecretPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret decrypts a secret stored by EncryptSecret. Secrets stored before encryption at
+// rest are returned as they are.
+func DecryptSecret(stored string, key []byte) (string, error) {
+	if !IsEncryptedSecret(stored) {
+		return stored, nil
+	}
+
+	gcm, err := secretCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(stored, encryptedSecretPrefix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", ErrMalformedSecretBox
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrMalformedSecretBox
+	}
+	return string(plain), nil
+}
+
+func secretCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, ErrSecretKeyMissing
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,34 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptSecret(t *testing.T) {
+	key := SecretKey("chave de teste")
+	secret, err := GenerateTOTPSecret()
+	assert.NoError(t, err)
+
+	stored, err := EncryptSecret(secret, key)
+	assert.NoError(t, err)
+	assert.True(t, IsEncryptedSecret(stored))
+	assert.NotContains(t, stored, secret)
+
+	plain, err := DecryptSecret(stored, key)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, plain)
+
+	// Another key cannot open the secret
+	_, err = DecryptSecret(stored, SecretKey("outra chave"))
+	assert.ErrorIs(t, err, ErrMalformedSecretBox)
+
+	// Secrets stored before encryption at rest are read as they are
+	plain, err = DecryptSecret(secret, key)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, plain)
+
+	_, err = EncryptSecret(secret, nil)
+	assert.ErrorIs(t, err, ErrSecretKeyMissing)
+}
@@ -0,0 +1,41 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// Token types carried in the "typ" claim of the JWTs issued by the API
+const (
+	// TokenTypeAccess grants access to the protected API
+	TokenTypeAccess = "access"
+	// TokenTypeTwoFactorChallenge is issued after a valid password when the user must still present a TOTP code
+	TokenTypeTwoFactorChallenge = "2fa_challenge"
+	// TokenTypeTwoFactorEnrollment is issued when a policy requires 2FA and the user has not enrolled yet
+	TokenTypeTwoFactorEnrollment = "2fa_enrollment"
+)
+
+const (
+	AccessTokenTTL    = time.Hour * 24
+	ChallengeTokenTTL = time.Minute * 5
+	InviteTokenTTL    = time.Hour * 72
+	AnamneseLinkTTL   = time.Hour * 72
+)
+
+// GenerateOpaqueToken returns a random URL-safe token for invites, links and similar single-purpose secrets
+func GenerateOpaqueToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// HashOpaqueToken hashes an opaque token for storage and lookup
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,466 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Signing algorithms supported by the token service
+const (
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmEdDSA = "EdDSA"
+)
+
+const (
+	DefaultKeyRotationInterval = time.Hour * 24 * 30
+	// keyRetirementGrace keeps a retired key verifiable a little longer than the longest token it signed
+	keyRetirementGrace = time.Minute * 5
+	rsaKeyBits         = 2048
+	// unknownKeyReloadInterval limits how often a token with an unknown key triggers a keyring reload
+	unknownKeyReloadInterval = time.Second * 30
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrUnknownSigningKey    = errors.New("unknown signing key")
+	ErrUnexpectedTokenType  = errors.New("unexpected token type")
+	ErrLegacyUntilRequired  = errors.New("legacy secret requires a cutoff for legacy tokens")
+)
+
+// TokenServiceConfig configures how tokens are signed and how often keys rotate
+type TokenServiceConfig struct {
+	Algorithm        string
+	Issuer           string
+	RotationInterval time.Duration
+	// MaxTokenTTL is the longest lifetime of a token signed by the service; retired keys stay verifiable that long
+	MaxTokenTTL time.Duration
+	// LegacySecret, when set, still accepts HS256 access tokens issued before the move to asymmetric keys
+	LegacySecret []byte
+	// LegacyUntil is when HS256 tokens stop being accepted; required with LegacySecret so that
+	// restarts do not extend it
+	LegacyUntil time.Time
+}
+
+// TokenClaims are the claims the API reads from a verified token
+type TokenClaims struct {
+	Subject  string
+	Role     string
+	Type     string
+	IssuedAt time.Time // Zero for tokens without an "iat" claim
+}
+
+// JWK is the public part of a signing key in RFC 7517 format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type loadedKey struct {
+	record  model.SigningKey
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// TokenService signs and verifies JWTs with a rotating set of asymmetric keys
+type TokenService struct {
+	store port.SigningKeyRepository
+	cfg   TokenServiceConfig
+
+	mu         sync.RWMutex
+	keys       map[string]*loadedKey
+	current    *loadedKey
+	reloadedAt time.Time
+}
+
+// NewTokenService loads the usable keys from the store, creating the first one if none exists
+func NewTokenService(store port.SigningKeyRepository, cfg TokenServiceConfig) (*TokenService, error) {
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = SigningAlgorithmRS256
+	}
+	if cfg.Algorithm != SigningAlgorithmRS256 && cfg.Algorithm != SigningAlgorithmEdDSA {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, cfg.Algorithm)
+	}
+	if cfg.RotationInterval <= 0 {
+		cfg.RotationInterval = DefaultKeyRotationInterval
+	}
+	if cfg.MaxTokenTTL <= 0 {
+		cfg.MaxTokenTTL = AccessTokenTTL
+	}
+	if len(cfg.LegacySecret) > 0 && cfg.LegacyUntil.IsZero() {
+		return nil, ErrLegacyUntilRequired
+	}
+
+	s := &TokenService{store: store, cfg: cfg}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	needsKey := s.current == nil || s.current.record.Algorithm != cfg.Algorithm
+	s.mu.RUnlock()
+	if needsKey {
+		if err := s.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Reload refreshes the in-memory keyring from the store, picking up keys rotated by other instances
+func (s *TokenService) Reload() error {
+	records, err := s.store.FindUsable(time.Now())
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*loadedKey, len(records))
+	var current *loadedKey
+	for _, record := range records {
+		key, err := decodeSigningKey(record)
+		if err != nil {
+			log.Printf("Aviso: chave de assinatura %s ignorada: %v", record.ID, err)
+			continue
+		}
+		keys[record.ID] = key
+		if record.RetiredAt == nil && (current == nil || record.CreatedAt.After(current.record.CreatedAt)) {
+			current = key
+		}
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.current = current
+	s.reloadedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// Rotate creates a new signing key and retires the current one, which keeps verifying until its tokens expire
+func (s *TokenService) Rotate() error {
+	record, err := generateSigningKey(s.cfg.Algorithm)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Save(record); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.MaxTokenTTL + keyRetirementGrace)
+	s.mu.RLock()
+	previous := make([]string, 0, len(s.keys))
+	for id, key := range s.keys {
+		if key.record.RetiredAt == nil {
+			previous = append(previous, id)
+		}
+	}
+	s.mu.RUnlock()
+	for _, id := range previous {
+		if err := s.store.Retire(id, now, expiresAt); err != nil {
+			return err
+		}
+	}
+
+	return s.Reload()
+}
+
+// RotateIfDue rotates the current key once it is older than the configured interval
+func (s *TokenService) RotateIfDue(now time.Time) error {
+	s.mu.RLock()
+	current := s.current
+	s.mu.RUnlock()
+
+	if current != nil && now.Sub(current.record.CreatedAt) < s.cfg.RotationInterval {
+		return nil
+	}
+	return s.Rotate()
+}
+
+// StartRotation periodically reloads the keyring and rotates the signing key when it is due
+func (s *TokenService) StartRotation(checkEvery time.Duration) {
+	go func() {
+		ticker := time.NewTicker(checkEvery)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.Reload(); err != nil {
+				log.Printf("Erro ao recarregar chaves de assinatura: %v", err)
+				continue
+			}
+			if err := s.RotateIfDue(time.Now()); err != nil {
+				log.Printf("Erro ao rotacionar chave de assinatura: %v", err)
+			}
+		}
+	}()
+}
+
+// Issue signs a token for the subject with the given role and type ("typ" claim), valid for ttl
+func (s *TokenService) Issue(subject uuid.UUID, role string, tokenType string, ttl time.Duration) (string, error) {
+	s.mu.RLock()
+	key := s.current
+	s.mu.RUnlock()
+	if key == nil {
+		return "", ErrUnknownSigningKey
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":  subject.String(),
+		"role": role,
+		"typ":  tokenType,
+		"iat":  now.Unix(),
+		"exp":  now.Add(ttl).Unix(),
+	}
+	if s.cfg.Issuer != "" {
+		claims["iss"] = s.cfg.Issuer
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.record.ID
+	return token.SignedString(key.private)
+}
+
+// Parse verifies a token against the keyring and returns its claims.
+// Tokens issued before the "typ" claim existed are treated as access tokens.
+func (s *TokenService) Parse(tokenString string) (*TokenClaims, error) {
+	methods := []string{SigningAlgorithmRS256, SigningAlgorithmEdDSA}
+	if s.acceptsLegacy(time.Now()) {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	token, err := jwt.Parse(tokenString, s.verificationKey, jwt.WithValidMethods(methods), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token claims")
+	}
+
+	// Legacy tokens were issued without an issuer; every token signed by the keyring must carry it
+	_, legacy := token.Method.(*jwt.SigningMethodHMAC)
+	if issuer, _ := claims.GetIssuer(); s.cfg.Issuer != "" && (!legacy || issuer != "") && issuer != s.cfg.Issuer {
+		return nil, jwt.ErrTokenInvalidIssuer
+	}
+
+	result := &TokenClaims{}
+	result.Subject, _ = claims["sub"].(string)
+	result.Role, _ = claims["role"].(string)
+	result.Type, _ = claims["typ"].(string)
+	if issuedAt, err := claims.GetIssuedAt(); err == nil && issuedAt != nil {
+		result.IssuedAt = issuedAt.Time
+	}
+	if result.Type == "" {
+		result.Type = TokenTypeAccess
+	}
+	if legacy && result.Type != TokenTypeAccess {
+		return nil, ErrUnexpectedTokenType
+	}
+	return result, nil
+}
+
+// ParseSubject verifies a token of the given type and returns its subject
+func (s *TokenService) ParseSubject(tokenString string, tokenType string) (uuid.UUID, error) {
+	claims, err := s.Parse(tokenString)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if claims.Type != tokenType {
+		return uuid.Nil, ErrUnexpectedTokenType
+	}
+	return uuid.Parse(claims.Subject)
+}
+
+func (s *TokenService) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, hasKid := token.Header["kid"].(string)
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		// Legacy tokens never carried a key ID
+		if hasKid {
+			return nil, ErrUnknownSigningKey
+		}
+		return s.cfg.LegacySecret, nil
+	}
+
+	key := s.lookup(kid)
+	if key == nil && s.reloadDue(time.Now()) {
+		// The key may have been created by another instance since the last reload
+		if err := s.Reload(); err != nil {
+			return nil, err
+		}
+		key = s.lookup(kid)
+	}
+	if key == nil {
+		return nil, ErrUnknownSigningKey
+	}
+
+	if key.method.Alg() != token.Method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.public, nil
+}
+
+// acceptsLegacy reports whether HS256 tokens signed with the legacy secret are still accepted
+func (s *TokenService) acceptsLegacy(now time.Time) bool {
+	return len(s.cfg.LegacySecret) > 0 && now.Before(s.cfg.LegacyUntil)
+}
+
+// reloadDue rate-limits the reloads triggered by unknown key IDs, which anyone can send
+func (s *TokenService) reloadDue(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.reloadedAt) < unknownKeyReloadInterval {
+		return false
+	}
+	s.reloadedAt = now
+	return true
+}
+
+func (s *TokenService) lookup(kid string) *loadedKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys[kid]
+}
+
+// JWKS returns the public keys that currently verify tokens, including retired keys still in their grace period
+func (s *TokenService) JWKS() JWKSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	set := JWKSet{Keys: make([]JWK, 0, len(s.keys))}
+	for _, key := range s.keys {
+		jwk := JWK{Kid: key.record.ID, Use: "sig", Alg: key.method.Alg()}
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// generateSigningKey creates a key pair for the algorithm and encodes it for storage
+func generateSigningKey(algorithm string) (*model.SigningKey, error) {
+	var private crypto.Signer
+	var err error
+	switch algorithm {
+	case SigningAlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case SigningAlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	// The key ID is the truncated SHA-256 thumbprint of the public key
+	sum := sha256.Sum256(publicDER)
+	return &model.SigningKey{
+		ID:         base64.RawURLEncoding.EncodeToString(sum[:16]),
+		Algorithm:  algorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// decodeSigningKey parses a stored key back into usable crypto keys
+func decodeSigningKey(record model.SigningKey) (*loadedKey, error) {
+	privateBlock, _ := pem.Decode([]byte(record.PrivateKey))
+	if privateBlock == nil {
+		return nil, errors.New("invalid private key PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(privateBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key cannot sign")
+	}
+
+	key := &loadedKey{record: record, private: private, public: private.Public()}
+	switch record.Algorithm {
+	case SigningAlgorithmRS256:
+		if _, ok := private.(*rsa.PrivateKey); !ok {
+			return nil, errors.New("key is not an RSA key")
+		}
+		key.method = jwt.SigningMethodRS256
+	case SigningAlgorithmEdDSA:
+		if _, ok := private.(ed25519.PrivateKey); !ok {
+			return nil, errors.New("key is not an Ed25519 key")
+		}
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, record.Algorithm)
+	}
+	return key, nil
+}
+
+var (
+	tokenServiceMu sync.RWMutex
+	tokenService   *TokenService
+)
+
+// SetTokenService registers the service used by handlers and middleware
+func SetTokenService(service *TokenService) {
+	tokenServiceMu.Lock()
+	defer tokenServiceMu.Unlock()
+	tokenService = service
+}
+
+// Tokens returns the registered token service
+func Tokens() *TokenService {
+	tokenServiceMu.RLock()
+	defer tokenServiceMu.RUnlock()
+	if tokenService == nil {
+		log.Fatal("Serviço de tokens não inicializado")
+	}
+	return tokenService
+}
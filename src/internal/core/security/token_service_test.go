@@ -0,0 +1,163 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type memorySigningKeyStore struct {
+	keys  []model.SigningKey
+	loads int
+}
+
+func (m *memorySigningKeyStore) Save(key *model.SigningKey) error {
+	m.keys = append(m.keys, *key)
+	return nil
+}
+
+func (m *memorySigningKeyStore) FindUsable(at time.Time) ([]model.SigningKey, error) {
+	m.loads++
+	var usable []model.SigningKey
+	for _, key := range m.keys {
+		if key.ExpiresAt == nil || key.ExpiresAt.After(at) {
+			usable = append(usable, key)
+		}
+	}
+	return usable, nil
+}
+
+func (m *memorySigningKeyStore) Retire(id string, retiredAt time.Time, expiresAt time.Time) error {
+	for i := range m.keys {
+		if m.keys[i].ID == id && m.keys[i].RetiredAt == nil {
+			m.keys[i].RetiredAt = &retiredAt
+			m.keys[i].ExpiresAt = &expiresAt
+		}
+	}
+	return nil
+}
+
+func TestTokenServiceRotationKeepsOldTokensValid(t *testing.T) {
+	for _, algorithm := range []string{SigningAlgorithmRS256, SigningAlgorithmEdDSA} {
+		store := &memorySigningKeyStore{}
+		service, err := NewTokenService(store, TokenServiceConfig{Algorithm: algorithm, Issuer: "psygrow-test"})
+		assert.NoError(t, err)
+
+		subject := uuid.New()
+		oldToken, err := service.Issue(subject, "admin", TokenTypeAccess, time.Hour)
+		assert.NoError(t, err)
+
+		assert.NoError(t, service.Rotate())
+		newToken, err := service.Issue(subject, "admin", TokenTypeAccess, time.Hour)
+		assert.NoError(t, err)
+
+		for _, token := range []string{oldToken, newToken} {
+			claims, err := service.Parse(token)
+			assert.NoError(t, err)
+			assert.Equal(t, subject.String(), claims.Subject)
+			assert.Equal(t, TokenTypeAccess, claims.Type)
+		}
+
+		assert.Len(t, service.JWKS().Keys, 2)
+
+		_, err = service.ParseSubject(newToken, TokenTypeTwoFactorChallenge)
+		assert.ErrorIs(t, err, ErrUnexpectedTokenType)
+	}
+}
+
+func TestTokenServiceRejectsUnknownKeys(t *testing.T) {
+	issuer, err := NewTokenService(&memorySigningKeyStore{}, TokenServiceConfig{Algorithm: SigningAlgorithmEdDSA})
+	assert.NoError(t, err)
+	verifier, err := NewTokenService(&memorySigningKeyStore{}, TokenServiceConfig{Algorithm: SigningAlgorithmEdDSA})
+	assert.NoError(t, err)
+
+	token, err := issuer.Issue(uuid.New(), "admin", TokenTypeAccess, time.Hour)
+	assert.NoError(t, err)
+
+	_, err = verifier.Parse(token)
+	assert.Error(t, err)
+}
+
+func TestTokenServiceRateLimitsReloadsForUnknownKeys(t *testing.T) {
+	issuer, err := NewTokenService(&memorySigningKeyStore{}, TokenServiceConfig{Algorithm: SigningAlgorithmEdDSA})
+	assert.NoError(t, err)
+	store := &memorySigningKeyStore{}
+	verifier, err := NewTokenService(store, TokenServiceConfig{Algorithm: SigningAlgorithmEdDSA})
+	assert.NoError(t, err)
+
+	token, err := issuer.Issue(uuid.New(), "admin", TokenTypeAccess, time.Hour)
+	assert.NoError(t, err)
+
+	loads := store.loads
+	for i := 0; i < 5; i++ {
+		_, err = verifier.Parse(token)
+		assert.ErrorIs(t, err, ErrUnknownSigningKey)
+	}
+	assert.Equal(t, loads, store.loads)
+}
+
+func TestTokenServiceEnforcesIssuer(t *testing.T) {
+	store := &memorySigningKeyStore{}
+	issuer, err := NewTokenService(store, TokenServiceConfig{Algorithm: SigningAlgorithmEdDSA, Issuer: "other-api"})
+	assert.NoError(t, err)
+	verifier, err := NewTokenService(store, TokenServiceConfig{
+		Algorithm:    SigningAlgorithmEdDSA,
+		Issuer:       "psygrow-test",
+		LegacySecret: []byte("legacy"),
+		LegacyUntil:  time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	token, err := issuer.Issue(uuid.New(), "admin", TokenTypeAccess, time.Hour)
+	assert.NoError(t, err)
+
+	_, err = verifier.Parse(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+}
+
+func TestTokenServiceLegacyTokens(t *testing.T) {
+	secret := []byte("legacy")
+	legacyToken := func(claims jwt.MapClaims, kid string) string {
+		claims["sub"] = uuid.New().String()
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(secret)
+		assert.NoError(t, err)
+		return signed
+	}
+
+	_, err := NewTokenService(&memorySigningKeyStore{}, TokenServiceConfig{LegacySecret: secret})
+	assert.ErrorIs(t, err, ErrLegacyUntilRequired)
+
+	service, err := NewTokenService(&memorySigningKeyStore{}, TokenServiceConfig{
+		Issuer:       "psygrow-test",
+		LegacySecret: secret,
+		LegacyUntil:  time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	claims, err := service.Parse(legacyToken(jwt.MapClaims{"role": "admin"}, ""))
+	assert.NoError(t, err)
+	assert.Equal(t, TokenTypeAccess, claims.Type)
+
+	_, err = service.Parse(legacyToken(jwt.MapClaims{"typ": TokenTypeTwoFactorChallenge}, ""))
+	assert.ErrorIs(t, err, ErrUnexpectedTokenType)
+
+	_, err = service.Parse(legacyToken(jwt.MapClaims{}, "some-key"))
+	assert.Error(t, err)
+
+	expired, err := NewTokenService(&memorySigningKeyStore{}, TokenServiceConfig{
+		LegacySecret: secret,
+		LegacyUntil:  time.Now().Add(-time.Minute),
+	})
+	assert.NoError(t, err)
+	_, err = expired.Parse(legacyToken(jwt.MapClaims{}, ""))
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+}
@@ -0,0 +1,136 @@
+package validation
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidCPF   = errors.New("CPF inválido")
+	ErrInvalidPhone = errors.New("telefone inválido")
+	ErrInvalidCEP   = errors.New("CEP inválido")
+	ErrInvalidUF    = errors.New("UF inválida")
+)
+
+// Validator tags registered by Register
+const (
+	TagCPF     = "cpf"
+	TagBRPhone = "br_phone"
+	TagCEP     = "cep"
+	TagUF      = "uf"
+)
+
+var (
+	e164BRPattern = regexp.MustCompile(`^\+55[1-9]{2}(9\d{8}|[2-5]\d{7})$`)
+	cepPattern    = regexp.MustCompile(`^\d{5}-\d{3}$`)
+)
+
+// states holds the Brazilian federative units
+var states = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true,
+	"ES": true, "GO": true, "MA": true, "MT": true, "MS": true, "MG": true, "PA": true,
+	"PB": true, "PR": true, "PE": true, "PI": true, "RJ": true, "RN": true, "RS": true,
+	"RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+// Register adds the Brazilian document, phone and address validations to a validator.
+// Values are expected in their normalized form.
+func Register(v *validator.Validate) error {
+	validations := map[string]validator.Func{
+		TagCPF:     func(fl validator.FieldLevel) bool { return IsValidCPF(fl.Field().String()) },
+		TagBRPhone: func(fl validator.FieldLevel) bool { return e164BRPattern.MatchString(fl.Field().String()) },
+		TagCEP:     func(fl validator.FieldLevel) bool { return cepPattern.MatchString(fl.Field().String()) },
+		TagUF:      func(fl validator.FieldLevel) bool { return states[fl.Field().String()] },
+	}
+	for tag, fn := range validations {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsValidCPF checks the length and the check digits of a CPF, formatted or not
+func IsValidCPF(cpf string) bool {
+	d := digits(cpf)
+	if len(d) != 11 || strings.Count(d, d[:1]) == 11 {
+		return false
+	}
+
+	checkDigit := func(n int) byte {
+		sum := 0
+		for i := 0; i < n; i++ {
+			sum += int(d[i]-'0') * (n + 1 - i)
+		}
+		rest := sum * 10 % 11
+		if rest == 10 {
+			rest = 0
+		}
+		return byte('0' + rest)
+	}
+
+	return d[9] == checkDigit(9) && d[10] == checkDigit(10)
+}
+
+// NormalizeCPF validates a CPF and returns its 11 digits
+func NormalizeCPF(cpf string) (string, error) {
+	if !IsValidCPF(cpf) {
+		return "", ErrInvalidCPF
+	}
+	return digits(cpf), nil
+}
+
+// NormalizePhone validates a Brazilian landline or mobile number with area code,
+// with or without country code and trunk prefix, and returns it in E.164 (+55DDDNNNNNNNN)
+func NormalizePhone(phone string) (string, error) {
+	trimmed := strings.TrimSpace(phone)
+	d := digits(trimmed)
+
+	switch {
+	case strings.HasPrefix(trimmed, "+"):
+		if !strings.HasPrefix(d, "55") {
+			return "", ErrInvalidPhone
+		}
+		d = d[2:]
+	case strings.HasPrefix(d, "0"):
+		d = d[1:]
+	case (len(d) == 12 || len(d) == 13) && strings.HasPrefix(d, "55"):
+		d = d[2:]
+	}
+
+	normalized := "+55" + d
+	if !e164BRPattern.MatchString(normalized) {
+		return "", ErrInvalidPhone
+	}
+	return normalized, nil
+}
+
+// NormalizeCEP validates a CEP and returns it formatted as 00000-000
+func NormalizeCEP(cep string) (string, error) {
+	d := digits(cep)
+	if len(d) != 8 || d == "00000000" {
+		return "", ErrInvalidCEP
+	}
+	return d[:5] + "-" + d[5:], nil
+}
+
+// NormalizeUF validates a federative unit and returns it in upper case
+func NormalizeUF(uf string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(uf))
+	if !states[normalized] {
+		return "", ErrInvalidUF
+	}
+	return normalized, nil
+}
+
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeCPF(t *testing.T) {
+	cpf, err := NormalizeCPF("529.982.247-25")
+	assert.NoError(t, err)
+	assert.Equal(t, "52998224725", cpf)
+
+	for _, invalid := range []string{"529.982.247-26", "111.111.111-11", "1234", ""} {
+		_, err := NormalizeCPF(invalid)
+		assert.ErrorIs(t, err, ErrInvalidCPF, invalid)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	valid := map[string]string{
+		"(11) 99999-0000":     "+5511999990000",
+		"+55 11 99999-0000":   "+5511999990000",
+		"5511999990000":       "+5511999990000",
+		"011 3333-4444":       "+551133334444",
+		"21 2555-1234":        "+552125551234",
+		" +55 (48) 3222-1000": "+554832221000",
+	}
+	for input, expected := range valid {
+		phone, err := NormalizePhone(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, phone, input)
+	}
+
+	for _, invalid := range []string{"99999-0000", "+1 415 555 0100", "(11) 89999-0000", "(01) 99999-0000", "11 9999-000"} {
+		_, err := NormalizePhone(invalid)
+		assert.ErrorIs(t, err, ErrInvalidPhone, invalid)
+	}
+}
+
+func TestNormalizeCEPAndUF(t *testing.T) {
+	cep, err := NormalizeCEP("01001000")
+	assert.NoError(t, err)
+	assert.Equal(t, "01001-000", cep)
+
+	_, err = NormalizeCEP("0100-100")
+	assert.ErrorIs(t, err, ErrInvalidCEP)
+
+	uf, err := NormalizeUF(" sp ")
+	assert.NoError(t, err)
+	assert.Equal(t, "SP", uf)
+
+	_, err = NormalizeUF("XX")
+	assert.ErrorIs(t, err, ErrInvalidUF)
+}
@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"sort"
+	"strings"
+)
+
+// FieldErrors maps the JSON name of each invalid field to the reason it was rejected
+type FieldErrors map[string]string
+
+// Add records the error of a field, if any
+func (e FieldErrors) Add(field string, err error) {
+	if err != nil {
+		e[field] = err.Error()
+	}
+}
+
+// Error lists the invalid fields in a stable order
+func (e FieldErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field, message := range e {
+		fields = append(fields, field+": "+message)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, "; ")
+}
+
+// NormalizeOptional applies a normalizer to an optional value in place.
+// Empty values are cleared and invalid values are recorded under the field name.
+func (e FieldErrors) NormalizeOptional(field string, value **string, normalize func(string) (string, error)) {
+	if *value == nil {
+		return
+	}
+	if strings.TrimSpace(**value) == "" {
+		*value = nil
+		return
+	}
+
+	normalized, err := normalize(**value)
+	if err != nil {
+		e.Add(field, err)
+		return
+	}
+	*value = &normalized
+}
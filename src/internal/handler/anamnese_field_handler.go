@@ -1,13 +1,18 @@
 package handler
 
 import (
+	"errors"
 	"github.com/LacirJR/psygrow-api/src/internal/config"
 	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/anamnese"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
 	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
 	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -52,9 +57,14 @@ func CreateAnamneseField(c *gin.Context) {
 		return
 	}
 
+	// Fields are only added to the draft version
+	draft, ok := editableDraft(c, template.ID)
+	if !ok {
+		return
+	}
+
 	// Create field ID
 	fieldID := uuid.New()
-	parsedAnamneseID, _ := uuid.Parse(anamneseID)
 
 	// Create field model
 	field := &model.AnamneseField{
@@ -65,11 +75,18 @@ func CreateAnamneseField(c *gin.Context) {
 		FieldRequired: req.FieldRequired,
 		FieldActive:   true,
 		UserID:        userIDParsed,
-		AnamneseID:    parsedAnamneseID,
+		AnamneseID:    template.ID,
+		VersionID:     draft.ID,
+		FieldKey:      fieldID,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
+	versionFields, ok := applyFieldVisibility(c, field, req.Visibility)
+	if !ok {
+		return
+	}
+
 	// Save field
 	if err := fieldRepo.Save(field); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create field", "details": err.Error()})
@@ -83,10 +100,13 @@ func CreateAnamneseField(c *gin.Context) {
 		var fieldOptions []*model.AnamneseFieldOption
 
 		for _, item := range req.Options {
+			optionID := uuid.New()
 			option := &model.AnamneseFieldOption{
+				ID:              optionID,
 				AnamneseFieldID: fieldID,
 				OptionValue:     item.OptionValue,
 				OptionOrder:     item.OptionOrder,
+				OptionKey:       optionID,
 			}
 			fieldOptions = append(fieldOptions, option)
 		}
@@ -116,12 +136,15 @@ func CreateAnamneseField(c *gin.Context) {
 		"field_required": field.FieldRequired,
 		"field_active":   field.FieldActive,
 		"anamnese_id":    field.AnamneseID.String(),
+		"version_id":     field.VersionID.String(),
 		"user_id":        field.UserID.String(),
 		"options":        options,
+		"visibility":     fieldVisibility(field, versionFields),
 	})
 }
 
-// GetAnamneseFields returns all fields for a specific anamnese template
+// GetAnamneseFields returns the fields of a version of an anamnese template: the one given as
+// version_id, the draft being edited when draft=true, else the latest published one
 func GetAnamneseFields(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
 	userID, exists := c.Get("user_id")
@@ -158,8 +181,16 @@ func GetAnamneseFields(c *gin.Context) {
 		return
 	}
 
+	version, ok := fieldsVersion(c, template.ID.String())
+	if !ok {
+		return
+	}
+
 	// Get fields
-	fields, err := fieldRepo.FindByAnamneseID(anamneseID)
+	var fields []*model.AnamneseField
+	if version != nil {
+		fields, err = fieldRepo.FindByVersionID(version.ID.String())
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch fields", "details": err.Error()})
 		return
@@ -202,6 +233,14 @@ func GetAnamneseFields(c *gin.Context) {
 		}
 	}
 
+	// Fields that depend on each field, so that the front-end knows what to re-evaluate
+	dependents := make(map[uuid.UUID][]string)
+	for _, field := range fields {
+		if field.VisibleIfFieldKey != nil {
+			dependents[*field.VisibleIfFieldKey] = append(dependents[*field.VisibleIfFieldKey], field.ID.String())
+		}
+	}
+
 	// Convert to response format
 	response := make([]gin.H, len(fields))
 	for i, field := range fields {
@@ -228,9 +267,12 @@ func GetAnamneseFields(c *gin.Context) {
 			"field_required": field.FieldRequired,
 			"field_active":   field.FieldActive,
 			"anamnese_id":    field.AnamneseID.String(),
+			"version_id":     field.VersionID.String(),
 			"user_id":        field.UserID.String(),
 			"options":        options,
 			"filled_fields":  filledFields,
+			"visibility":     fieldVisibility(field, fields),
+			"dependents":     dependents[field.FieldKey],
 		}
 	}
 
@@ -277,6 +319,13 @@ func UpdateAnamneseField(c *gin.Context) {
 		return
 	}
 
+	// Published versions are frozen, the edit goes to the field's copy in the draft version
+	field, ok := draftField(c, field)
+	if !ok {
+		return
+	}
+	fieldID = field.ID.String()
+
 	// Update field
 	field.FieldNumber = req.FieldNumber
 	field.FieldType = req.FieldType
@@ -284,6 +333,11 @@ func UpdateAnamneseField(c *gin.Context) {
 	field.FieldRequired = req.FieldRequired
 	field.UpdatedAt = time.Now()
 
+	versionFields, ok := applyFieldVisibility(c, field, req.Visibility)
+	if !ok {
+		return
+	}
+
 	// Save updated field
 	if err := fieldRepo.Update(field); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update field", "details": err.Error()})
@@ -302,13 +356,15 @@ func UpdateAnamneseField(c *gin.Context) {
 
 		// Create new options
 		var fieldOptions []*model.AnamneseFieldOption
-		fieldIDParsed, _ := uuid.Parse(fieldID)
 
 		for _, item := range req.Options {
+			optionID := uuid.New()
 			option := &model.AnamneseFieldOption{
-				AnamneseFieldID: fieldIDParsed,
+				ID:              optionID,
+				AnamneseFieldID: field.ID,
 				OptionValue:     item.OptionValue,
 				OptionOrder:     item.OptionOrder,
+				OptionKey:       optionID,
 			}
 			fieldOptions = append(fieldOptions, option)
 		}
@@ -345,8 +401,10 @@ func UpdateAnamneseField(c *gin.Context) {
 		"field_required": field.FieldRequired,
 		"field_active":   field.FieldActive,
 		"anamnese_id":    field.AnamneseID.String(),
+		"version_id":     field.VersionID.String(),
 		"user_id":        field.UserID.String(),
 		"options":        options,
+		"visibility":     fieldVisibility(field, versionFields),
 	})
 }
 
@@ -384,6 +442,25 @@ func DeleteAnamneseField(c *gin.Context) {
 		return
 	}
 
+	// The field is only removed from the draft version, published versions keep it
+	field, ok := draftField(c, field)
+	if !ok {
+		return
+	}
+	fieldID = field.ID.String()
+
+	versionFields, err := fieldRepo.FindByVersionID(field.VersionID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch fields", "details": err.Error()})
+		return
+	}
+	for _, other := range versionFields {
+		if other.VisibleIfFieldKey != nil && *other.VisibleIfFieldKey == field.FieldKey {
+			c.JSON(http.StatusConflict, gin.H{"error": "Field is used by the visibility rule of another field", "field_id": other.ID.String()})
+			return
+		}
+	}
+
 	// Delete field options first
 	optionRepo := repository.NewAnamneseFieldOptionRepository(config.DB)
 	if err := optionRepo.DeleteByAnamneseFieldID(fieldID); err != nil {
@@ -399,3 +476,182 @@ func DeleteAnamneseField(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Field deleted successfully"})
 }
+
+// ReorderAnamneseFields numbers the fields of the draft version of a template in the listed
+// order, in a single operation. Every field of the draft must be listed once.
+func ReorderAnamneseFields(c *gin.Context) {
+	template, ok := findOwnTemplate(c, c.Param("template_id"))
+	if !ok {
+		return
+	}
+
+	var req dto.AnamneseFieldOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	draft, ok := editableDraft(c, template.ID)
+	if !ok {
+		return
+	}
+
+	fieldRepo := repository.NewAnamneseFieldRepository(config.DB)
+	draftFields, err := fieldRepo.FindByVersionID(draft.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch fields", "details": err.Error()})
+		return
+	}
+	byKey := make(map[uuid.UUID]*model.AnamneseField, len(draftFields))
+	for _, field := range draftFields {
+		byKey[field.FieldKey] = field
+	}
+
+	order := make([]uuid.UUID, 0, len(req.FieldIDs))
+	listed := make(map[uuid.UUID]bool, len(req.FieldIDs))
+	for _, fieldID := range req.FieldIDs {
+		// Fields of other versions are matched with their copy in the draft
+		field, err := fieldRepo.FindByID(fieldID)
+		var copied *model.AnamneseField
+		if err == nil && field.AnamneseID == template.ID {
+			copied = byKey[field.FieldKey]
+		}
+		if copied == nil || listed[copied.ID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or repeated field ID", "field_id": fieldID})
+			return
+		}
+		listed[copied.ID] = true
+		order = append(order, copied.ID)
+	}
+
+	if err := fieldRepo.Reorder(draft.ID, order); err != nil {
+		if errors.Is(err, port.ErrFieldOrderIncomplete) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Every field of the template must be listed"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder fields", "details": err.Error()})
+		return
+	}
+
+	fields := make([]gin.H, len(order))
+	for i, fieldID := range order {
+		fields[i] = gin.H{"id": fieldID.String(), "field_number": i + 1}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"version_id": draft.ID.String(), "fields": fields})
+}
+
+// fieldsVersion picks the version whose fields are listed: the one given as version_id, the
+// draft when draft=true is given, else the latest published. It is nil when the template has
+// no such version.
+func fieldsVersion(c *gin.Context, templateID string) (*model.AnamneseTemplateVersion, bool) {
+	versionRepo := repository.NewAnamneseTemplateVersionRepository(config.DB)
+
+	if versionID := c.Query("version_id"); versionID != "" {
+		version, err := versionRepo.FindByID(versionID)
+		if err != nil || version.TemplateID.String() != templateID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+			return nil, false
+		}
+		return version, true
+	}
+
+	draft := false
+	if raw := c.Query("draft"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft parameter"})
+			return nil, false
+		}
+		draft = parsed
+	}
+
+	var version *model.AnamneseTemplateVersion
+	var err error
+	if draft {
+		version, err = versionRepo.FindDraft(templateID)
+	} else {
+		version, err = versionRepo.FindLatestPublished(templateID)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, true
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template version", "details": err.Error()})
+		return nil, false
+	}
+	return version, true
+}
+
+// applyFieldVisibility sets the visibility rule of a draft field from the request and checks
+// the rules of the whole version with it. It returns the fields of the version, the given one
+// included.
+func applyFieldVisibility(c *gin.Context, field *model.AnamneseField, visibility *dto.AnamneseFieldVisibilityRequest) ([]*model.AnamneseField, bool) {
+	fieldRepo := repository.NewAnamneseFieldRepository(config.DB)
+	field.VisibleIfFieldKey, field.VisibleIfOperator, field.VisibleIfValue = nil, nil, nil
+
+	if visibility != nil {
+		dependsOn, err := fieldRepo.FindByID(visibility.DependsOnFieldID)
+		if err != nil || dependsOn.AnamneseID != field.AnamneseID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Visibility rule depends on a field of another template"})
+			return nil, false
+		}
+		// Rules refer to the key, which the copies of the field share across versions
+		field.VisibleIfFieldKey = &dependsOn.FieldKey
+		field.VisibleIfOperator = &visibility.Operator
+		field.VisibleIfValue = &visibility.Value
+	}
+
+	versionFields, err := fieldRepo.FindByVersionID(field.VersionID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch fields", "details": err.Error()})
+		return nil, false
+	}
+
+	replaced := false
+	for i, other := range versionFields {
+		if other.ID == field.ID {
+			versionFields[i], replaced = field, true
+		}
+	}
+	if !replaced {
+		versionFields = append(versionFields, field)
+	}
+
+	if errs := helper.CheckAnamneseRules(versionFields); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid visibility rule", "details": errs})
+		return nil, false
+	}
+
+	// Store the value as an answer to the other field would be stored
+	if visibility != nil {
+		for _, dependsOn := range versionFields {
+			if dependsOn.FieldKey == *field.VisibleIfFieldKey {
+				value, _ := helper.NormalizeAnamneseRule(dependsOn, visibility.Operator, visibility.Value)
+				field.VisibleIfValue = &value
+			}
+		}
+	}
+
+	return versionFields, true
+}
+
+// fieldVisibility renders the visibility rule of a field, pointing to the field of the same
+// version it depends on. It is nil for fields that are always shown.
+func fieldVisibility(field *model.AnamneseField, versionFields []*model.AnamneseField) gin.H {
+	if field.VisibleIfFieldKey == nil {
+		return nil
+	}
+
+	rule := gin.H{
+		"depends_on_field_key": field.VisibleIfFieldKey.String(),
+		"operator":             field.VisibleIfOperator,
+		"value":                field.VisibleIfValue,
+	}
+	for _, dependsOn := range versionFields {
+		if dependsOn.FieldKey == *field.VisibleIfFieldKey {
+			rule["depends_on_field_id"] = dependsOn.ID.String()
+		}
+	}
+	return rule
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"github.com/LacirJR/psygrow-api/src/internal/config"
 	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/anamnese"
 	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
 	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,15 +21,14 @@ func CreateAnamneseFieldOption(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userID, exists := c.Get("userID")
+	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
 	}
 
 	// Parse anamnese field ID
-	anamneseFieldID, err := uuid.Parse(request.AnamneseFieldID)
-	if err != nil {
+	if _, err := uuid.Parse(request.AnamneseFieldID); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anamnese field ID"})
 		return
 	}
@@ -45,11 +46,20 @@ func CreateAnamneseFieldOption(c *gin.Context) {
 		return
 	}
 
+	// Options are only changed in the draft version of the template
+	anamneseField, ok := draftField(c, anamneseField)
+	if !ok {
+		return
+	}
+
 	// Create anamnese field option
+	optionID := uuid.New()
 	option := &model.AnamneseFieldOption{
-		AnamneseFieldID: anamneseFieldID,
+		ID:              optionID,
+		AnamneseFieldID: anamneseField.ID,
 		OptionValue:     request.OptionValue,
 		OptionOrder:     request.OptionOrder,
+		OptionKey:       optionID,
 	}
 
 	// Save anamnese field option
@@ -79,15 +89,14 @@ func CreateAnamneseFieldOptionsBulk(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userID, exists := c.Get("userID")
+	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
 	}
 
 	// Parse anamnese field ID
-	anamneseFieldID, err := uuid.Parse(request.AnamneseFieldID)
-	if err != nil {
+	if _, err := uuid.Parse(request.AnamneseFieldID); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anamnese field ID"})
 		return
 	}
@@ -105,26 +114,32 @@ func CreateAnamneseFieldOptionsBulk(c *gin.Context) {
 		return
 	}
 
-	// Delete existing options for this field
-	optionRepo := repository.NewAnamneseFieldOptionRepository(config.DB)
-	if err := optionRepo.DeleteByAnamneseFieldID(request.AnamneseFieldID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete existing options"})
+	// Options are only changed in the draft version of the template
+	anamneseField, ok := draftField(c, anamneseField)
+	if !ok {
 		return
 	}
 
 	// Create anamnese field options
 	var options []*model.AnamneseFieldOption
 	for _, item := range request.Options {
+		optionID := uuid.New()
 		option := &model.AnamneseFieldOption{
-			AnamneseFieldID: anamneseFieldID,
+			ID:              optionID,
+			AnamneseFieldID: anamneseField.ID,
 			OptionValue:     item.OptionValue,
 			OptionOrder:     item.OptionOrder,
+			OptionKey:       optionID,
 		}
 		options = append(options, option)
 	}
 
-	// Save anamnese field options
-	if err := optionRepo.SaveBulk(options); err != nil {
+	// Existing options are replaced, unless some were chosen in answers
+	optionRepo := repository.NewAnamneseFieldOptionRepository(config.DB)
+	if err := optionRepo.ReplaceByAnamneseFieldID(anamneseField.ID, options); err != nil {
+		if optionInUse(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create anamnese field options"})
 		return
 	}
@@ -149,7 +164,7 @@ func GetAnamneseFieldOptions(c *gin.Context) {
 	fieldID := c.Param("field_id")
 
 	// Get user ID from context
-	userID, exists := c.Get("userID")
+	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 		return
@@ -190,3 +205,278 @@ func GetAnamneseFieldOptions(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"options": responses})
 }
+
+// GetAnamneseFieldOption gets an option of an anamnese field
+func GetAnamneseFieldOption(c *gin.Context) {
+	// Get user ID from context
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Check if anamnese field exists and belongs to the user
+	anamneseFieldRepo := repository.NewAnamneseFieldRepository(config.DB)
+	anamneseField, err := anamneseFieldRepo.FindByID(c.Param("field_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Anamnese field not found"})
+		return
+	}
+
+	if anamneseField.UserID.String() != userID.(string) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view options for this anamnese field"})
+		return
+	}
+
+	optionRepo := repository.NewAnamneseFieldOptionRepository(config.DB)
+	option, err := optionRepo.FindByID(c.Param("option_id"))
+	if err != nil || option.AnamneseFieldID != anamneseField.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Anamnese field option not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAnamneseFieldOptionResponse(option.ID, option.OptionValue, option.OptionOrder, option.AnamneseFieldID))
+}
+
+// UpdateAnamneseFieldOption updates the value and order of an option in the draft version of
+// its field
+func UpdateAnamneseFieldOption(c *gin.Context) {
+	var request dto.AnamneseFieldOptionItem
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	anamneseField, ok := findOwnDraftField(c, c.Param("field_id"))
+	if !ok {
+		return
+	}
+	option, ok := draftOption(c, anamneseField, c.Param("option_id"))
+	if !ok {
+		return
+	}
+
+	option.OptionValue = request.OptionValue
+	option.OptionOrder = request.OptionOrder
+
+	optionRepo := repository.NewAnamneseFieldOptionRepository(config.DB)
+	if err := optionRepo.Update(option); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update anamnese field option"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAnamneseFieldOptionResponse(option.ID, option.OptionValue, option.OptionOrder, option.AnamneseFieldID))
+}
+
+// DeleteAnamneseFieldOption deletes an option from the draft version of its field. Options
+// chosen in answered anamneses cannot be deleted.
+func DeleteAnamneseFieldOption(c *gin.Context) {
+	anamneseField, ok := findOwnDraftField(c, c.Param("field_id"))
+	if !ok {
+		return
+	}
+	option, ok := draftOption(c, anamneseField, c.Param("option_id"))
+	if !ok {
+		return
+	}
+
+	optionRepo := repository.NewAnamneseFieldOptionRepository(config.DB)
+	if err := optionRepo.DeleteUnused(option); err != nil {
+		if optionInUse(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete anamnese field option"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Anamnese field option deleted successfully"})
+}
+
+// ReplaceAnamneseFieldOptions replaces all the options of a field in a single operation.
+// Options sent with their ID are updated, the others are created, and options left out are
+// deleted unless they were chosen in answered anamneses.
+func ReplaceAnamneseFieldOptions(c *gin.Context) {
+	var request dto.AnamneseFieldOptionReplaceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	anamneseField, ok := findOwnDraftField(c, c.Param("field_id"))
+	if !ok {
+		return
+	}
+
+	optionRepo := repository.NewAnamneseFieldOptionRepository(config.DB)
+	current, err := optionRepo.FindByAnamneseFieldID(anamneseField.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get anamnese field options"})
+		return
+	}
+
+	kept := make(map[uuid.UUID]bool, len(request.Options))
+	options := make([]*model.AnamneseFieldOption, 0, len(request.Options))
+	for _, item := range request.Options {
+		var option *model.AnamneseFieldOption
+		if item.ID != "" {
+			option = resolveDraftOption(anamneseField, current, item.ID)
+			if option == nil || kept[option.ID] {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or repeated option ID", "option_id": item.ID})
+				return
+			}
+		} else {
+			optionID := uuid.New()
+			option = &model.AnamneseFieldOption{ID: optionID, AnamneseFieldID: anamneseField.ID, OptionKey: optionID}
+		}
+		option.OptionValue = item.OptionValue
+		option.OptionOrder = item.OptionOrder
+		kept[option.ID] = true
+		options = append(options, option)
+	}
+
+	if err := optionRepo.ReplaceByAnamneseFieldID(anamneseField.ID, options); err != nil {
+		if optionInUse(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to replace anamnese field options"})
+		return
+	}
+
+	responses := make([]dto.AnamneseFieldOptionResponse, len(options))
+	for i, option := range options {
+		responses[i] = dto.NewAnamneseFieldOptionResponse(option.ID, option.OptionValue, option.OptionOrder, option.AnamneseFieldID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"options": responses})
+}
+
+// ReorderAnamneseFieldOptions orders the options of a field as listed, in a single operation.
+// Every option of the field must be listed once.
+func ReorderAnamneseFieldOptions(c *gin.Context) {
+	var request dto.AnamneseFieldOptionOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	anamneseField, ok := findOwnDraftField(c, c.Param("field_id"))
+	if !ok {
+		return
+	}
+
+	optionRepo := repository.NewAnamneseFieldOptionRepository(config.DB)
+	current, err := optionRepo.FindByAnamneseFieldID(anamneseField.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get anamnese field options"})
+		return
+	}
+
+	order := make([]uuid.UUID, 0, len(request.OptionIDs))
+	listed := make(map[uuid.UUID]bool, len(request.OptionIDs))
+	for _, id := range request.OptionIDs {
+		option := resolveDraftOption(anamneseField, current, id)
+		if option == nil || listed[option.ID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or repeated option ID", "option_id": id})
+			return
+		}
+		listed[option.ID] = true
+		order = append(order, option.ID)
+	}
+	if len(order) != len(current) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Every option of the field must be listed"})
+		return
+	}
+
+	if err := optionRepo.Reorder(anamneseField.ID, order); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder anamnese field options"})
+		return
+	}
+
+	responses := make([]dto.AnamneseFieldOptionResponse, 0, len(order))
+	for i, optionID := range order {
+		for _, option := range current {
+			if option.ID == optionID {
+				responses = append(responses, dto.NewAnamneseFieldOptionResponse(option.ID, option.OptionValue, i+1, option.AnamneseFieldID))
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"options": responses})
+}
+
+// findOwnDraftField finds a field of the authenticated user and returns its copy in the draft
+// version of the template, answering the error otherwise
+func findOwnDraftField(c *gin.Context, fieldID string) (*model.AnamneseField, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+
+	anamneseField, err := repository.NewAnamneseFieldRepository(config.DB).FindByID(fieldID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Anamnese field not found"})
+		return nil, false
+	}
+
+	if anamneseField.UserID.String() != userID.(string) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to change options of this anamnese field"})
+		return nil, false
+	}
+
+	// Options are only changed in the draft version of the template
+	return draftField(c, anamneseField)
+}
+
+// draftOption finds the copy of an option in the given draft field, answering the error
+// otherwise
+func draftOption(c *gin.Context, field *model.AnamneseField, optionID string) (*model.AnamneseFieldOption, bool) {
+	current, err := repository.NewAnamneseFieldOptionRepository(config.DB).FindByAnamneseFieldID(field.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get anamnese field options"})
+		return nil, false
+	}
+
+	option := resolveDraftOption(field, current, optionID)
+	if option == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Anamnese field option not found"})
+		return nil, false
+	}
+	return option, true
+}
+
+// resolveDraftOption matches an option ID against the options of a draft field. The ID may be
+// that of a copy of the option in another version of the field. It is nil when nothing matches.
+func resolveDraftOption(field *model.AnamneseField, current []*model.AnamneseFieldOption, optionID string) *model.AnamneseFieldOption {
+	for _, option := range current {
+		if option.ID.String() == optionID {
+			return option
+		}
+	}
+
+	other, err := repository.NewAnamneseFieldOptionRepository(config.DB).FindByID(optionID)
+	if err != nil {
+		return nil
+	}
+	owner, err := repository.NewAnamneseFieldRepository(config.DB).FindByID(other.AnamneseFieldID.String())
+	if err != nil || owner.FieldKey != field.FieldKey {
+		return nil
+	}
+	for _, option := range current {
+		if option.OptionKey == other.OptionKey {
+			return option
+		}
+	}
+	return nil
+}
+
+// optionInUse reports whether the error is an option chosen in answered anamneses, answering
+// the conflict when it is
+func optionInUse(c *gin.Context, err error) bool {
+	var inUse *port.AnamneseOptionInUseError
+	if !errors.As(err, &inUse) {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": "Option was chosen in answered anamneses and cannot be removed", "option_id": inUse.OptionID.String()})
+	return true
+}
@@ -209,6 +209,17 @@ func DeleteAnamneseTemplate(c *gin.Context) {
 		return
 	}
 
+	// Answered anamneses keep referring to the questions of the template
+	answered, err := repository.NewPatientAnamneseRepository(config.DB).CountByTemplateID(templateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check template usage", "details": err.Error()})
+		return
+	}
+	if answered > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Template has answered anamneses and cannot be deleted"})
+		return
+	}
+
 	// Delete template
 	if err := repo.Delete(templateID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete template", "details": err.Error()})
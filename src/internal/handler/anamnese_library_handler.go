@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/anamnese"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+)
+
+// ExportAnamneseTemplate exports a template with the fields and options of one of its versions.
+// Without a version_id query parameter the latest published version is exported, or the draft
+// when nothing was published yet.
+func ExportAnamneseTemplate(c *gin.Context) {
+	template, ok := findReadableTemplate(c, c.Param("template_id"))
+	if !ok {
+		return
+	}
+
+	export, ok := exportTemplate(c, template, c.Query("version_id"))
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// ImportAnamneseTemplate creates a template in the account of the authenticated user from an
+// export, with its fields and options as a first published version
+func ImportAnamneseTemplate(c *gin.Context) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req dto.AnamneseTemplateExport
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	template, version, ok := importTemplate(c, req, userID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"template": dto.NewAnamneseTemplateResponse(template.ID, template.Title, template.UserID),
+		"version":  dto.NewAnamneseTemplateVersionResponse(*version),
+	})
+}
+
+// GetAnamneseLibrary returns the starter templates shipped with the API, which any user can
+// export or clone into their account
+func GetAnamneseLibrary(c *gin.Context) {
+	templates, err := repository.NewAnamneseTemplateRepository(config.DB).FindBuiltIn()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch templates", "details": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, len(templates))
+	for i, t := range templates {
+		response[i] = gin.H{
+			"id":    t.ID.String(),
+			"key":   *t.BuiltInKey,
+			"title": t.Title,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CloneAnamneseTemplate copies a template of the authenticated user, or a starter template,
+// into the account of another user or of every active member of an organization. Targets must
+// belong to the organization of the authenticated user unless they are an admin, and only
+// admins clone into a whole organization.
+func CloneAnamneseTemplate(c *gin.Context) {
+	template, ok := findReadableTemplate(c, c.Param("template_id"))
+	if !ok {
+		return
+	}
+
+	// The body is optional: without one the template is cloned into the caller's account
+	var req dto.AnamneseTemplateCloneRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	targets, ok := cloneTargets(c, req)
+	if !ok {
+		return
+	}
+
+	export, ok := exportTemplate(c, template, "")
+	if !ok {
+		return
+	}
+
+	// Every clone is created or none is
+	copies := make([]port.AnamneseTemplateCopy, 0, len(targets))
+	for _, target := range targets {
+		cloned, ok := templateFromExport(c, export, target)
+		if !ok {
+			return
+		}
+		copies = append(copies, cloned)
+	}
+	if err := repository.NewAnamneseTemplateRepository(config.DB).CreateCopies(copies); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clone template", "details": err.Error()})
+		return
+	}
+
+	response := make([]dto.AnamneseTemplateResponse, len(copies))
+	for i, cloned := range copies {
+		response[i] = dto.NewAnamneseTemplateResponse(cloned.Template.ID, cloned.Template.Title, cloned.Template.UserID)
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// findReadableTemplate finds a template of the authenticated user or a starter template,
+// answering the error otherwise
+func findReadableTemplate(c *gin.Context, templateID string) (*model.AnamneseTemplate, bool) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return nil, false
+	}
+
+	template, err := repository.NewAnamneseTemplateRepository(config.DB).FindByID(templateID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Anamnese template not found"})
+		return nil, false
+	}
+
+	if template.UserID != userID && template.BuiltInKey == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this template"})
+		return nil, false
+	}
+
+	return template, true
+}
+
+// exportTemplate exports the given version of a template, or its latest published version and
+// then its draft when no version is given
+func exportTemplate(c *gin.Context, template *model.AnamneseTemplate, versionID string) (dto.AnamneseTemplateExport, bool) {
+	versionRepo := repository.NewAnamneseTemplateVersionRepository(config.DB)
+
+	var version *model.AnamneseTemplateVersion
+	var err error
+	if versionID != "" {
+		version, err = versionRepo.FindByID(versionID)
+		if err != nil || version.TemplateID != template.ID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+			return dto.AnamneseTemplateExport{}, false
+		}
+	} else {
+		version, err = versionRepo.FindLatestPublished(template.ID.String())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			version, err = versionRepo.FindDraft(template.ID.String())
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Template has no fields to export"})
+			return dto.AnamneseTemplateExport{}, false
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template version", "details": err.Error()})
+			return dto.AnamneseTemplateExport{}, false
+		}
+	}
+
+	version, err = versionRepo.FindByIDWithFields(version.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch fields", "details": err.Error()})
+		return dto.AnamneseTemplateExport{}, false
+	}
+	if len(version.Fields) == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Template has no fields to export"})
+		return dto.AnamneseTemplateExport{}, false
+	}
+
+	fields := make([]*model.AnamneseField, len(version.Fields))
+	for i := range version.Fields {
+		fields[i] = &version.Fields[i]
+	}
+
+	return dto.NewAnamneseTemplateExport(template.Title, fields), true
+}
+
+// importTemplate creates a template owned by the given user from an export, answering the
+// error otherwise. Visibility rule errors are keyed by the keys of the export.
+func importTemplate(c *gin.Context, export dto.AnamneseTemplateExport, userID uuid.UUID) (*model.AnamneseTemplate, *model.AnamneseTemplateVersion, bool) {
+	imported, ok := templateFromExport(c, export, userID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	if err := repository.NewAnamneseTemplateRepository(config.DB).CreateWithVersion(imported.Template, imported.Version, imported.Fields); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import template", "details": err.Error()})
+		return nil, nil, false
+	}
+
+	return imported.Template, imported.Version, true
+}
+
+// templateFromExport builds a template owned by the given user from an export without saving
+// it, answering the error otherwise
+func templateFromExport(c *gin.Context, export dto.AnamneseTemplateExport, userID uuid.UUID) (port.AnamneseTemplateCopy, bool) {
+	template, version, fields, err := export.ToModels(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template export", "details": err.Error()})
+		return port.AnamneseTemplateCopy{}, false
+	}
+
+	if errs := helper.NormalizeAnamneseRules(fields); len(errs) > 0 {
+		byKey := validation.FieldErrors{}
+		for i, field := range fields {
+			if reason, ok := errs[field.ID.String()]; ok {
+				byKey[export.Fields[i].Key] = reason
+			}
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid visibility rules", "details": byKey})
+		return port.AnamneseTemplateCopy{}, false
+	}
+
+	return port.AnamneseTemplateCopy{Template: template, Version: version, Fields: fields}, true
+}
+
+// cloneTargets resolves the users a template is cloned into: the authenticated user, the
+// requested user or the active members of the requested organization
+func cloneTargets(c *gin.Context, req dto.AnamneseTemplateCloneRequest) ([]uuid.UUID, bool) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return nil, false
+	}
+	if req.UserID == nil && req.OrganizationID == nil {
+		return []uuid.UUID{userID}, true
+	}
+
+	userRepo := repository.NewUserRepository(config.DB)
+	caller, err := userRepo.FindByID(userID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return nil, false
+	}
+	allowed := func(organizationID *uuid.UUID) bool {
+		return caller.Role == model.UserRoleAdmin ||
+			(caller.OrganizationID != nil && organizationID != nil && *caller.OrganizationID == *organizationID)
+	}
+
+	if req.UserID != nil {
+		targetID, _ := uuid.Parse(*req.UserID)
+		target, err := userRepo.FindByID(targetID)
+		if err != nil || !target.IsActive {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Target user not found"})
+			return nil, false
+		}
+		if !allowed(target.OrganizationID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to clone templates into this account"})
+			return nil, false
+		}
+		return []uuid.UUID{target.ID}, true
+	}
+
+	// Cloning into every member of an organization is an admin task
+	organizationID, _ := uuid.Parse(*req.OrganizationID)
+	if caller.Role != model.UserRoleAdmin || !allowed(&organizationID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to clone templates into this organization"})
+		return nil, false
+	}
+
+	active := true
+	members, err := userRepo.FindAll(port.UserFilter{OrganizationID: &organizationID, IsActive: &active})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch organization members", "details": err.Error()})
+		return nil, false
+	}
+	if len(members) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organization has no active members"})
+		return nil, false
+	}
+
+	targets := make([]uuid.UUID, len(members))
+	for i, member := range members {
+		targets[i] = member.ID
+	}
+	return targets, true
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/anamnese"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+// PublishAnamneseTemplate publishes the draft version of a template, freezing its fields and
+// options. New patient anamneses are answered against the latest published version.
+func PublishAnamneseTemplate(c *gin.Context) {
+	template, ok := findOwnTemplate(c, c.Param("template_id"))
+	if !ok {
+		return
+	}
+
+	versionRepo := repository.NewAnamneseTemplateVersionRepository(config.DB)
+	draft, err := versionRepo.FindDraft(template.ID.String())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Template has no draft version to publish"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch draft version", "details": err.Error()})
+		return
+	}
+
+	fields, err := repository.NewAnamneseFieldRepository(config.DB).FindByVersionID(draft.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch fields", "details": err.Error()})
+		return
+	}
+	if len(fields) == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "A version without fields cannot be published"})
+		return
+	}
+	// Options may have changed since the rules were saved
+	if errs := helper.CheckAnamneseRules(fields); len(errs) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Invalid visibility rules", "details": errs})
+		return
+	}
+
+	if err := versionRepo.Publish(draft); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Version was already published"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish version", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAnamneseTemplateVersionResponse(*draft))
+}
+
+// GetAnamneseTemplateVersions returns the versions of a template, most recent first
+func GetAnamneseTemplateVersions(c *gin.Context) {
+	template, ok := findOwnTemplate(c, c.Param("template_id"))
+	if !ok {
+		return
+	}
+
+	versionRepo := repository.NewAnamneseTemplateVersionRepository(config.DB)
+	versions, err := versionRepo.FindByTemplateID(template.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch versions", "details": err.Error()})
+		return
+	}
+
+	response := make([]dto.AnamneseTemplateVersionResponse, len(versions))
+	for i, version := range versions {
+		response[i] = dto.NewAnamneseTemplateVersionResponse(*version)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAnamneseTemplateVersion returns a version of a template with the fields and options it had
+func GetAnamneseTemplateVersion(c *gin.Context) {
+	template, ok := findOwnTemplate(c, c.Param("template_id"))
+	if !ok {
+		return
+	}
+
+	versionRepo := repository.NewAnamneseTemplateVersionRepository(config.DB)
+	version, err := versionRepo.FindByIDWithFields(c.Param("version_id"))
+	if err != nil || version.TemplateID != template.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAnamneseTemplateVersionResponse(*version))
+}
+
+// findOwnTemplate finds a template of the authenticated user, answering the error otherwise
+func findOwnTemplate(c *gin.Context, templateID string) (*model.AnamneseTemplate, bool) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return nil, false
+	}
+
+	template, err := repository.NewAnamneseTemplateRepository(config.DB).FindByID(templateID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Anamnese template not found"})
+		return nil, false
+	}
+
+	if template.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this template"})
+		return nil, false
+	}
+
+	return template, true
+}
+
+// editableDraft returns the draft version of a template, creating it from the latest
+// published version when there is none
+func editableDraft(c *gin.Context, templateID uuid.UUID) (*model.AnamneseTemplateVersion, bool) {
+	versionRepo := repository.NewAnamneseTemplateVersionRepository(config.DB)
+	draft, err := versionRepo.FindOrCreateDraft(templateID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to prepare draft version", "details": err.Error()})
+		return nil, false
+	}
+	return draft, true
+}
+
+// draftField returns the copy of a field in the draft version of its template, so that edits
+// never change a published version
+func draftField(c *gin.Context, field *model.AnamneseField) (*model.AnamneseField, bool) {
+	draft, ok := editableDraft(c, field.AnamneseID)
+	if !ok {
+		return nil, false
+	}
+	if field.VersionID == draft.ID {
+		return field, true
+	}
+
+	fieldRepo := repository.NewAnamneseFieldRepository(config.DB)
+	copied, err := fieldRepo.FindByKey(draft.ID.String(), field.FieldKey)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Field was removed from the draft version"})
+		return nil, false
+	}
+	return copied, true
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/user"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/security"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CreateAPIKey creates an API key acting on behalf of the authenticated user.
+// The full key is returned only in this response.
+func CreateAPIKey(c *gin.Context) {
+	var req dto.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	// A key cannot grant access to data its owner's role cannot read, as the routes check the
+	// owner's role on API key requests too
+	role := c.GetString("user_role")
+	for _, permission := range req.Permissions {
+		if !model.RoleGrantsAPIKeyPermission(role, permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Seu perfil não permite conceder esta permissão", "permission": permission})
+			return
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A data de expiração deve estar no futuro"})
+		return
+	}
+
+	key, prefix, err := security.GenerateAPIKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar chave de API"})
+		return
+	}
+
+	apiKey := model.APIKey{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Name:        req.Name,
+		Prefix:      prefix,
+		KeyHash:     security.HashOpaqueToken(key),
+		Permissions: strings.Join(req.Permissions, " "),
+		ExpiresAt:   req.ExpiresAt,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := apiKey.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := repository.NewAPIKeyRepository(config.DB).Save(&apiKey); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar chave de API", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIKeyCreatedResponse{
+		APIKeyResponse: dto.NewAPIKeyResponse(apiKey),
+		Key:            key,
+	})
+}
+
+// GetAPIKeys lists the API keys of the authenticated user
+func GetAPIKeys(c *gin.Context) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	keys, err := repository.NewAPIKeyRepository(config.DB).FindByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar chaves de API", "details": err.Error()})
+		return
+	}
+
+	responses := make([]dto.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = dto.NewAPIKeyResponse(key)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// RevokeAPIKey revokes an API key of the authenticated user
+func RevokeAPIKey(c *gin.Context) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	apiKeyRepo := repository.NewAPIKeyRepository(config.DB)
+	key, err := apiKeyRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Chave de API não encontrada"})
+		return
+	}
+
+	if key.RevokedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Chave de API já revogada"})
+		return
+	}
+
+	if err := apiKeyRepo.Revoke(key.ID, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao revogar chave de API", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Chave de API revogada com sucesso"})
+}
@@ -55,6 +55,17 @@ func CreateAppointment(c *gin.Context) {
 	startTime := req.StartTime
 	endTime := req.EndTime
 
+	patient, err := repository.NewPatientRepository(config.DB).FindByID(patientID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Patient not found"})
+		return
+	}
+
+	// Minors are only seen with a treatment consent signed by a legal responsible
+	if !checkTreatmentConsent(c, patient, startTime) {
+		return
+	}
+
 	// Create appointment ID
 	appointmentID := uuid.New()
 
@@ -82,9 +93,9 @@ func CreateAppointment(c *gin.Context) {
 		appointment.CustomRepasseValue = req.CustomRepasseValue
 	}
 
-	// Create repository and save appointment
-	repo := repository.NewAppointmentRepository(config.DB)
-	if err := repo.Save(appointment); err != nil {
+	// Attach the appointment to the episode of care of the patient, starting a new one for a
+	// returning patient
+	if err := repository.NewCareEpisodeRepository(config.DB).Schedule(appointment); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create appointment", "details": err.Error()})
 		return
 	}
@@ -93,6 +104,7 @@ func CreateAppointment(c *gin.Context) {
 		"id":                   appointment.ID.String(),
 		"client_id":            appointment.UserID.String(),
 		"patient_id":           appointment.PatientID.String(),
+		"episode_id":           appointment.EpisodeID,
 		"professional_id":      appointment.ProfessionalID.String(),
 		"cost_center_id":       appointment.CostCenterID.String(),
 		"custom_repasse_type":  appointment.CustomRepasseType,
@@ -114,23 +126,28 @@ func GetAppointments(c *gin.Context) {
 		return
 	}
 
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
 	// Create repository
 	repo := repository.NewAppointmentRepository(config.DB)
 
 	// Get appointments
-	appointments, err := repo.FindByUserID(userID.String())
+	page, err := repo.List(userID.String(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch appointments", "details": err.Error()})
+		respondListError(c, err, "Failed to fetch appointments")
 		return
 	}
 
 	// Convert to response format
-	response := make([]gin.H, len(appointments))
-	for i, appointment := range appointments {
-		response[i] = gin.H{
+	respondList(c, page, func(appointment model.Appointment) gin.H {
+		return gin.H{
 			"id":                   appointment.ID.String(),
 			"client_id":            appointment.UserID.String(),
 			"patient_id":           appointment.PatientID.String(),
+			"episode_id":           appointment.EpisodeID,
 			"professional_id":      appointment.ProfessionalID.String(),
 			"cost_center_id":       appointment.CostCenterID.String(),
 			"custom_repasse_type":  appointment.CustomRepasseType,
@@ -141,9 +158,7 @@ func GetAppointments(c *gin.Context) {
 			"status":               appointment.Status,
 			"notes":                appointment.Notes,
 		}
-	}
-
-	c.JSON(http.StatusOK, response)
+	})
 }
 
 // GetAppointment returns a specific appointment
@@ -185,6 +200,7 @@ func GetAppointment(c *gin.Context) {
 		"id":                   appointment.ID.String(),
 		"client_id":            appointment.UserID.String(),
 		"patient_id":           appointment.PatientID.String(),
+		"episode_id":           appointment.EpisodeID,
 		"professional_id":      appointment.ProfessionalID.String(),
 		"cost_center_id":       appointment.CostCenterID.String(),
 		"custom_repasse_type":  appointment.CustomRepasseType,
@@ -245,6 +261,15 @@ func UpdateAppointment(c *gin.Context) {
 
 	if req.StartTime != nil {
 		appointment.StartTime = *req.StartTime
+
+		patient, err := repository.NewPatientRepository(config.DB).FindByID(appointment.PatientID, clientID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Patient not found"})
+			return
+		}
+		if !checkTreatmentConsent(c, patient, appointment.StartTime) {
+			return
+		}
 	}
 
 	if req.EndTime != nil {
@@ -288,6 +313,7 @@ func UpdateAppointment(c *gin.Context) {
 			AppointmentID:  appointment.ID,
 			UserID:         appointment.UserID,
 			PatientID:      appointment.PatientID,
+			EpisodeID:      appointment.EpisodeID,
 			ProfessionalID: appointment.ProfessionalID,
 			StartTime:      appointment.StartTime,
 			EndTime:        appointment.EndTime,
@@ -306,6 +332,7 @@ func UpdateAppointment(c *gin.Context) {
 		"id":                   appointment.ID.String(),
 		"client_id":            appointment.UserID.String(),
 		"patient_id":           appointment.PatientID.String(),
+		"episode_id":           appointment.EpisodeID,
 		"professional_id":      appointment.ProfessionalID.String(),
 		"cost_center_id":       appointment.CostCenterID.String(),
 		"custom_repasse_type":  appointment.CustomRepasseType,
@@ -361,3 +388,18 @@ func DeleteAppointment(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Appointment deleted successfully"})
 }
+
+// checkTreatmentConsent answers 409 when the patient is a minor at the given time and has no valid
+// treatment consent on file for it
+func checkTreatmentConsent(c *gin.Context, patient *model.Patient, at time.Time) bool {
+	ok, err := hasTreatmentConsent(patient, at)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check patient consent", "details": err.Error()})
+		return false
+	}
+	if !ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "Minor patient has no valid treatment consent signed by a legal responsible"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/patient"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// CreateCareEpisode opens a new episode of care for a patient returning to treatment, reactivating
+// the patient. A patient has at most one open episode.
+func CreateCareEpisode(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	var req dto.CareEpisodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	// Verify that the patient belongs to the user
+	if _, err := repository.NewPatientRepository(config.DB).FindByID(patientID, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return
+	}
+
+	episode := model.CareEpisode{
+		ID:        uuid.New(),
+		UserID:    userID,
+		PatientID: patientID,
+		StartDate: time.Now(),
+	}
+	if req.StartDate != nil {
+		episode.StartDate = *req.StartDate
+	}
+
+	if err := repository.NewCareEpisodeRepository(config.DB).Open(&episode); err != nil {
+		if errors.Is(err, port.ErrCareEpisodeAlreadyOpen) {
+			c.JSON(http.StatusConflict, gin.H{"error": "O paciente já possui um episódio de cuidado aberto"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao abrir episódio de cuidado", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewCareEpisodeResponse(episode))
+}
+
+// GetCareEpisodes lists the episodes of care of a patient, most recent first
+func GetCareEpisodes(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	// Verify that the patient belongs to the user
+	if _, err := repository.NewPatientRepository(config.DB).FindByID(patientID, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return
+	}
+
+	episodes, err := repository.NewCareEpisodeRepository(config.DB).FindByPatient(patientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar episódios de cuidado", "details": err.Error()})
+		return
+	}
+
+	responses := []dto.CareEpisodeResponse{}
+	for _, episode := range episodes {
+		responses = append(responses, dto.NewCareEpisodeResponse(episode))
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetCareEpisode gets an episode of care of a patient
+func GetCareEpisode(c *gin.Context) {
+	episode, ok := careEpisodeFromPath(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewCareEpisodeResponse(*episode))
+}
+
+// CloseCareEpisode closes an open episode of care by discharge, referral or dropout. The patient
+// is deactivated and the appointments still scheduled are canceled.
+func CloseCareEpisode(c *gin.Context) {
+	episode, ok := careEpisodeFromPath(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CareEpisodeCloseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if !episode.IsOpen() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Episódio de cuidado já encerrado"})
+		return
+	}
+
+	endDate := time.Now()
+	if req.EndDate != nil {
+		if req.EndDate.After(endDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A data de encerramento não pode ser futura"})
+			return
+		}
+		endDate = *req.EndDate
+	}
+	if endDate.Before(episode.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A data de encerramento não pode ser anterior ao início do episódio"})
+		return
+	}
+
+	reason := req.Reason
+	episode.EndDate = &endDate
+	episode.EndReason = &reason
+	episode.ReferredTo = req.ReferredTo
+	episode.DischargeSummary = req.DischargeSummary
+
+	canceled, err := repository.NewCareEpisodeRepository(config.DB).Close(episode)
+	if err != nil {
+		if errors.Is(err, port.ErrCareEpisodeClosed) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Episódio de cuidado já encerrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao encerrar episódio de cuidado", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CareEpisodeCloseResponse{
+		Episode:              dto.NewCareEpisodeResponse(*episode),
+		CanceledAppointments: canceled,
+	})
+}
+
+// careEpisodeFromPath finds the episode of care in the path, of a patient of the user, answering
+// the error otherwise
+func careEpisodeFromPath(c *gin.Context) (*model.CareEpisode, bool) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return nil, false
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return nil, false
+	}
+
+	// Verify that the patient belongs to the user
+	if _, err := repository.NewPatientRepository(config.DB).FindByID(patientID, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return nil, false
+	}
+
+	episode, err := repository.NewCareEpisodeRepository(config.DB).FindByID(id, patientID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Episódio de cuidado não encontrado"})
+		return nil, false
+	}
+	return episode, true
+}
@@ -78,20 +78,24 @@ func GetCostCenters(c *gin.Context) {
 		return
 	}
 
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
 	// Create repository
 	repo := repository.NewCostCenterRepository(config.DB)
 
 	// Get cost centers
-	costCenters, err := repo.FindByUserID(userID.(string))
+	page, err := repo.List(userID.(string), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cost centers", "details": err.Error()})
+		respondListError(c, err, "Failed to fetch cost centers")
 		return
 	}
 
 	// Convert to response format
-	response := make([]gin.H, len(costCenters))
-	for i, costCenter := range costCenters {
-		response[i] = gin.H{
+	respondList(c, page, func(costCenter model.CostCenter) gin.H {
+		return gin.H{
 			"id":            costCenter.ID.String(),
 			"user_id":       costCenter.UserID.String(),
 			"name":          costCenter.Name,
@@ -102,9 +106,7 @@ func GetCostCenters(c *gin.Context) {
 			"created_at":    costCenter.CreatedAt,
 			"updated_at":    costCenter.UpdatedAt,
 		}
-	}
-
-	c.JSON(http.StatusOK, response)
+	})
 }
 
 // GetCostCenter returns a specific cost center
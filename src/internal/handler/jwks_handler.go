@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/security"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// GetJWKS publishes the public signing keys so other services can verify PsyGrow tokens
+func GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, security.Tokens().JWKS())
+}
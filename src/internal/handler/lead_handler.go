@@ -1,14 +1,16 @@
 package handler
 
 import (
+	"errors"
 	"github.com/LacirJR/psygrow-api/src/internal/config"
 	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/lead"
 	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
 	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"net/http"
-	"strconv"
 	"time"
 )
 
@@ -45,6 +47,7 @@ func CreateLead(c *gin.Context) {
 		FullName:         req.FullName,
 		Phone:            req.Phone,
 		Email:            req.Email,
+		Address:          req.Address.ToModel(),
 		BirthDate:        req.BirthDate,
 		ContactDate:      req.ContactDate,
 		Status:           req.Status,
@@ -56,6 +59,11 @@ func CreateLead(c *gin.Context) {
 		UpdatedAt:        time.Now(),
 	}
 
+	if errs := lead.NormalizeContact(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return
+	}
+
 	leadRepo := repository.NewLeadRepository(config.DB)
 	if err := leadRepo.Create(&lead); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar lead", "details": err.Error()})
@@ -99,35 +107,19 @@ func GetLeads(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination parameters
-	limit, offset := getPaginationParams(c)
-
-	leadRepo := repository.NewLeadRepository(config.DB)
-	leads, err := leadRepo.FindAll(userID, limit, offset)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar leads", "details": err.Error()})
+	query, ok := parseListQuery(c)
+	if !ok {
 		return
 	}
 
-	// Convert leads to response DTOs
-	var responses []dto.LeadResponse
-	for _, lead := range leads {
-		responses = append(responses, dto.NewLeadResponse(lead))
-	}
-
-	// Get total count
-	count, err := leadRepo.Count(userID)
+	leadRepo := repository.NewLeadRepository(config.DB)
+	page, err := leadRepo.List(userID, query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao contar leads", "details": err.Error()})
+		respondListError(c, err, "Erro ao buscar leads")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   responses,
-		"total":  count,
-		"limit":  limit,
-		"offset": offset,
-	})
+	respondList(c, page, dto.NewLeadResponse)
 }
 
 // UpdateLead updates a lead
@@ -174,8 +166,16 @@ func UpdateLead(c *gin.Context) {
 	lead.FullName = req.FullName
 	lead.Phone = req.Phone
 	lead.Email = req.Email
+	lead.Address = req.Address.ToModel()
 	lead.BirthDate = req.BirthDate
 	lead.ContactDate = req.ContactDate
+	if req.Status != lead.Status {
+		// The pipeline stage no longer matches a manually changed status
+		lead.StageID = nil
+		if req.Status != model.LeadStatusLost {
+			lead.LostReasonID = nil
+		}
+	}
 	lead.Status = req.Status
 	lead.WasAttended = req.WasAttended
 	lead.Notes = req.Notes
@@ -183,6 +183,11 @@ func UpdateLead(c *gin.Context) {
 	lead.GdprBlockContact = req.GdprBlockContact
 	lead.UpdatedAt = time.Now()
 
+	if errs := lead.NormalizeContact(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return
+	}
+
 	if err := leadRepo.Update(lead); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar lead", "details": err.Error()})
 		return
@@ -215,7 +220,8 @@ func DeleteLead(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Lead excluído com sucesso"})
 }
 
-// ConvertLeadToPatient converts a lead to a patient
+// ConvertLeadToPatient converts a lead to a patient, carrying over its contact data, notes and origin.
+// The first appointment and the intake anamnese template can be set in the same transaction.
 func ConvertLeadToPatient(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -237,34 +243,165 @@ func ConvertLeadToPatient(c *gin.Context) {
 	}
 
 	leadRepo := repository.NewLeadRepository(config.DB)
-	patient, err := leadRepo.ConvertToPatient(id, userID, req.CostCenterID)
+	lead, err := leadRepo.FindByID(id, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao converter lead para paciente", "details": err.Error()})
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lead não encontrado"})
+		return
+	}
+
+	if lead.Status == model.LeadStatusConverted || lead.ConvertedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Lead já convertido"})
+		return
+	}
+	if lead.GdprBlockContact {
+		c.JSON(http.StatusConflict, gin.H{"error": "Lead bloqueado para contato não pode ser convertido"})
+		return
+	}
+
+	costCenter, err := repository.NewCostCenterRepository(config.DB).FindByID(req.CostCenterID.String())
+	if err != nil || costCenter.UserID != userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Centro de custo não encontrado"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":    "Lead convertido para paciente com sucesso",
-		"patient_id": patient.ID,
-	})
+	if req.AnamneseTemplateID != nil {
+		template, err := repository.NewAnamneseTemplateRepository(config.DB).FindByID(req.AnamneseTemplateID.String())
+		if err != nil || template.UserID != userID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Modelo de anamnese não encontrado"})
+			return
+		}
+	}
+
+	patient, ok := buildPatientFromLead(c, lead, req, userID)
+	if !ok {
+		return
+	}
+
+	var appointment *model.Appointment
+	if req.FirstAppointment != nil {
+		professionalID := userID
+		if req.FirstAppointment.ProfessionalID != nil {
+			professionalID = *req.FirstAppointment.ProfessionalID
+		}
+
+		appointment = &model.Appointment{
+			ID:                 uuid.New(),
+			UserID:             userID,
+			PatientID:          patient.ID,
+			ProfessionalID:     professionalID,
+			CostCenterID:       patient.CostCenterID,
+			ServiceTitle:       req.FirstAppointment.ServiceTitle,
+			StartTime:          req.FirstAppointment.StartTime,
+			EndTime:            req.FirstAppointment.EndTime,
+			Status:             model.AppointmentStatusScheduled,
+			Notes:              req.FirstAppointment.Notes,
+			CustomRepasseType:  req.FirstAppointment.CustomRepasseType,
+			CustomRepasseValue: req.FirstAppointment.CustomRepasseValue,
+			CreatedAt:          time.Now(),
+			UpdatedAt:          time.Now(),
+		}
+
+		if err := appointment.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados da primeira consulta inválidos", "details": err.Error()})
+			return
+		}
+
+		// A minor converted from a lead has no consent on file yet, so the first appointment waits for it
+		if !checkTreatmentConsent(c, patient, appointment.StartTime) {
+			return
+		}
+	}
+
+	if err := leadRepo.ConvertToPatient(id, userID, patient, appointment); err != nil {
+		switch {
+		case errors.Is(err, port.ErrLeadAlreadyConverted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Lead já convertido"})
+		case errors.Is(err, port.ErrLeadContactBlocked):
+			c.JSON(http.StatusConflict, gin.H{"error": "Lead bloqueado para contato não pode ser convertido"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Lead não encontrado"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao converter lead para paciente", "details": err.Error()})
+		}
+		return
+	}
+
+	response := dto.ConvertLeadResponse{
+		Message:            "Lead convertido para paciente com sucesso",
+		PatientID:          patient.ID,
+		AnamneseTemplateID: patient.AnamneseTemplateID,
+	}
+	if appointment != nil {
+		response.AppointmentID = &appointment.ID
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
-// Helper function to get pagination parameters
-func getPaginationParams(c *gin.Context) (int, int) {
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
+// buildPatientFromLead fills a new patient with the conversion request, falling back to the lead's data
+func buildPatientFromLead(c *gin.Context, lead *model.Lead, req dto.ConvertLeadRequest, userID uuid.UUID) (*model.Patient, bool) {
+	patient := &model.Patient{
+		ID:                    uuid.New(),
+		UserID:                userID,
+		CostCenterID:          req.CostCenterID,
+		FullName:              lead.FullName,
+		SocialName:            req.SocialName,
+		Document:              req.Document,
+		Phone:                 lead.Phone,
+		Email:                 lead.Email,
+		Gender:                req.Gender,
+		Address:               req.Address,
+		PostalAddress:         lead.Address,
+		ResidesWith:           req.ResidesWith,
+		EmergencyContactName:  req.EmergencyContactName,
+		EmergencyContactPhone: req.EmergencyContactPhone,
+		Observation:           lead.Notes,
+		DefaultRepasseType:    req.DefaultRepasseType,
+		DefaultRepasseValue:   req.DefaultRepasseValue,
+		IsActive:              true,
+		Origin:                lead.Origin,
+		AnamneseTemplateID:    req.AnamneseTemplateID,
+		CreatedAt:             time.Now(),
+		UpdatedAt:             time.Now(),
+	}
+
+	if req.FullName != nil {
+		patient.FullName = *req.FullName
+	}
+	if req.Phone != nil {
+		patient.Phone = req.Phone
+	}
+	if req.Email != nil {
+		patient.Email = req.Email
+	}
+	if req.Observation != nil {
+		patient.Observation = req.Observation
+	}
+	if req.PostalAddress != nil {
+		patient.PostalAddress = req.PostalAddress.ToModel()
+	}
+
+	switch {
+	case req.BirthDate != nil:
+		patient.BirthDate = *req.BirthDate
+	case lead.BirthDate != nil:
+		patient.BirthDate = *lead.BirthDate
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe a data de nascimento do paciente"})
+		return nil, false
+	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 {
-		limit = 10
+	if errs := patient.NormalizeContact(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados do paciente inválidos", "details": errs})
+		return nil, false
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	if err := patient.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados do paciente inválidos", "details": err.Error()})
+		return nil, false
 	}
 
-	return limit, offset
+	return patient, true
 }
 
 // Helper function to get user ID from token
@@ -0,0 +1,623 @@
+package handler
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/lead"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// CreateLeadStage creates a new pipeline stage
+func CreateLeadStage(c *gin.Context) {
+	var req dto.LeadStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	stage := model.LeadStage{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		Position:  req.Position,
+		Status:    req.Status,
+		IsActive:  req.IsActive == nil || *req.IsActive,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := stage.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	stageRepo := repository.NewLeadStageRepository(config.DB)
+	if !checkLeadStageName(c, stageRepo, stage) {
+		return
+	}
+
+	if err := stageRepo.Create(&stage); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar etapa", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewLeadStageResponse(stage))
+}
+
+// GetLeadStages lists the pipeline stages of the user
+func GetLeadStages(c *gin.Context) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	stages, err := repository.NewLeadStageRepository(config.DB).FindAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar etapas", "details": err.Error()})
+		return
+	}
+
+	responses := make([]dto.LeadStageResponse, len(stages))
+	for i, stage := range stages {
+		responses[i] = dto.NewLeadStageResponse(stage)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// UpdateLeadStage updates a pipeline stage
+func UpdateLeadStage(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var req dto.LeadStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	stageRepo := repository.NewLeadStageRepository(config.DB)
+	stage, err := stageRepo.FindByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Etapa não encontrada"})
+		return
+	}
+
+	// Leads in the stage carry its status, so the mapping cannot change under them
+	if req.Status != stage.Status {
+		count, err := stageRepo.CountLeads(stage.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar leads da etapa", "details": err.Error()})
+			return
+		}
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Não é possível alterar o status de uma etapa com leads"})
+			return
+		}
+	}
+
+	stage.Name = req.Name
+	stage.Position = req.Position
+	stage.Status = req.Status
+	if req.IsActive != nil {
+		stage.IsActive = *req.IsActive
+	}
+	stage.UpdatedAt = time.Now()
+
+	if err := stage.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if !checkLeadStageName(c, stageRepo, *stage) {
+		return
+	}
+
+	if err := stageRepo.Update(stage); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar etapa", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewLeadStageResponse(*stage))
+}
+
+// DeleteLeadStage deletes a pipeline stage that has no leads
+func DeleteLeadStage(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	stageRepo := repository.NewLeadStageRepository(config.DB)
+	if _, err := stageRepo.FindByID(id, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Etapa não encontrada"})
+		return
+	}
+
+	count, err := stageRepo.CountLeads(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar leads da etapa", "details": err.Error()})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Etapa possui leads; mova-os ou desative a etapa"})
+		return
+	}
+
+	if err := stageRepo.Delete(id, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir etapa", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Etapa excluída com sucesso"})
+}
+
+// CreateLeadLostReason creates a new lost reason
+func CreateLeadLostReason(c *gin.Context) {
+	var req dto.LeadLostReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	reason := model.LeadLostReason{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		IsActive:  req.IsActive == nil || *req.IsActive,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := reason.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	reasonRepo := repository.NewLeadLostReasonRepository(config.DB)
+	if !checkLeadLostReasonName(c, reasonRepo, reason) {
+		return
+	}
+
+	if err := reasonRepo.Create(&reason); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar motivo de perda", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewLeadLostReasonResponse(reason))
+}
+
+// GetLeadLostReasons lists the lost reasons of the user
+func GetLeadLostReasons(c *gin.Context) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	reasons, err := repository.NewLeadLostReasonRepository(config.DB).FindAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar motivos de perda", "details": err.Error()})
+		return
+	}
+
+	responses := make([]dto.LeadLostReasonResponse, len(reasons))
+	for i, reason := range reasons {
+		responses[i] = dto.NewLeadLostReasonResponse(reason)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// UpdateLeadLostReason updates a lost reason
+func UpdateLeadLostReason(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var req dto.LeadLostReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	reasonRepo := repository.NewLeadLostReasonRepository(config.DB)
+	reason, err := reasonRepo.FindByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Motivo de perda não encontrado"})
+		return
+	}
+
+	reason.Name = req.Name
+	if req.IsActive != nil {
+		reason.IsActive = *req.IsActive
+	}
+	reason.UpdatedAt = time.Now()
+
+	if err := reason.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if !checkLeadLostReasonName(c, reasonRepo, *reason) {
+		return
+	}
+
+	if err := reasonRepo.Update(reason); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar motivo de perda", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewLeadLostReasonResponse(*reason))
+}
+
+// DeleteLeadLostReason deletes a lost reason that was never used
+func DeleteLeadLostReason(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	reasonRepo := repository.NewLeadLostReasonRepository(config.DB)
+	if _, err := reasonRepo.FindByID(id, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Motivo de perda não encontrado"})
+		return
+	}
+
+	count, err := reasonRepo.CountLeads(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar uso do motivo de perda", "details": err.Error()})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Motivo de perda em uso; desative-o em vez de excluir"})
+		return
+	}
+
+	if err := reasonRepo.Delete(id, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir motivo de perda", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Motivo de perda excluído com sucesso"})
+}
+
+// MoveLeadStage moves a lead to another pipeline stage, updating its status.
+// Moving to a lost stage requires a lost reason; conversion goes through ConvertLeadToPatient.
+func MoveLeadStage(c *gin.Context) {
+	var req dto.MoveLeadStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	userID, lead, ok := findUserLead(c)
+	if !ok {
+		return
+	}
+
+	stageRepo := repository.NewLeadStageRepository(config.DB)
+	stage, err := stageRepo.FindByID(req.StageID, userID)
+	if err != nil || !stage.IsActive {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Etapa não encontrada ou inativa"})
+		return
+	}
+
+	if lead.Status == model.LeadStatusConverted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Lead já convertido"})
+		return
+	}
+
+	if stage.Status == model.LeadStatusConverted {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Use a conversão de lead para paciente para concluir o lead"})
+		return
+	}
+
+	lead.LostReasonID = nil
+	if stage.Status == model.LeadStatusLost {
+		if req.LostReasonID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Informe o motivo da perda"})
+			return
+		}
+		reason, err := repository.NewLeadLostReasonRepository(config.DB).FindByID(*req.LostReasonID, userID)
+		if err != nil || !reason.IsActive {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Motivo de perda não encontrado ou inativo"})
+			return
+		}
+		lead.LostReasonID = &reason.ID
+		lead.NextFollowUpAt = nil
+	}
+
+	transition := model.LeadStageTransition{
+		ID:          uuid.New(),
+		LeadID:      lead.ID,
+		FromStageID: lead.StageID,
+		ToStageID:   stage.ID,
+		UserID:      userID,
+		ChangedAt:   time.Now(),
+	}
+
+	lead.StageID = &stage.ID
+	lead.Status = stage.Status
+	lead.UpdatedAt = time.Now()
+
+	if err := stageRepo.MoveLead(lead, &transition); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao mover lead", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewLeadResponse(*lead))
+}
+
+// UpdateLeadFollowUp schedules or clears the next follow-up of a lead
+func UpdateLeadFollowUp(c *gin.Context) {
+	var req dto.LeadFollowUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	_, lead, ok := findUserLead(c)
+	if !ok {
+		return
+	}
+
+	if req.NextFollowUpAt != nil && !canFollowUp(c, lead) {
+		return
+	}
+
+	lead.NextFollowUpAt = req.NextFollowUpAt
+	lead.UpdatedAt = time.Now()
+
+	if err := repository.NewLeadRepository(config.DB).Update(lead); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao agendar retorno", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewLeadResponse(*lead))
+}
+
+// CreateLeadActivity records an interaction with a lead and optionally schedules the next follow-up
+func CreateLeadActivity(c *gin.Context) {
+	var req dto.LeadActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	userID, lead, ok := findUserLead(c)
+	if !ok {
+		return
+	}
+
+	occurredAt := time.Now()
+	if req.OccurredAt != nil {
+		if req.OccurredAt.After(occurredAt) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A data da atividade não pode estar no futuro"})
+			return
+		}
+		occurredAt = *req.OccurredAt
+	}
+
+	activity := model.LeadActivity{
+		ID:         uuid.New(),
+		LeadID:     lead.ID,
+		UserID:     userID,
+		Type:       req.Type,
+		Outcome:    req.Outcome,
+		Notes:      req.Notes,
+		OccurredAt: occurredAt,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := activity.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if req.NextFollowUpAt != nil {
+		if !canFollowUp(c, lead) {
+			return
+		}
+		lead.NextFollowUpAt = req.NextFollowUpAt
+	} else if req.ClearFollowUp {
+		lead.NextFollowUpAt = nil
+	}
+	if req.Outcome != model.LeadActivityOutcomeNoAnswer && req.Outcome != model.LeadActivityOutcomeLeftMessage {
+		lead.WasAttended = true
+	}
+	lead.UpdatedAt = time.Now()
+
+	if err := repository.NewLeadActivityRepository(config.DB).CreateWithLead(&activity, lead); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar atividade", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewLeadActivityResponse(activity))
+}
+
+// GetLeadActivities lists the activity timeline of a lead
+func GetLeadActivities(c *gin.Context) {
+	_, lead, ok := findUserLead(c)
+	if !ok {
+		return
+	}
+
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := repository.NewLeadActivityRepository(config.DB).List(lead.ID, query)
+	if err != nil {
+		respondListError(c, err, "Erro ao buscar atividades")
+		return
+	}
+
+	respondList(c, page, dto.NewLeadActivityResponse)
+}
+
+// GetDueLeadFollowUps lists the open leads to contact until the end of the given day (default today)
+func GetDueLeadFollowUps(c *gin.Context) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	day := time.Now()
+	if dateStr := c.Query("date"); dateStr != "" {
+		day, err = time.ParseInLocation("2006-01-02", dateStr, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Formato de data inválido, use AAAA-MM-DD"})
+			return
+		}
+	}
+	until := time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 59, 0, day.Location())
+
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+	// "date" picks the last day of the follow-ups instead of a filter
+	delete(query.Filters, "date")
+
+	page, err := repository.NewLeadRepository(config.DB).ListDueFollowUps(userID, until, query)
+	if err != nil {
+		respondListError(c, err, "Erro ao buscar retornos")
+		return
+	}
+
+	leadIDs := make([]uuid.UUID, len(page.Data))
+	for i, lead := range page.Data {
+		leadIDs[i] = lead.ID
+	}
+	lastActivities, err := repository.NewLeadActivityRepository(config.DB).FindLastByLeadIDs(leadIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar atividades", "details": err.Error()})
+		return
+	}
+
+	envelope := listEnvelope(page, func(lead model.Lead) dto.LeadFollowUpResponse {
+		response := dto.LeadFollowUpResponse{LeadResponse: dto.NewLeadResponse(lead)}
+		if last, ok := lastActivities[lead.ID]; ok {
+			response.LastActivityAt = &last
+		}
+		return response
+	})
+	envelope["until"] = until
+	c.JSON(http.StatusOK, envelope)
+}
+
+// findUserLead loads the lead in the "id" path parameter for the authenticated user
+func findUserLead(c *gin.Context) (uuid.UUID, *model.Lead, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return uuid.Nil, nil, false
+	}
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return uuid.Nil, nil, false
+	}
+
+	lead, err := repository.NewLeadRepository(config.DB).FindByID(id, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lead não encontrado"})
+		return uuid.Nil, nil, false
+	}
+
+	return userID, lead, true
+}
+
+// canFollowUp refuses follow-ups for closed leads and for people who did not consent to be contacted
+func canFollowUp(c *gin.Context, lead *model.Lead) bool {
+	if lead.Status == model.LeadStatusConverted || lead.Status == model.LeadStatusLost {
+		c.JSON(http.StatusConflict, gin.H{"error": "Lead encerrado não pode ter retorno agendado"})
+		return false
+	}
+	if lead.GdprBlockContact {
+		c.JSON(http.StatusConflict, gin.H{"error": "Lead bloqueado para contato"})
+		return false
+	}
+	return true
+}
+
+// checkLeadStageName rejects a stage name the user already gave to another stage
+func checkLeadStageName(c *gin.Context, stageRepo port.LeadStageRepository, stage model.LeadStage) bool {
+	taken, err := stageRepo.NameTaken(stage.UserID, stage.Name, stage.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar nome da etapa", "details": err.Error()})
+		return false
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "Já existe uma etapa com este nome"})
+		return false
+	}
+	return true
+}
+
+// checkLeadLostReasonName rejects a lost reason name the user already gave to another reason
+func checkLeadLostReasonName(c *gin.Context, reasonRepo port.LeadLostReasonRepository, reason model.LeadLostReason) bool {
+	taken, err := reasonRepo.NameTaken(reason.UserID, reason.Name, reason.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar nome do motivo de perda", "details": err.Error()})
+		return false
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "Já existe um motivo de perda com este nome"})
+		return false
+	}
+	return true
+}
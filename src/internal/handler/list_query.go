@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultListLimit = 10
+	maxListLimit     = 100
+)
+
+// listQueryParams are the query string parameters read by parseListQuery itself;
+// every other parameter is handed to the list as a filter
+var listQueryParams = map[string]bool{
+	"cursor": true,
+	"limit":  true,
+	"sort":   true,
+	"order":  true,
+	"from":   true,
+	"to":     true,
+}
+
+// parseListQuery reads cursor, limit, sort, order and the from/to date range (YYYY-MM-DD with
+// an inclusive end, or RFC 3339) from the query string, answering 400 on invalid values
+func parseListQuery(c *gin.Context) (port.ListQuery, bool) {
+	query := port.ListQuery{
+		Cursor:  c.Query("cursor"),
+		Limit:   defaultListLimit,
+		SortBy:  c.Query("sort"),
+		Filters: map[string]string{},
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limite inválido"})
+			return query, false
+		}
+		query.Limit = min(limit, maxListLimit)
+	}
+
+	switch order := c.Query("order"); order {
+	case "", port.SortAsc, port.SortDesc:
+		query.SortOrder = order
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ordenação inválida", "details": "order"})
+		return query, false
+	}
+
+	var ok bool
+	if query.From, ok = parseListDate(c, "from", false); !ok {
+		return query, false
+	}
+	if query.To, ok = parseListDate(c, "to", true); !ok {
+		return query, false
+	}
+
+	for name, values := range c.Request.URL.Query() {
+		if !listQueryParams[name] && len(values) > 0 {
+			query.Filters[name] = values[0]
+		}
+	}
+
+	return query, true
+}
+
+// parseListDate parses a date bound. A plain date used as the end of the range covers the whole day.
+func parseListDate(c *gin.Context, param string, end bool) (*time.Time, bool) {
+	raw := c.Query(param)
+	if raw == "" {
+		return nil, true
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", raw, time.Local); err == nil {
+		if end {
+			date = date.AddDate(0, 0, 1)
+		}
+		return &date, true
+	}
+	if date, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &date, true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Data inválida", "details": param})
+	return nil, false
+}
+
+// respondList writes a page in the standard list envelope, converting each row
+func respondList[T any, R any](c *gin.Context, page port.Page[T], convert func(T) R) {
+	c.JSON(http.StatusOK, listEnvelope(page, convert))
+}
+
+// listEnvelope builds the standard list envelope of a page, for lists that add fields to it
+func listEnvelope[T any, R any](page port.Page[T], convert func(T) R) gin.H {
+	data := make([]R, 0, len(page.Data))
+	for _, row := range page.Data {
+		data = append(data, convert(row))
+	}
+
+	var nextCursor *string
+	if page.NextCursor != "" {
+		nextCursor = &page.NextCursor
+	}
+
+	return gin.H{
+		"data":        data,
+		"next_cursor": nextCursor,
+		"total":       page.Total,
+	}
+}
+
+// respondListError answers 400 for invalid sort fields, filters or cursors and 500 otherwise
+func respondListError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, port.ErrInvalidSort):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ordenação inválida", "details": err.Error()})
+	case errors.Is(err, port.ErrInvalidFilter):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Filtro inválido", "details": err.Error()})
+	case errors.Is(err, port.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cursor inválido"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message, "details": err.Error()})
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/security"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// loginThrottler is shared by every login endpoint. The in-memory store can be swapped
+// for a persistent port.LoginThrottleStore when running multiple instances.
+var loginThrottler = security.NewLoginThrottler(
+	repository.NewMemoryLoginThrottleStore(time.Hour),
+	security.DefaultEmailThrottleRule,
+	security.DefaultIPThrottleRule,
+)
+
+// GetLoginAttempts returns the failed login attempts against the authenticated user's account
+func GetLoginAttempts(c *gin.Context) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := repository.NewLoginAttemptRepository(config.DB).List(userID, query)
+	if err != nil {
+		respondListError(c, err, "Erro ao buscar tentativas de login")
+		return
+	}
+
+	respondList(c, page, func(attempt model.LoginAttempt) gin.H {
+		return gin.H{
+			"id":         attempt.ID.String(),
+			"ip_address": attempt.IPAddress,
+			"user_agent": attempt.UserAgent,
+			"reason":     attempt.Reason,
+			"created_at": attempt.CreatedAt,
+		}
+	})
+}
+
+// checkLoginThrottle admits the login attempt, counting it against the email and client IP until
+// it proves valid. It responds with 429 and returns false when either is blocked.
+func checkLoginThrottle(c *gin.Context, email string, userID *uuid.UUID) bool {
+	wait, err := loginThrottler.Attempt(email, c.ClientIP(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar tentativas de login"})
+		return false
+	}
+
+	if wait > 0 {
+		recordLoginAttempt(c, email, userID, model.LoginAttemptReasonThrottled)
+
+		seconds := int(math.Ceil(wait.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":               "Muitas tentativas de login. Tente novamente mais tarde",
+			"retry_after_seconds": seconds,
+		})
+		return false
+	}
+
+	return true
+}
+
+// registerLoginFailure records the failure for the account owner. It was already counted for
+// throttling when checkLoginThrottle admitted the attempt.
+func registerLoginFailure(c *gin.Context, email string, userID *uuid.UUID, reason string) {
+	recordLoginAttempt(c, email, userID, reason)
+}
+
+// releaseLoginAttempt gives back an admitted attempt that did not fail
+func releaseLoginAttempt(c *gin.Context, email string) {
+	if err := loginThrottler.Release(email, c.ClientIP()); err != nil {
+		log.Printf("Erro ao liberar tentativa de login: %v", err)
+	}
+}
+
+func recordLoginAttempt(c *gin.Context, email string, userID *uuid.UUID, reason string) {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	attempt := &model.LoginAttempt{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Email:     email,
+		IPAddress: c.ClientIP(),
+		UserAgent: userAgent,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+
+	if err := repository.NewLoginAttemptRepository(config.DB).Save(attempt); err != nil {
+		log.Printf("Erro ao registrar tentativa de login: %v", err)
+	}
+}
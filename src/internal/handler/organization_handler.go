@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/user"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CreateOrganization creates a new organization (admin only)
+func CreateOrganization(c *gin.Context) {
+	var req dto.OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	leadOwnerID, ok := resolveLeadOwner(c, req.LeadOwnerID)
+	if !ok {
+		return
+	}
+
+	organization := model.Organization{
+		ID:          uuid.New(),
+		Name:        req.Name,
+		Slug:        strings.ToLower(strings.TrimSpace(req.Slug)),
+		LeadOwnerID: leadOwnerID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := organization.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	organizationRepo := repository.NewOrganizationRepository(config.DB)
+	if _, err := organizationRepo.FindBySlug(organization.Slug); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug já está em uso"})
+		return
+	}
+
+	if err := organizationRepo.Save(&organization); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar organização", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewOrganizationResponse(organization))
+}
+
+// GetOrganizations lists all organizations (admin only)
+func GetOrganizations(c *gin.Context) {
+	organizations, err := repository.NewOrganizationRepository(config.DB).FindAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar organizações", "details": err.Error()})
+		return
+	}
+
+	responses := make([]dto.OrganizationResponse, len(organizations))
+	for i, organization := range organizations {
+		responses[i] = dto.NewOrganizationResponse(organization)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// UpdateOrganization updates the name, slug and lead owner of an organization (admin only)
+func UpdateOrganization(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var req dto.OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	organizationRepo := repository.NewOrganizationRepository(config.DB)
+	organization, err := organizationRepo.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organização não encontrada"})
+		return
+	}
+
+	leadOwnerID, ok := resolveLeadOwner(c, req.LeadOwnerID)
+	if !ok {
+		return
+	}
+
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if slug != organization.Slug {
+		if _, err := organizationRepo.FindBySlug(slug); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Slug já está em uso"})
+			return
+		}
+	}
+
+	organization.Name = req.Name
+	organization.Slug = slug
+	organization.LeadOwnerID = leadOwnerID
+	organization.UpdatedAt = time.Now()
+
+	if err := organization.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := organizationRepo.Update(organization); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar organização", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewOrganizationResponse(*organization))
+}
+
+// resolveLeadOwner checks that the user chosen to receive public leads exists and is active
+func resolveLeadOwner(c *gin.Context, leadOwnerID *string) (*uuid.UUID, bool) {
+	if leadOwnerID == nil {
+		return nil, true
+	}
+
+	id, err := uuid.Parse(*leadOwnerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do responsável pelos leads inválido"})
+		return nil, false
+	}
+
+	owner, err := repository.NewUserRepository(config.DB).FindByID(id)
+	if err != nil || !owner.IsActive {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Responsável pelos leads não encontrado ou inativo"})
+		return nil, false
+	}
+
+	return &id, true
+}
+
+// CreateTwoFactorPolicy makes 2FA mandatory for an organization and/or role (admin only)
+func CreateTwoFactorPolicy(c *gin.Context) {
+	var req dto.TwoFactorPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	adminID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	policy := model.TwoFactorPolicy{
+		ID:        uuid.New(),
+		Role:      req.Role,
+		CreatedBy: adminID,
+		CreatedAt: time.Now(),
+	}
+
+	if req.OrganizationID != nil {
+		organizationID, err := uuid.Parse(*req.OrganizationID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da organização inválido"})
+			return
+		}
+		if _, err := repository.NewOrganizationRepository(config.DB).FindByID(organizationID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Organização não encontrada"})
+			return
+		}
+		policy.OrganizationID = &organizationID
+	}
+
+	if err := repository.NewTwoFactorPolicyRepository(config.DB).Save(&policy); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar política de 2FA", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, twoFactorPolicyResponse(policy))
+}
+
+// GetTwoFactorPolicies lists the 2FA policies (admin only)
+func GetTwoFactorPolicies(c *gin.Context) {
+	policies, err := repository.NewTwoFactorPolicyRepository(config.DB).FindAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar políticas de 2FA", "details": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, len(policies))
+	for i, policy := range policies {
+		response[i] = twoFactorPolicyResponse(policy)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteTwoFactorPolicy removes a 2FA policy (admin only)
+func DeleteTwoFactorPolicy(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := repository.NewTwoFactorPolicyRepository(config.DB).Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir política de 2FA", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Política de 2FA excluída com sucesso"})
+}
+
+func twoFactorPolicyResponse(policy model.TwoFactorPolicy) gin.H {
+	response := gin.H{
+		"id":         policy.ID.String(),
+		"role":       policy.Role,
+		"created_by": policy.CreatedBy.String(),
+		"created_at": policy.CreatedAt,
+	}
+
+	if policy.OrganizationID != nil {
+		response["organization_id"] = policy.OrganizationID.String()
+	}
+
+	return response
+}
@@ -0,0 +1,360 @@
+package handler
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/anamnese"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// UpdatePatientAnamneseAnswers changes some answers of a draft patient anamnese, keeping the
+// history of the previous values. Required fields may still be left empty.
+func UpdatePatientAnamneseAnswers(c *gin.Context) {
+	var req dto.PatientAnamneseAnswersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	patientAnamnese, ok := findOwnPatientAnamnese(c)
+	if !ok {
+		return
+	}
+
+	if !patientAnamnese.IsDraft() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Finalized anamneses cannot be changed"})
+		return
+	}
+
+	versionFields, err := repository.NewAnamneseFieldRepository(config.DB).FindByVersionID(patientAnamnese.TemplateVersionID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template version fields", "details": err.Error()})
+		return
+	}
+
+	requested, ok := requestAnswers(c, req.Fields)
+	if !ok {
+		return
+	}
+
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+	now := time.Now()
+	patientAnamnese.UpdatedAt = now
+
+	// The changes are computed from the answers stored when the anamnese is locked, so that
+	// concurrent changes are applied one after the other
+	patientAnamneseRepo := repository.NewPatientAnamneseRepository(config.DB)
+	err = patientAnamneseRepo.UpdateAnswers(patientAnamnese, func(stored []*model.PatientAnamneseField) (port.PatientAnamneseAnswerChanges, error) {
+		return answerChanges(patientAnamnese, versionFields, stored, requested, userID, now)
+	})
+	if err != nil {
+		var errs validation.FieldErrors
+		switch {
+		case errors.As(err, &errs):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		case errors.Is(err, port.ErrPatientAnamneseFinalized):
+			c.JSON(http.StatusConflict, gin.H{"error": "Finalized anamneses cannot be changed"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update patient anamnese", "details": err.Error()})
+		}
+		return
+	}
+
+	_, stored, ok := loadPatientAnamneseAnswers(c, patientAnamnese)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, patientAnamneseResponse(patientAnamnese, versionFields, stored))
+}
+
+// answerChanges computes the answers to save and remove, and their history, when the
+// requested answers replace the stored ones. The other stored answers are checked again,
+// since the visibility of fields may depend on the answers that changed.
+func answerChanges(patientAnamnese *model.PatientAnamnese, versionFields []*model.AnamneseField, stored []*model.PatientAnamneseField, requested []helper.AnamneseAnswer, userID uuid.UUID, now time.Time) (port.PatientAnamneseAnswerChanges, error) {
+	var changes port.PatientAnamneseAnswerChanges
+
+	patched := make(map[uuid.UUID]bool, len(requested))
+	answers := make([]helper.AnamneseAnswer, 0, len(requested)+len(stored))
+	for _, answer := range requested {
+		patched[answer.FieldID] = true
+		answers = append(answers, answer)
+	}
+	for _, answer := range storedAnswers(stored) {
+		if !patched[answer.FieldID] {
+			answers = append(answers, answer)
+		}
+	}
+
+	answers, errs := helper.ValidateAnamneseDraft(versionFields, answers)
+	if len(errs) > 0 {
+		return changes, errs
+	}
+
+	byField := make(map[uuid.UUID]*model.PatientAnamneseField, len(stored))
+	for _, field := range stored {
+		byField[field.FieldID] = field
+	}
+
+	kept := make(map[uuid.UUID]bool, len(answers))
+	for _, answer := range answers {
+		kept[answer.FieldID] = true
+		values, err := encodeAnswerValues(answer.Values)
+		if err != nil {
+			return changes, err
+		}
+
+		change := &model.PatientAnamneseFieldHistory{
+			ID:                uuid.New(),
+			PatientAnamneseID: patientAnamnese.ID,
+			FieldID:           answer.FieldID,
+			Value:             answer.Value,
+			Values:            values,
+			ChangedBy:         userID,
+			ChangedAt:         now,
+		}
+
+		field, exists := byField[answer.FieldID]
+		if !exists {
+			field = &model.PatientAnamneseField{
+				ID:                uuid.New(),
+				PatientAnamneseID: patientAnamnese.ID,
+				FieldID:           answer.FieldID,
+				CreatedAt:         now,
+			}
+		} else if field.Value == answer.Value && sameAnswerValues(field.Values, values) {
+			continue
+		} else {
+			change.PreviousValue, change.PreviousValues = field.Value, field.Values
+		}
+
+		field.Value, field.Values, field.UpdatedAt = answer.Value, values, now
+		changes.Saved = append(changes.Saved, field)
+		changes.History = append(changes.History, change)
+	}
+
+	for _, field := range stored {
+		if kept[field.FieldID] {
+			continue
+		}
+		changes.Removed = append(changes.Removed, field.ID)
+		changes.History = append(changes.History, &model.PatientAnamneseFieldHistory{
+			ID:                uuid.New(),
+			PatientAnamneseID: patientAnamnese.ID,
+			FieldID:           field.FieldID,
+			PreviousValue:     field.Value,
+			PreviousValues:    field.Values,
+			ChangedBy:         userID,
+			ChangedAt:         now,
+		})
+	}
+
+	return changes, nil
+}
+
+// FinalizePatientAnamnese locks the answers of a draft patient anamnese once every required
+// field shown is answered
+func FinalizePatientAnamnese(c *gin.Context) {
+	patientAnamnese, ok := findOwnPatientAnamnese(c)
+	if !ok {
+		return
+	}
+
+	if !patientAnamnese.IsDraft() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Anamnese is already finalized"})
+		return
+	}
+
+	versionFields, err := repository.NewAnamneseFieldRepository(config.DB).FindByVersionID(patientAnamnese.TemplateVersionID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template version fields", "details": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	patientAnamnese.Status = model.PatientAnamneseStatusFinalized
+	patientAnamnese.AnsweredAt = now
+	patientAnamnese.FinalizedAt = &now
+	patientAnamnese.UpdatedAt = now
+
+	// The answers are checked as stored when the anamnese is locked
+	var stored []*model.PatientAnamneseField
+	patientAnamneseRepo := repository.NewPatientAnamneseRepository(config.DB)
+	err = patientAnamneseRepo.Finalize(patientAnamnese, func(answers []*model.PatientAnamneseField) error {
+		stored = answers
+		if _, errs := helper.ValidateAnamneseAnswers(versionFields, storedAnswers(answers)); len(errs) > 0 {
+			return errs
+		}
+		return nil
+	})
+	if err != nil {
+		var errs validation.FieldErrors
+		switch {
+		case errors.As(err, &errs):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		case errors.Is(err, port.ErrPatientAnamneseFinalized):
+			c.JSON(http.StatusConflict, gin.H{"error": "Anamnese is already finalized"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to finalize patient anamnese", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, patientAnamneseResponse(patientAnamnese, versionFields, stored))
+}
+
+// GetPatientAnamneseHistory returns the changes made to the answers of a patient anamnese,
+// most recent first
+func GetPatientAnamneseHistory(c *gin.Context) {
+	patientAnamnese, ok := findOwnPatientAnamnese(c)
+	if !ok {
+		return
+	}
+
+	history, err := repository.NewPatientAnamneseFieldRepository(config.DB).FindHistoryByPatientAnamneseID(patientAnamnese.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch answer history", "details": err.Error()})
+		return
+	}
+
+	versionFields, err := repository.NewAnamneseFieldRepository(config.DB).FindByVersionID(patientAnamnese.TemplateVersionID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template version fields", "details": err.Error()})
+		return
+	}
+	titles := make(map[uuid.UUID]string, len(versionFields))
+	for _, field := range versionFields {
+		titles[field.ID] = field.FieldTitle
+	}
+
+	response := make([]gin.H, len(history))
+	for i, change := range history {
+		response[i] = gin.H{
+			"id":              change.ID.String(),
+			"field_id":        change.FieldID.String(),
+			"field_title":     titles[change.FieldID],
+			"previous_value":  change.PreviousValue,
+			"previous_values": decodeAnswerValues(change.PreviousValues),
+			"value":           change.Value,
+			"values":          decodeAnswerValues(change.Values),
+			"changed_by":      change.ChangedBy.String(),
+			"changed_at":      change.ChangedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// findOwnPatientAnamnese finds the patient anamnese of the URL, answering the error when it
+// does not exist, belongs to another patient or to another user
+func findOwnPatientAnamnese(c *gin.Context) (*model.PatientAnamnese, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return nil, false
+	}
+
+	clientID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+		return nil, false
+	}
+
+	patientAnamnese, err := repository.NewPatientAnamneseRepository(config.DB).FindByID(c.Param("id"))
+	if err != nil || patientAnamnese.PatientID.String() != c.Param("patient_id") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Patient anamnese not found"})
+		return nil, false
+	}
+
+	if patientAnamnese.UserID != clientID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this anamnese"})
+		return nil, false
+	}
+
+	return patientAnamnese, true
+}
+
+// loadPatientAnamneseAnswers loads the fields of the version a patient anamnese refers to,
+// with their options, and its stored answers
+func loadPatientAnamneseAnswers(c *gin.Context, patientAnamnese *model.PatientAnamnese) ([]*model.AnamneseField, []*model.PatientAnamneseField, bool) {
+	versionFields, err := repository.NewAnamneseFieldRepository(config.DB).FindByVersionID(patientAnamnese.TemplateVersionID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template version fields", "details": err.Error()})
+		return nil, nil, false
+	}
+
+	stored, err := repository.NewPatientAnamneseFieldRepository(config.DB).FindByPatientAnamneseID(patientAnamnese.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch patient anamnese fields", "details": err.Error()})
+		return nil, nil, false
+	}
+
+	return versionFields, stored, true
+}
+
+// patientAnamneseResponse renders a patient anamnese with its answers, the questions as they
+// were in the version answered, and how much of it is filled in
+func patientAnamneseResponse(patientAnamnese *model.PatientAnamnese, versionFields []*model.AnamneseField, stored []*model.PatientAnamneseField) gin.H {
+	questions := make(map[uuid.UUID]*model.AnamneseField, len(versionFields))
+	for _, field := range versionFields {
+		questions[field.ID] = field
+	}
+
+	fieldResponses := make([]gin.H, len(stored))
+	for i, field := range stored {
+		fieldResponses[i] = gin.H{
+			"id":                  field.ID.String(),
+			"patient_anamnese_id": field.PatientAnamneseID.String(),
+			"field_id":            field.FieldID.String(),
+			"value":               field.Value,
+		}
+		if values := decodeAnswerValues(field.Values); values != nil {
+			fieldResponses[i]["values"] = values
+		}
+		if question, ok := questions[field.FieldID]; ok {
+			fieldResponses[i]["field_title"] = question.FieldTitle
+			fieldResponses[i]["field_type"] = question.FieldType
+		}
+	}
+
+	return gin.H{
+		"id":                  patientAnamnese.ID.String(),
+		"patient_id":          patientAnamnese.PatientID.String(),
+		"anamnese_id":         patientAnamnese.AnamneseID.String(),
+		"template_version_id": patientAnamnese.TemplateVersionID.String(),
+		"client_id":           patientAnamnese.UserID.String(),
+		"status":              patientAnamnese.Status,
+		"completion":          helper.AnamneseCompletion(versionFields, storedAnswers(stored)),
+		"answered_at":         patientAnamnese.AnsweredAt,
+		"finalized_at":        patientAnamnese.FinalizedAt,
+		"fields":              fieldResponses,
+	}
+}
+
+// storedAnswers turns stored answers back into answers, as they would be sent again
+func storedAnswers(stored []*model.PatientAnamneseField) []helper.AnamneseAnswer {
+	answers := make([]helper.AnamneseAnswer, len(stored))
+	for i, field := range stored {
+		if values := decodeAnswerValues(field.Values); values != nil {
+			answers[i] = helper.AnamneseAnswer{FieldID: field.FieldID, Values: values}
+		} else {
+			answers[i] = helper.AnamneseAnswer{FieldID: field.FieldID, Value: field.Value}
+		}
+	}
+	return answers
+}
+
+func sameAnswerValues(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
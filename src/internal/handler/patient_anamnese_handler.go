@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"encoding/json"
 	"github.com/LacirJR/psygrow-api/src/internal/config"
 	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/anamnese"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
 	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
 	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -49,7 +52,6 @@ func CreatePatientAnamnese(c *gin.Context) {
 	// Create repositories
 	templateRepo := repository.NewAnamneseTemplateRepository(config.DB)
 	patientAnamneseRepo := repository.NewPatientAnamneseRepository(config.DB)
-	patientAnamneseFieldRepo := repository.NewPatientAnamneseFieldRepository(config.DB)
 
 	// Check if template exists and belongs to the user
 	template, err := templateRepo.FindByID(req.AnamneseID)
@@ -63,55 +65,78 @@ func CreatePatientAnamnese(c *gin.Context) {
 		return
 	}
 
+	// Answers always refer to the latest published version of the template
+	versionRepo := repository.NewAnamneseTemplateVersionRepository(config.DB)
+	version, err := versionRepo.FindLatestPublished(template.ID.String())
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Template has no published version"})
+		return
+	}
+
+	// Check the answers against the fields of the version
+	versionFields, err := repository.NewAnamneseFieldRepository(config.DB).FindByVersionID(version.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template version fields", "details": err.Error()})
+		return
+	}
+
+	answers, ok := requestAnswers(c, req.Fields)
+	if !ok {
+		return
+	}
+	validate := helper.ValidateAnamneseAnswers
+	if req.Status == model.PatientAnamneseStatusDraft {
+		validate = helper.ValidateAnamneseDraft
+	}
+	answers, errs := validate(versionFields, answers)
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return
+	}
+
 	// Create patient anamnese ID
 	patientAnamneseID := uuid.New()
 
 	// Create patient anamnese model
 	patientAnamnese := &model.PatientAnamnese{
-		ID:         patientAnamneseID,
-		PatientID:  patientID,
-		AnamneseID: anamneseID,
-		UserID:     userIDParsed,
-		AnsweredAt: time.Now(),
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		ID:                patientAnamneseID,
+		PatientID:         patientID,
+		AnamneseID:        anamneseID,
+		TemplateVersionID: version.ID,
+		UserID:            userIDParsed,
+		Status:            model.PatientAnamneseStatusFinalized,
+		AnsweredAt:        time.Now(),
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+	if req.Status == model.PatientAnamneseStatusDraft {
+		patientAnamnese.Status = model.PatientAnamneseStatusDraft
+	} else {
+		patientAnamnese.FinalizedAt = &patientAnamnese.AnsweredAt
 	}
 
-	// Save patient anamnese
-	if err := patientAnamneseRepo.Save(patientAnamnese); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create patient anamnese", "details": err.Error()})
+	patientAnamneseFields, err := newPatientAnamneseFields(patientAnamneseID, answers)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode answers", "details": err.Error()})
 		return
 	}
 
-	// Save patient anamnese fields
-	for _, field := range req.Fields {
-		fieldID, err := uuid.Parse(field.FieldID)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field ID format", "field_id": field.FieldID})
-			return
-		}
-
-		patientAnamneseField := &model.PatientAnamneseField{
-			ID:                uuid.New(),
-			PatientAnamneseID: patientAnamneseID,
-			FieldID:           fieldID,
-			Value:             field.Value,
-			CreatedAt:         time.Now(),
-			UpdatedAt:         time.Now(),
-		}
-
-		if err := patientAnamneseFieldRepo.Save(patientAnamneseField); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create patient anamnese field", "details": err.Error()})
-			return
-		}
+	// Save patient anamnese and its answers
+	if err := patientAnamneseRepo.SaveWithFields(patientAnamnese, patientAnamneseFields); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create patient anamnese", "details": err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"id":          patientAnamneseID.String(),
-		"patient_id":  patientID.String(),
-		"anamnese_id": anamneseID.String(),
-		"user_id":     userIDParsed.String(),
-		"answered_at": patientAnamnese.AnsweredAt,
+		"id":                  patientAnamneseID.String(),
+		"patient_id":          patientID.String(),
+		"anamnese_id":         anamneseID.String(),
+		"template_version_id": version.ID.String(),
+		"user_id":             userIDParsed.String(),
+		"status":              patientAnamnese.Status,
+		"completion":          helper.AnamneseCompletion(versionFields, answers),
+		"answered_at":         patientAnamnese.AnsweredAt,
+		"finalized_at":        patientAnamnese.FinalizedAt,
 	})
 }
 
@@ -156,11 +181,14 @@ func GetPatientAnamneses(c *gin.Context) {
 	response := make([]gin.H, len(filteredAnamneses))
 	for i, anamnese := range filteredAnamneses {
 		response[i] = gin.H{
-			"id":          anamnese.ID.String(),
-			"patient_id":  anamnese.PatientID.String(),
-			"anamnese_id": anamnese.AnamneseID.String(),
-			"client_id":   anamnese.UserID.String(),
-			"answered_at": anamnese.AnsweredAt,
+			"id":                  anamnese.ID.String(),
+			"patient_id":          anamnese.PatientID.String(),
+			"anamnese_id":         anamnese.AnamneseID.String(),
+			"template_version_id": anamnese.TemplateVersionID.String(),
+			"client_id":           anamnese.UserID.String(),
+			"status":              anamnese.Status,
+			"answered_at":         anamnese.AnsweredAt,
+			"finalized_at":        anamnese.FinalizedAt,
 		}
 	}
 
@@ -169,67 +197,83 @@ func GetPatientAnamneses(c *gin.Context) {
 
 // GetPatientAnamneseDetails returns details of a specific patient anamnese including fields
 func GetPatientAnamneseDetails(c *gin.Context) {
-	// Get user ID from context (set by auth middleware)
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+	patientAnamnese, ok := findOwnPatientAnamnese(c)
+	if !ok {
 		return
 	}
 
-	// Parse user ID
-	clientID, err := uuid.Parse(userID.(string))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	versionFields, fields, ok := loadPatientAnamneseAnswers(c, patientAnamnese)
+	if !ok {
 		return
 	}
 
-	// Parse patient anamnese ID from URL
-	patientAnamneseID := c.Param("id")
-
-	// Create repositories
-	patientAnamneseRepo := repository.NewPatientAnamneseRepository(config.DB)
-	patientAnamneseFieldRepo := repository.NewPatientAnamneseFieldRepository(config.DB)
+	c.JSON(http.StatusOK, patientAnamneseResponse(patientAnamnese, versionFields, fields))
+}
 
-	// Get patient anamnese
-	patientAnamnese, err := patientAnamneseRepo.FindByID(patientAnamneseID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Patient anamnese not found"})
-		return
+// requestAnswers reads the answers sent in a request, answering 400 keyed by the field_id
+// that is not a valid ID
+func requestAnswers(c *gin.Context, fields []dto.PatientAnamneseFieldRequest) ([]helper.AnamneseAnswer, bool) {
+	errs := validation.FieldErrors{}
+	answers := make([]helper.AnamneseAnswer, len(fields))
+	for i, field := range fields {
+		fieldID, err := uuid.Parse(field.FieldID)
+		if err != nil {
+			errs.Add(field.FieldID, helper.ErrAnswerInvalidFieldID)
+			continue
+		}
+		answers[i] = helper.AnamneseAnswer{FieldID: fieldID, Value: field.Value, Values: field.Values}
+	}
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return nil, false
 	}
+	return answers, true
+}
 
-	// Check if anamnese belongs to the authenticated user
-	if patientAnamnese.UserID != clientID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this anamnese"})
-		return
+// newPatientAnamneseFields builds the rows of validated answers, keeping the options of
+// multiselect fields as a JSON array
+func newPatientAnamneseFields(patientAnamneseID uuid.UUID, answers []helper.AnamneseAnswer) ([]*model.PatientAnamneseField, error) {
+	fields := make([]*model.PatientAnamneseField, len(answers))
+	for i, answer := range answers {
+		fields[i] = &model.PatientAnamneseField{
+			ID:                uuid.New(),
+			PatientAnamneseID: patientAnamneseID,
+			FieldID:           answer.FieldID,
+			Value:             answer.Value,
+			CreatedAt:         time.Now(),
+			UpdatedAt:         time.Now(),
+		}
+		values, err := encodeAnswerValues(answer.Values)
+		if err != nil {
+			return nil, err
+		}
+		fields[i].Values = values
 	}
+	return fields, nil
+}
 
-	// Get patient anamnese fields
-	fields, err := patientAnamneseFieldRepo.FindByPatientAnamneseID(patientAnamneseID)
+// encodeAnswerValues encodes the options chosen in a multiselect answer as a JSON array, or
+// nil for other answers
+func encodeAnswerValues(values []string) (*string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(values)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch patient anamnese fields", "details": err.Error()})
-		return
+		return nil, err
 	}
+	text := string(encoded)
+	return &text, nil
+}
 
-	// Convert fields to response format
-	fieldResponses := make([]gin.H, len(fields))
-	for i, field := range fields {
-		fieldResponses[i] = gin.H{
-			"id":                  field.ID.String(),
-			"patient_anamnese_id": field.PatientAnamneseID.String(),
-			"field_id":            field.FieldID.String(),
-			"value":               field.Value,
-		}
+// decodeAnswerValues decodes the options chosen in a multiselect answer, or nil for other answers
+func decodeAnswerValues(encoded *string) []string {
+	if encoded == nil {
+		return nil
 	}
-
-	// Create response
-	response := gin.H{
-		"id":          patientAnamnese.ID.String(),
-		"patient_id":  patientAnamnese.PatientID.String(),
-		"anamnese_id": patientAnamnese.AnamneseID.String(),
-		"client_id":   patientAnamnese.UserID.String(),
-		"answered_at": patientAnamnese.AnsweredAt,
-		"fields":      fieldResponses,
+	var values []string
+	if err := json.Unmarshal([]byte(*encoded), &values); err != nil {
+		return nil
 	}
-
-	c.JSON(http.StatusOK, response)
+	return values
 }
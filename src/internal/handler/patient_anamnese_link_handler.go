@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/anamnese"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/security"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"net/http"
+	"time"
+)
+
+// CreatePatientAnamneseLink creates a single-use link through which the patient, or a guardian,
+// answers the latest published version of a template from their own device
+func CreatePatientAnamneseLink(c *gin.Context) {
+	patient, ok := findLinkPatient(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PatientAnamneseLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	template, ok := findOwnTemplate(c, req.AnamneseID)
+	if !ok {
+		return
+	}
+
+	version, err := repository.NewAnamneseTemplateVersionRepository(config.DB).FindLatestPublished(template.ID.String())
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Template has no published version"})
+		return
+	}
+
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate link"})
+		return
+	}
+
+	ttl := security.AnamneseLinkTTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	link := &model.PatientAnamneseLink{
+		ID:                uuid.New(),
+		UserID:            template.UserID,
+		PatientID:         patient.ID,
+		AnamneseID:        template.ID,
+		TemplateVersionID: version.ID,
+		TokenHash:         security.HashOpaqueToken(token),
+		ExpiresAt:         time.Now().Add(ttl),
+		CreatedAt:         time.Now(),
+	}
+
+	if err := repository.NewPatientAnamneseLinkRepository(config.DB).Save(link); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create link", "details": err.Error()})
+		return
+	}
+
+	response := dto.NewPatientAnamneseLinkResponse(*link, time.Now())
+	response.Token = token
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetPatientAnamneseLinks returns the self-service links sent to a patient, most recent first
+func GetPatientAnamneseLinks(c *gin.Context) {
+	patient, ok := findLinkPatient(c)
+	if !ok {
+		return
+	}
+
+	links, err := repository.NewPatientAnamneseLinkRepository(config.DB).FindByPatient(patient.ID, patient.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch links", "details": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	response := make([]dto.PatientAnamneseLinkResponse, len(links))
+	for i, link := range links {
+		response[i] = dto.NewPatientAnamneseLinkResponse(link, now)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokePatientAnamneseLink prevents a link not yet used from being used
+func RevokePatientAnamneseLink(c *gin.Context) {
+	link, ok := findPatientAnamneseLink(c)
+	if !ok {
+		return
+	}
+
+	if link.UsedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Link was already used"})
+		return
+	}
+
+	if link.RevokedAt == nil {
+		now := time.Now()
+		link.RevokedAt = &now
+		if err := repository.NewPatientAnamneseLinkRepository(config.DB).Revoke(link); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke link", "details": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.NewPatientAnamneseLinkResponse(*link, time.Now()))
+}
+
+// GetPatientAnamneseLinkAccesses returns the access log of a link, most recent first
+func GetPatientAnamneseLinkAccesses(c *gin.Context) {
+	link, ok := findPatientAnamneseLink(c)
+	if !ok {
+		return
+	}
+
+	accesses, err := repository.NewPatientAnamneseLinkRepository(config.DB).FindAccesses(link.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch link accesses", "details": err.Error()})
+		return
+	}
+
+	response := make([]dto.PatientAnamneseLinkAccessResponse, len(accesses))
+	for i, access := range accesses {
+		response[i] = dto.NewPatientAnamneseLinkAccessResponse(access)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPublicAnamneseForm returns the questions of the anamnese a link gives access to
+func GetPublicAnamneseForm(c *gin.Context) {
+	link, ok := usablePatientAnamneseLink(c, model.PatientAnamneseLinkActionView)
+	if !ok {
+		return
+	}
+
+	template, err := repository.NewAnamneseTemplateRepository(config.DB).FindByID(link.AnamneseID.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Anamnese não encontrada"})
+		return
+	}
+
+	versionFields, err := repository.NewAnamneseFieldRepository(config.DB).FindByVersionID(link.TemplateVersionID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar perguntas da anamnese"})
+		return
+	}
+
+	fields := make([]gin.H, 0, len(versionFields))
+	for _, field := range versionFields {
+		if !field.FieldActive {
+			continue
+		}
+		options := make([]gin.H, len(field.Options))
+		for j, option := range field.Options {
+			options[j] = gin.H{
+				"option_value": option.OptionValue,
+				"option_order": option.OptionOrder,
+			}
+		}
+		fields = append(fields, gin.H{
+			"id":             field.ID.String(),
+			"field_number":   field.FieldNumber,
+			"field_type":     field.FieldType,
+			"field_title":    field.FieldTitle,
+			"field_required": field.FieldRequired,
+			"options":        options,
+			"visibility":     fieldVisibility(field, versionFields),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"title":      template.Title,
+		"expires_at": link.ExpiresAt,
+		"fields":     fields,
+	})
+}
+
+// SubmitPublicAnamnese receives the answers sent through a link. They are saved as a draft
+// for the professional to review and finalize, and the link can no longer be used.
+func SubmitPublicAnamnese(c *gin.Context) {
+	var req dto.PatientAnamneseAnswersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	link, ok := usablePatientAnamneseLink(c, "")
+	if !ok {
+		return
+	}
+
+	versionFields, err := repository.NewAnamneseFieldRepository(config.DB).FindByVersionID(link.TemplateVersionID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar perguntas da anamnese"})
+		return
+	}
+
+	answers, ok := requestAnswers(c, req.Fields)
+	if !ok {
+		return
+	}
+	answers, errs := helper.ValidateAnamneseDraft(versionFields, answers)
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return
+	}
+
+	now := time.Now()
+	patientAnamnese := &model.PatientAnamnese{
+		ID:                uuid.New(),
+		PatientID:         link.PatientID,
+		AnamneseID:        link.AnamneseID,
+		TemplateVersionID: link.TemplateVersionID,
+		UserID:            link.UserID,
+		Status:            model.PatientAnamneseStatusDraft,
+		AnsweredAt:        now,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	fields, err := newPatientAnamneseFields(patientAnamnese.ID, answers)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar respostas"})
+		return
+	}
+
+	link.UsedAt = &now
+	if err := repository.NewPatientAnamneseLinkRepository(config.DB).Consume(link, patientAnamnese, fields); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logPatientAnamneseLinkAccess(c, link, model.PatientAnamneseLinkActionRejected)
+			c.JSON(http.StatusGone, gin.H{"error": "Link expirado ou já utilizado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar respostas"})
+		return
+	}
+
+	logPatientAnamneseLinkAccess(c, link, model.PatientAnamneseLinkActionSubmit)
+	c.JSON(http.StatusCreated, gin.H{"message": "Respostas enviadas com sucesso"})
+}
+
+// findLinkPatient finds the patient of the URL among the authenticated user's patients
+func findLinkPatient(c *gin.Context) (*model.Patient, bool) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return nil, false
+	}
+
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid patient ID format"})
+		return nil, false
+	}
+
+	patient, err := repository.NewPatientRepository(config.DB).FindByID(patientID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Patient not found"})
+		return nil, false
+	}
+	return patient, true
+}
+
+// findPatientAnamneseLink finds the link of the URL among the links the authenticated user
+// sent to the patient of the URL
+func findPatientAnamneseLink(c *gin.Context) (*model.PatientAnamneseLink, bool) {
+	patient, ok := findLinkPatient(c)
+	if !ok {
+		return nil, false
+	}
+
+	linkID, err := uuid.Parse(c.Param("link_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link ID format"})
+		return nil, false
+	}
+
+	link, err := repository.NewPatientAnamneseLinkRepository(config.DB).FindByID(linkID, patient.UserID)
+	if err != nil || link.PatientID != patient.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return nil, false
+	}
+	return link, true
+}
+
+// usablePatientAnamneseLink finds the link of the token in the URL, logging the access with the
+// given action, if any. Attempts to use a link that can no longer be used are always logged.
+func usablePatientAnamneseLink(c *gin.Context, action string) (*model.PatientAnamneseLink, bool) {
+	linkRepo := repository.NewPatientAnamneseLinkRepository(config.DB)
+	link, err := linkRepo.FindByTokenHash(security.HashOpaqueToken(c.Param("token")))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link inválido"})
+		return nil, false
+	}
+
+	if !link.IsUsableAt(time.Now()) {
+		logPatientAnamneseLinkAccess(c, link, model.PatientAnamneseLinkActionRejected)
+		c.JSON(http.StatusGone, gin.H{"error": "Link expirado ou já utilizado"})
+		return nil, false
+	}
+
+	if action != "" {
+		logPatientAnamneseLinkAccess(c, link, action)
+	}
+	return link, true
+}
+
+func logPatientAnamneseLinkAccess(c *gin.Context, link *model.PatientAnamneseLink, action string) {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	access := &model.PatientAnamneseLinkAccess{
+		ID:        uuid.New(),
+		LinkID:    link.ID,
+		Action:    action,
+		IPAddress: c.ClientIP(),
+		UserAgent: userAgent,
+		CreatedAt: time.Now(),
+	}
+
+	if err := repository.NewPatientAnamneseLinkRepository(config.DB).LogAccess(access); err != nil {
+		log.Printf("Erro ao registrar acesso ao link de anamnese: %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/patient"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+// CreatePatientConsent records a consent signed for a patient. Consents of minors must be
+// signed by a family member who is legal responsible for the patient.
+func CreatePatientConsent(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	var req dto.PatientConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if req.ValidUntil != nil && !req.ValidUntil.After(req.SignedAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A validade deve ser posterior à data de assinatura"})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	patientRepo := repository.NewPatientRepository(config.DB)
+	patient, err := patientRepo.FindByID(patientID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return
+	}
+
+	if req.GuardianID != nil {
+		guardian, err := repository.NewPatientFamilyRepository(config.DB).FindByID(*req.GuardianID)
+		if err != nil || guardian.PatientID != patient.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Responsável não encontrado"})
+			return
+		}
+		if !guardian.IsLegalResponsible {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "O familiar não é responsável legal do paciente"})
+			return
+		}
+	} else if patient.IsMinorAt(req.SignedAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O consentimento de paciente menor deve ser assinado por um responsável legal"})
+		return
+	}
+
+	consent := model.PatientConsent{
+		ID:                uuid.New(),
+		PatientID:         patient.ID,
+		GuardianID:        req.GuardianID,
+		Type:              req.Type,
+		SignedAt:          req.SignedAt,
+		ValidUntil:        req.ValidUntil,
+		DocumentReference: req.DocumentReference,
+		Notes:             req.Notes,
+		CreatedAt:         time.Now(),
+	}
+
+	if err := consent.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	consentRepo := repository.NewPatientConsentRepository(config.DB)
+	if err := consentRepo.Create(&consent); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar consentimento", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewPatientConsentResponse(consent))
+}
+
+// GetPatientConsents gets all consents of a patient
+func GetPatientConsents(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	// Verify that the patient belongs to the user
+	patientRepo := repository.NewPatientRepository(config.DB)
+	_, err = patientRepo.FindByID(patientID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return
+	}
+
+	consentRepo := repository.NewPatientConsentRepository(config.DB)
+	consents, err := consentRepo.FindByPatient(patientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar consentimentos", "details": err.Error()})
+		return
+	}
+
+	responses := []dto.PatientConsentResponse{}
+	for _, consent := range consents {
+		responses = append(responses, dto.NewPatientConsentResponse(consent))
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// RevokePatientConsent revokes a consent from now on, keeping its record
+func RevokePatientConsent(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	// Verify that the patient belongs to the user
+	patientRepo := repository.NewPatientRepository(config.DB)
+	_, err = patientRepo.FindByID(patientID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return
+	}
+
+	consentRepo := repository.NewPatientConsentRepository(config.DB)
+	consent, err := consentRepo.FindByID(id, patientID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Consentimento não encontrado"})
+		return
+	}
+
+	if consent.RevokedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Consentimento já revogado"})
+		return
+	}
+
+	now := time.Now()
+	consent.RevokedAt = &now
+	if err := consentRepo.Update(consent); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao revogar consentimento", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPatientConsentResponse(*consent))
+}
+
+// hasTreatmentConsent reports whether the patient may be seen at the given time: adults always
+// may, minors need a treatment consent valid at that time signed by a legal responsible
+func hasTreatmentConsent(patient *model.Patient, at time.Time) (bool, error) {
+	if !patient.IsMinorAt(at) {
+		return true, nil
+	}
+
+	consentRepo := repository.NewPatientConsentRepository(config.DB)
+	_, err := consentRepo.FindValidByGuardian(patient.ID, model.ConsentTypeTreatment, at)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
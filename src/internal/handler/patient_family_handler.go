@@ -49,15 +49,30 @@ func CreatePatientFamily(c *gin.Context) {
 	}
 
 	patientFamily := model.PatientFamily{
-		ID:           uuid.New(),
-		PatientID:    req.PatientID,
-		Relationship: req.Relationship,
-		Name:         req.Name,
-		BirthDate:    req.BirthDate,
-		Schooling:    req.Schooling,
-		Occupation:   req.Occupation,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		ID:                     uuid.New(),
+		PatientID:              req.PatientID,
+		Relationship:           req.Relationship,
+		Name:                   req.Name,
+		BirthDate:              req.BirthDate,
+		Schooling:              req.Schooling,
+		Occupation:             req.Occupation,
+		Document:               req.Document,
+		Phone:                  req.Phone,
+		Email:                  req.Email,
+		Address:                req.Address.ToModel(),
+		IsLegalResponsible:     req.IsLegalResponsible,
+		IsFinancialResponsible: req.IsFinancialResponsible,
+		Gender:                 req.Gender,
+		IsDeceased:             req.IsDeceased || req.DeathDate != nil,
+		DeathDate:              req.DeathDate,
+		Notes:                  req.Notes,
+		CreatedAt:              time.Now(),
+		UpdatedAt:              time.Now(),
+	}
+
+	if errs := patientFamily.NormalizeContact(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return
 	}
 
 	patientFamilyRepo := repository.NewPatientFamilyRepository(config.DB)
@@ -198,8 +213,23 @@ func UpdatePatientFamily(c *gin.Context) {
 	patientFamily.BirthDate = req.BirthDate
 	patientFamily.Schooling = req.Schooling
 	patientFamily.Occupation = req.Occupation
+	patientFamily.Document = req.Document
+	patientFamily.Phone = req.Phone
+	patientFamily.Email = req.Email
+	patientFamily.Address = req.Address.ToModel()
+	patientFamily.IsLegalResponsible = req.IsLegalResponsible
+	patientFamily.IsFinancialResponsible = req.IsFinancialResponsible
+	patientFamily.Gender = req.Gender
+	patientFamily.IsDeceased = req.IsDeceased || req.DeathDate != nil
+	patientFamily.DeathDate = req.DeathDate
+	patientFamily.Notes = req.Notes
 	patientFamily.UpdatedAt = time.Now()
 
+	if errs := patientFamily.NormalizeContact(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return
+	}
+
 	if err := patientFamilyRepo.Update(patientFamily); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar familiar do paciente", "details": err.Error()})
 		return
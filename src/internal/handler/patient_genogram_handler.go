@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/patient"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// GetPatientGenogram gets the family tree of a patient as nodes and edges, or as a
+// GEDCOM file with format=gedcom
+func GetPatientGenogram(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "gedcom" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido", "details": "format"})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	genogram, ok := loadGenogram(c, patientID, userID)
+	if !ok {
+		return
+	}
+
+	if format == "gedcom" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=genogram-%s.ged", patientID))
+		c.Data(http.StatusOK, "application/x-gedcom; charset=utf-8", []byte(genogram.GEDCOM()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewGenogramResponse(patientID, genogram))
+}
+
+// CreatePatientFamilyLink links two people of a patient's family tree
+func CreatePatientFamilyLink(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	var req dto.PatientFamilyLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if req.FromID == req.ToID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Uma pessoa não pode ser vinculada a ela mesma"})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	genogram, ok := loadGenogram(c, patientID, userID)
+	if !ok {
+		return
+	}
+
+	inTree := make(map[uuid.UUID]bool, len(genogram.Nodes))
+	for _, node := range genogram.Nodes {
+		inTree[node.ID] = true
+	}
+	if !inTree[req.FromID] || !inTree[req.ToID] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "As pessoas vinculadas devem ser o paciente ou seus familiares"})
+		return
+	}
+
+	link := model.PatientFamilyLink{
+		ID:        uuid.New(),
+		PatientID: patientID,
+		FromID:    req.FromID,
+		ToID:      req.ToID,
+		Type:      req.Type,
+		Notes:     req.Notes,
+		CreatedAt: time.Now(),
+	}
+
+	// Child links are stored as parent links from the other end
+	if req.Type == "child" {
+		link.FromID, link.ToID = req.ToID, req.FromID
+		link.Type = model.FamilyLinkTypeParent
+	}
+
+	if genogram.Connected(link.FromID, link.ToID) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Essas pessoas já estão vinculadas"})
+		return
+	}
+	if link.Type == model.FamilyLinkTypeParent && genogram.IsAncestor(link.ToID, link.FromID) {
+		c.JSON(http.StatusConflict, gin.H{"error": "O vínculo criaria um ciclo de ascendência"})
+		return
+	}
+
+	linkRepo := repository.NewPatientFamilyLinkRepository(config.DB)
+	if err := linkRepo.Create(&link); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar vínculo familiar", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewPatientFamilyLinkResponse(link))
+}
+
+// GetPatientFamilyLinks gets all links of a patient's family tree
+func GetPatientFamilyLinks(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	// Verify that the patient belongs to the user
+	patientRepo := repository.NewPatientRepository(config.DB)
+	_, err = patientRepo.FindByID(patientID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return
+	}
+
+	linkRepo := repository.NewPatientFamilyLinkRepository(config.DB)
+	links, err := linkRepo.FindByPatient(patientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar vínculos familiares", "details": err.Error()})
+		return
+	}
+
+	responses := []dto.PatientFamilyLinkResponse{}
+	for _, link := range links {
+		responses = append(responses, dto.NewPatientFamilyLinkResponse(link))
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// DeletePatientFamilyLink deletes a link of a patient's family tree
+func DeletePatientFamilyLink(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	// Verify that the patient belongs to the user
+	patientRepo := repository.NewPatientRepository(config.DB)
+	_, err = patientRepo.FindByID(patientID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return
+	}
+
+	linkRepo := repository.NewPatientFamilyLinkRepository(config.DB)
+	if _, err := linkRepo.FindByID(id, patientID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vínculo familiar não encontrado"})
+		return
+	}
+
+	if err := linkRepo.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir vínculo familiar", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Vínculo familiar excluído com sucesso"})
+}
+
+// loadGenogram builds the family tree of a patient of the user, answering 404 when the patient is not found
+func loadGenogram(c *gin.Context, patientID uuid.UUID, userID uuid.UUID) (helper.Genogram, bool) {
+	patientRepo := repository.NewPatientRepository(config.DB)
+	patient, err := patientRepo.FindByID(patientID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return helper.Genogram{}, false
+	}
+
+	members, err := repository.NewPatientFamilyRepository(config.DB).FindByPatient(patientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar familiares do paciente", "details": err.Error()})
+		return helper.Genogram{}, false
+	}
+
+	links, err := repository.NewPatientFamilyLinkRepository(config.DB).FindByPatient(patientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar vínculos familiares", "details": err.Error()})
+		return helper.Genogram{}, false
+	}
+
+	return helper.BuildGenogram(*patient, members, links), true
+}
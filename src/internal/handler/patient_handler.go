@@ -38,6 +38,7 @@ func CreatePatient(c *gin.Context) {
 		Email:                 req.Email,
 		Gender:                req.Gender,
 		Address:               req.Address,
+		PostalAddress:         req.PostalAddress.ToModel(),
 		ResidesWith:           req.ResidesWith,
 		EmergencyContactName:  req.EmergencyContactName,
 		EmergencyContactPhone: req.EmergencyContactPhone,
@@ -49,12 +50,18 @@ func CreatePatient(c *gin.Context) {
 		UpdatedAt:             time.Now(),
 	}
 
-	patientRepo := repository.NewPatientRepository(config.DB)
-	if err := patientRepo.Create(&patient); err != nil {
+	if errs := patient.NormalizeContact(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return
+	}
+
+	// An active patient starts their first episode of care along with the patient
+	if err := repository.NewCareEpisodeRepository(config.DB).CreatePatient(&patient); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar paciente", "details": err.Error()})
 		return
 	}
 
+	patientRepo := repository.NewPatientRepository(config.DB)
 	// Fetch the patient with the cost center to get the cost center name
 	createdPatient, err := patientRepo.FindByID(patient.ID, userID)
 	if err != nil {
@@ -99,35 +106,19 @@ func GetPatients(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination parameters
-	limit, offset := getPaginationParams(c)
-
-	patientRepo := repository.NewPatientRepository(config.DB)
-	patients, err := patientRepo.FindAll(userID, limit, offset)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar pacientes", "details": err.Error()})
+	query, ok := parseListQuery(c)
+	if !ok {
 		return
 	}
 
-	// Convert patients to response DTOs
-	var responses []dto.PatientResponse
-	for _, patient := range patients {
-		responses = append(responses, dto.NewPatientResponse(patient))
-	}
-
-	// Get total count
-	count, err := patientRepo.Count(userID)
+	patientRepo := repository.NewPatientRepository(config.DB)
+	page, err := patientRepo.List(userID, query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao contar pacientes", "details": err.Error()})
+		respondListError(c, err, "Erro ao buscar pacientes")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   responses,
-		"total":  count,
-		"limit":  limit,
-		"offset": offset,
-	})
+	respondList(c, page, dto.NewPatientResponse)
 }
 
 // UpdatePatient updates a patient
@@ -158,6 +149,8 @@ func UpdatePatient(c *gin.Context) {
 		return
 	}
 
+	wasActive := patient.IsActive
+
 	// Update patient fields
 	patient.CostCenterID = req.CostCenterID
 	patient.FullName = req.FullName
@@ -168,6 +161,7 @@ func UpdatePatient(c *gin.Context) {
 	patient.Email = req.Email
 	patient.Gender = req.Gender
 	patient.Address = req.Address
+	patient.PostalAddress = req.PostalAddress.ToModel()
 	patient.ResidesWith = req.ResidesWith
 	patient.EmergencyContactName = req.EmergencyContactName
 	patient.EmergencyContactPhone = req.EmergencyContactPhone
@@ -177,7 +171,15 @@ func UpdatePatient(c *gin.Context) {
 	patient.IsActive = req.IsActive
 	patient.UpdatedAt = time.Now()
 
-	if err := patientRepo.Update(patient); err != nil {
+	if errs := patient.NormalizeContact(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return
+	}
+
+	// Deactivating a patient closes their episode of care and cancels their future appointments,
+	// while reactivating them starts a new episode
+	episodeRepo := repository.NewCareEpisodeRepository(config.DB)
+	if _, err := episodeRepo.UpdatePatient(patient, wasActive, model.CareEpisodeEndReasonOther); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar paciente", "details": err.Error()})
 		return
 	}
@@ -216,44 +218,6 @@ func DeletePatient(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Paciente excluído com sucesso"})
 }
 
-// SearchPatientsByName searches patients by name
-func SearchPatientsByName(c *gin.Context) {
-	name := c.Query("name")
-	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Nome não fornecido"})
-		return
-	}
-
-	// Get user ID from token
-	userID, err := getUserIDFromToken(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
-		return
-	}
-
-	// Parse pagination parameters
-	limit, offset := getPaginationParams(c)
-
-	patientRepo := repository.NewPatientRepository(config.DB)
-	patients, err := patientRepo.FindByName(userID, name, limit, offset)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar pacientes", "details": err.Error()})
-		return
-	}
-
-	// Convert patients to response DTOs
-	var responses []dto.PatientResponse
-	for _, patient := range patients {
-		responses = append(responses, dto.NewPatientResponse(patient))
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"data":   responses,
-		"limit":  limit,
-		"offset": offset,
-	})
-}
-
 // GetPatientsByCostCenter gets patients by cost center
 func GetPatientsByCostCenter(c *gin.Context) {
 	costCenterID, err := uuid.Parse(c.Param("cost_center_id"))
@@ -269,25 +233,18 @@ func GetPatientsByCostCenter(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination parameters
-	limit, offset := getPaginationParams(c)
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+	query.Filters["cost_center_id"] = costCenterID.String()
 
 	patientRepo := repository.NewPatientRepository(config.DB)
-	patients, err := patientRepo.FindByCostCenter(userID, costCenterID, limit, offset)
+	page, err := patientRepo.List(userID, query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar pacientes", "details": err.Error()})
+		respondListError(c, err, "Erro ao buscar pacientes")
 		return
 	}
 
-	// Convert patients to response DTOs
-	var responses []dto.PatientResponse
-	for _, patient := range patients {
-		responses = append(responses, dto.NewPatientResponse(patient))
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"data":   responses,
-		"limit":  limit,
-		"offset": offset,
-	})
+	respondList(c, page, dto.NewPatientResponse)
 }
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/patient"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+// GetPatientDuplicates lists the pairs of patients that probably are the same person
+func GetPatientDuplicates(c *gin.Context) {
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	patientRepo := repository.NewPatientRepository(config.DB)
+	patients, err := patientRepo.FindAllByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar pacientes", "details": err.Error()})
+		return
+	}
+
+	byID := make(map[uuid.UUID]model.Patient, len(patients))
+	for _, patient := range patients {
+		byID[patient.ID] = patient
+	}
+
+	responses := []dto.PatientDuplicateResponse{}
+	for _, duplicate := range helper.FindDuplicatePatients(patients) {
+		responses = append(responses, dto.PatientDuplicateResponse{
+			Patient:   dto.NewPatientResponse(byID[duplicate.PatientID]),
+			Duplicate: dto.NewPatientResponse(byID[duplicate.DuplicateID]),
+			Reasons:   duplicate.Reasons,
+			Score:     duplicate.Score,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"total": len(responses),
+	})
+}
+
+// MergePatients merges a duplicate patient into the patient of the URL, which survives
+func MergePatients(c *gin.Context) {
+	survivorID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var req dto.PatientMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if req.DuplicateID == survivorID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Um paciente não pode ser mesclado com ele mesmo"})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	mergeRepo := repository.NewPatientMergeRepository(config.DB)
+	merge, err := mergeRepo.Merge(userID, survivorID, req.DuplicateID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao mesclar pacientes", "details": err.Error()})
+		return
+	}
+
+	patientRepo := repository.NewPatientRepository(config.DB)
+	survivor, err := patientRepo.FindByID(survivorID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar paciente mesclado", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"patient": dto.NewPatientResponse(*survivor),
+		"merge":   dto.NewPatientMergeResponse(*merge),
+	})
+}
+
+// GetPatientMerges lists the audit of the patient merges made by the user
+func GetPatientMerges(c *gin.Context) {
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := repository.NewPatientMergeRepository(config.DB).List(userID, query)
+	if err != nil {
+		respondListError(c, err, "Erro ao buscar mesclagens")
+		return
+	}
+
+	respondList(c, page, dto.NewPatientMergeResponse)
+}
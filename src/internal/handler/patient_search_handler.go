@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/patient"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SearchPatients searches patients combining the filters given in the query string:
+// q (name or social name), document, phone, active, cost_center_id, min_age, max_age,
+// last_session_before, last_session_after (YYYY-MM-DD) and has_open_balance. The page is
+// read with the standard list parameters, sorting by name, birth_date, created_at or last_session.
+func SearchPatients(c *gin.Context) {
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+	filter, ok := parsePatientSearchFilter(c, userID)
+	if !ok {
+		return
+	}
+
+	patientRepo := repository.NewPatientRepository(config.DB)
+	page, err := patientRepo.Search(filter, query)
+	if err != nil {
+		respondListError(c, err, "Erro ao buscar pacientes")
+		return
+	}
+
+	respondList(c, page, dto.NewPatientResponse)
+}
+
+// parsePatientSearchFilter reads the search filters from the query string, answering 400 on invalid values
+func parsePatientSearchFilter(c *gin.Context, userID uuid.UUID) (port.PatientSearchFilter, bool) {
+	filter := port.PatientSearchFilter{UserID: userID}
+
+	// "name" is kept for clients of the former name-only search
+	filter.Query = strings.TrimSpace(c.DefaultQuery("q", c.Query("name")))
+
+	if document := c.Query("document"); document != "" {
+		filter.Document = helper.DocumentDigits(document)
+	}
+
+	if phone := c.Query("phone"); phone != "" {
+		if normalized, err := validation.NormalizePhone(phone); err == nil {
+			filter.Phone = normalized
+		} else {
+			filter.Phone = helper.PhoneDigits(phone)
+		}
+	}
+
+	invalid := func(param string) (port.PatientSearchFilter, bool) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Filtro inválido", "details": param})
+		return filter, false
+	}
+
+	parseBool := func(param string) (*bool, bool) {
+		raw := c.Query(param)
+		if raw == "" {
+			return nil, true
+		}
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, false
+		}
+		return &value, true
+	}
+
+	parseDate := func(param string) (*time.Time, bool) {
+		raw := c.Query(param)
+		if raw == "" {
+			return nil, true
+		}
+		value, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+		if err != nil {
+			return nil, false
+		}
+		return &value, true
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	parseAge := func(param string) (*int, bool) {
+		raw := c.Query(param)
+		if raw == "" {
+			return nil, true
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return nil, false
+		}
+		return &value, true
+	}
+
+	var ok bool
+	if filter.IsActive, ok = parseBool("active"); !ok {
+		return invalid("active")
+	}
+	if filter.HasOpenBalance, ok = parseBool("has_open_balance"); !ok {
+		return invalid("has_open_balance")
+	}
+	if filter.LastSessionBefore, ok = parseDate("last_session_before"); !ok {
+		return invalid("last_session_before")
+	}
+	if filter.LastSessionAfter, ok = parseDate("last_session_after"); !ok {
+		return invalid("last_session_after")
+	}
+
+	if raw := c.Query("cost_center_id"); raw != "" {
+		costCenterID, err := uuid.Parse(raw)
+		if err != nil {
+			return invalid("cost_center_id")
+		}
+		filter.CostCenterID = &costCenterID
+	}
+
+	minAge, ok := parseAge("min_age")
+	if !ok {
+		return invalid("min_age")
+	}
+	if minAge != nil {
+		bornBefore := today.AddDate(-*minAge, 0, 0)
+		filter.BornBefore = &bornBefore
+	}
+	maxAge, ok := parseAge("max_age")
+	if !ok {
+		return invalid("max_age")
+	}
+	if maxAge != nil {
+		bornAfter := today.AddDate(-(*maxAge + 1), 0, 0)
+		filter.BornAfter = &bornAfter
+	}
+
+	return filter, true
+}
@@ -69,6 +69,11 @@ func CreatePayment(c *gin.Context) {
 		payment.PatientID = &patientID
 	}
 
+	// Attribute the payment to the family member financially responsible for the patient
+	if !attributePaymentPayer(c, payment, req.PayerID) {
+		return
+	}
+
 	// Create repositories
 	paymentRepo := repository.NewPaymentRepository(config.DB)
 	paymentAppointmentRepo := repository.NewPaymentAppointmentRepository(config.DB)
@@ -118,6 +123,10 @@ func CreatePayment(c *gin.Context) {
 		response["patient_id"] = payment.PatientID.String()
 	}
 
+	if payment.PayerID != nil {
+		response["payer_id"] = payment.PayerID.String()
+	}
+
 	c.JSON(http.StatusCreated, response)
 }
 
@@ -130,19 +139,23 @@ func GetPayments(c *gin.Context) {
 		return
 	}
 
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
 	// Create repository
 	repo := repository.NewPaymentRepository(config.DB)
 
 	// Get payments
-	payments, err := repo.FindByUserID(userID.(string))
+	page, err := repo.List(userID.(string), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payments", "details": err.Error()})
+		respondListError(c, err, "Failed to fetch payments")
 		return
 	}
 
 	// Convert to response format
-	response := make([]gin.H, len(payments))
-	for i, payment := range payments {
+	respondList(c, page, func(payment model.Payment) gin.H {
 		paymentResponse := gin.H{
 			"id":             payment.ID.String(),
 			"user_id":        payment.UserID.String(),
@@ -158,10 +171,12 @@ func GetPayments(c *gin.Context) {
 			paymentResponse["patient_id"] = payment.PatientID.String()
 		}
 
-		response[i] = paymentResponse
-	}
+		if payment.PayerID != nil {
+			paymentResponse["payer_id"] = payment.PayerID.String()
+		}
 
-	c.JSON(http.StatusOK, response)
+		return paymentResponse
+	})
 }
 
 // GetPayment returns a specific payment
@@ -230,6 +245,10 @@ func GetPayment(c *gin.Context) {
 		response["patient_id"] = payment.PatientID.String()
 	}
 
+	if payment.PayerID != nil {
+		response["payer_id"] = payment.PayerID.String()
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -339,19 +358,23 @@ func GetRepasses(c *gin.Context) {
 		return
 	}
 
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
 	// Create repository
 	repo := repository.NewRepasseRepository(config.DB)
 
 	// Get repasses
-	repasses, err := repo.FindByUserID(userID.(string))
+	page, err := repo.List(userID.(string), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch repasses", "details": err.Error()})
+		respondListError(c, err, "Failed to fetch repasses")
 		return
 	}
 
 	// Convert to response format
-	response := make([]gin.H, len(repasses))
-	for i, repasse := range repasses {
+	respondList(c, page, func(repasse model.Repasse) gin.H {
 		repasseResponse := gin.H{
 			"id":              repasse.ID.String(),
 			"user_id":         repasse.UserID.String(),
@@ -369,10 +392,8 @@ func GetRepasses(c *gin.Context) {
 			repasseResponse["paid_at"] = repasse.PaidAt
 		}
 
-		response[i] = repasseResponse
-	}
-
-	c.JSON(http.StatusOK, response)
+		return repasseResponse
+	})
 }
 
 // UpdateRepasseStatus updates the status of a specific repasse
@@ -465,3 +486,50 @@ func UpdateRepasseStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// attributePaymentPayer sets the payer of a payment to the given family member, who must be
+// financially responsible for the payment's patient. Without one, the patient's only financial
+// responsible, if any, is used.
+func attributePaymentPayer(c *gin.Context, payment *model.Payment, payerID *string) bool {
+	familyRepo := repository.NewPatientFamilyRepository(config.DB)
+
+	if payerID != nil {
+		if payment.PatientID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A payer requires the patient of the payment"})
+			return false
+		}
+
+		parsedPayerID, err := uuid.Parse(*payerID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payer ID format"})
+			return false
+		}
+
+		payer, err := familyRepo.FindByID(parsedPayerID)
+		if err != nil || payer.PatientID != *payment.PatientID || payer.Patient.UserID != payment.UserID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Payer not found among the patient's family members"})
+			return false
+		}
+		if !payer.IsFinancialResponsible {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Payer is not financially responsible for the patient"})
+			return false
+		}
+
+		payment.PayerID = &payer.ID
+		return true
+	}
+
+	if payment.PatientID == nil {
+		return true
+	}
+
+	responsibles, err := familyRepo.FindFinancialResponsibles(*payment.PatientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch financial responsibles", "details": err.Error()})
+		return false
+	}
+	if len(responsibles) == 1 {
+		payment.PayerID = &responsibles[0].ID
+	}
+	return true
+}
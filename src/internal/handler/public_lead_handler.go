@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/lead"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// publicLeadMinFillTime is the minimum time a person takes to fill the contact form
+	publicLeadMinFillTime = time.Second * 3
+	// publicLeadMaxFormAge rejects forms rendered too long ago (replayed submissions)
+	publicLeadMaxFormAge = time.Hour * 24
+	// publicLeadDefaultOrigin is used when no UTM or source parameter is sent
+	publicLeadDefaultOrigin = "website"
+)
+
+// publicLeadAccepted is returned for every accepted submission, including the ones discarded as spam,
+// so bots cannot tell whether they were detected
+var publicLeadAccepted = gin.H{"message": "Contato recebido com sucesso"}
+
+// CreatePublicLead captures a lead from the website contact form of an organization
+func CreatePublicLead(c *gin.Context) {
+	var req dto.PublicLeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	slug := strings.ToLower(strings.TrimSpace(c.Param("org_slug")))
+	organization, err := repository.NewOrganizationRepository(config.DB).FindBySlug(slug)
+	if err != nil || organization.LeadOwnerID == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organização não encontrada"})
+		return
+	}
+
+	now := time.Now()
+	if isSpamLeadSubmission(req, now) {
+		log.Printf("Aviso: envio de lead descartado como spam (organização %s, IP %s)", organization.Slug, c.ClientIP())
+		c.JSON(http.StatusAccepted, publicLeadAccepted)
+		return
+	}
+
+	email := ""
+	if req.Email != nil {
+		email = helper.NormalizeEmail(*req.Email)
+	}
+	phone := ""
+	if req.Phone != nil {
+		phone = strings.TrimSpace(*req.Phone)
+	}
+	if email == "" && phone == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe um email ou telefone para contato"})
+		return
+	}
+
+	if !req.ConsentDataProcessing {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "É necessário consentir com o tratamento dos dados"})
+		return
+	}
+
+	owner, err := repository.NewUserRepository(config.DB).FindByID(*organization.LeadOwnerID)
+	if err != nil || !owner.IsActive {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organização não encontrada"})
+		return
+	}
+
+	origin := publicLeadOrigin(c, req)
+	lead := model.Lead{
+		ID:          uuid.New(),
+		UserID:      owner.ID,
+		FullName:    strings.TrimSpace(req.FullName),
+		ContactDate: now,
+		Status:      model.LeadStatusNew,
+		Notes:       publicLeadNotes(req),
+		Origin:      &origin,
+		// Without consent to be contacted the team must not reach out to the person
+		GdprBlockContact: !req.ConsentContact,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if email != "" {
+		lead.Email = &email
+	}
+	if phone != "" {
+		lead.Phone = &phone
+	}
+	if errs := lead.NormalizeContact(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return
+	}
+
+	// Flag submissions from people already known as leads or patients
+	phoneDigits := ""
+	if lead.Phone != nil {
+		phoneDigits = helper.PhoneDigits(*lead.Phone)
+	}
+	existingLead, err := repository.NewLeadRepository(config.DB).FindByContact(owner.ID, email, phoneDigits)
+	if err == nil {
+		lead.DuplicateOfLeadID = &existingLead.ID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar contato"})
+		return
+	}
+	existingPatient, err := repository.NewPatientRepository(config.DB).FindByContact(owner.ID, email, phoneDigits)
+	if err == nil {
+		lead.DuplicateOfPatientID = &existingPatient.ID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar contato"})
+		return
+	}
+
+	if err := lead.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	consent := model.LeadConsent{
+		ID:             uuid.New(),
+		DataProcessing: req.ConsentDataProcessing,
+		Contact:        req.ConsentContact,
+		PolicyVersion:  req.PolicyVersion,
+		IPAddress:      c.ClientIP(),
+		CreatedAt:      now,
+	}
+	if userAgent := c.Request.UserAgent(); userAgent != "" {
+		if len(userAgent) > 255 {
+			userAgent = userAgent[:255]
+		}
+		consent.UserAgent = &userAgent
+	}
+
+	if err := repository.NewLeadRepository(config.DB).CreateWithConsent(&lead, &consent); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar contato"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, publicLeadAccepted)
+}
+
+// isSpamLeadSubmission detects bots by the filled honeypot field or an implausible fill time
+func isSpamLeadSubmission(req dto.PublicLeadRequest, now time.Time) bool {
+	if strings.TrimSpace(req.Website) != "" {
+		return true
+	}
+	elapsed := now.Sub(req.FormRenderedAt)
+	return elapsed < publicLeadMinFillTime || elapsed > publicLeadMaxFormAge
+}
+
+// publicLeadOrigin takes the origin from utm_source, then source (body or query string), defaulting to website
+func publicLeadOrigin(c *gin.Context, req dto.PublicLeadRequest) string {
+	candidates := []string{c.Query("utm_source"), c.Query("source")}
+	if req.UtmSource != nil {
+		candidates = append([]string{*req.UtmSource}, candidates...)
+	}
+	if req.Source != nil {
+		candidates = append(candidates, *req.Source)
+	}
+
+	for _, candidate := range candidates {
+		origin := strings.ToLower(strings.TrimSpace(candidate))
+		if origin == "" {
+			continue
+		}
+		if len(origin) > 50 {
+			origin = origin[:50]
+		}
+		return origin
+	}
+	return publicLeadDefaultOrigin
+}
+
+// publicLeadNotes keeps the message and the campaign details of the submission
+func publicLeadNotes(req dto.PublicLeadRequest) *string {
+	var lines []string
+	if req.Message != nil && strings.TrimSpace(*req.Message) != "" {
+		lines = append(lines, strings.TrimSpace(*req.Message))
+	}
+	if req.UtmMedium != nil && *req.UtmMedium != "" {
+		lines = append(lines, "utm_medium: "+*req.UtmMedium)
+	}
+	if req.UtmCampaign != nil && *req.UtmCampaign != "" {
+		lines = append(lines, "utm_campaign: "+*req.UtmCampaign)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	notes := strings.Join(lines, "\n")
+	return &notes
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/report"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GetLeadFunnelReport reports conversions by origin, time to conversion, stage drop-off, monthly cohorts
+// and the revenue of converted patients. Filters: start_date and end_date (YYYY-MM-DD, inclusive) on the
+// lead contact date, and origin (repeatable or comma-separated).
+func GetLeadFunnelReport(c *gin.Context) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	filter := port.LeadFunnelFilter{UserID: userID}
+
+	startStr, endStr := c.Query("start_date"), c.Query("end_date")
+	if startStr != "" {
+		start, err := time.ParseInLocation("2006-01-02", startStr, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Formato de data inicial inválido, use AAAA-MM-DD"})
+			return
+		}
+		filter.StartDate = &start
+	}
+	if endStr != "" {
+		end, err := time.ParseInLocation("2006-01-02", endStr, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Formato de data final inválido, use AAAA-MM-DD"})
+			return
+		}
+		// The end date is inclusive
+		end = end.AddDate(0, 0, 1)
+		filter.EndDate = &end
+	}
+	if filter.StartDate != nil && filter.EndDate != nil && !filter.StartDate.Before(*filter.EndDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A data inicial deve ser anterior ou igual à data final"})
+		return
+	}
+
+	for _, value := range c.QueryArray("origin") {
+		for _, origin := range strings.Split(value, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				filter.Origins = append(filter.Origins, origin)
+			}
+		}
+	}
+
+	reportRepo := repository.NewLeadReportRepository(config.DB)
+
+	origins, err := reportRepo.FunnelByOrigin(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular conversões por origem", "details": err.Error()})
+		return
+	}
+
+	conversion, err := reportRepo.ConversionTime(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular tempo de conversão", "details": err.Error()})
+		return
+	}
+
+	stages, err := reportRepo.StageFunnel(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular etapas do funil", "details": err.Error()})
+		return
+	}
+
+	cohorts, err := reportRepo.MonthlyCohorts(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular coortes mensais", "details": err.Error()})
+		return
+	}
+
+	response := dto.NewLeadFunnelResponse(origins, *conversion, stages, cohorts)
+	if startStr != "" {
+		response.StartDate = &startStr
+	}
+	if endStr != "" {
+		response.EndDate = &endStr
+	}
+	response.Origins = filter.Origins
+
+	c.JSON(http.StatusOK, response)
+}
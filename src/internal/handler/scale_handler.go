@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/scale"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// GetScales lists the psychometric scales available for application
+func GetScales(c *gin.Context) {
+	scales, err := repository.NewScaleRepository(config.DB).FindAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar escalas", "details": err.Error()})
+		return
+	}
+
+	responses := []dto.ScaleResponse{}
+	for _, scale := range scales {
+		responses = append(responses, dto.NewScaleResponse(scale, nil, nil))
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetScale gets a psychometric scale with its items, response options and severity bands
+func GetScale(c *gin.Context) {
+	scaleID, err := uuid.Parse(c.Param("scale_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID da escala inválido"})
+		return
+	}
+
+	scale, err := repository.NewScaleRepository(config.DB).FindByID(scaleID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Escala não encontrada"})
+		return
+	}
+
+	minScore, maxScore := helper.ScaleScoreRange(scale)
+	c.JSON(http.StatusOK, dto.NewScaleResponse(*scale, &minScore, &maxScore))
+}
+
+// CreateScaleApplication records a scale answered by a patient, optionally during one of their
+// sessions. The score and severity are computed from the answers.
+func CreateScaleApplication(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	var req dto.ScaleApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	patient, err := repository.NewPatientRepository(config.DB).FindByID(patientID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return
+	}
+
+	scale, err := repository.NewScaleRepository(config.DB).FindByID(req.ScaleID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Escala não encontrada"})
+		return
+	}
+
+	appliedAt := time.Now()
+	if req.SessionID != nil {
+		session, err := repository.NewSessionRepository(config.DB).FindByID(req.SessionID.String())
+		if err != nil || session.UserID != userID || session.PatientID != patient.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Sessão não encontrada para o paciente"})
+			return
+		}
+		appliedAt = session.StartTime
+	}
+	if req.AppliedAt != nil {
+		if req.AppliedAt.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A data de aplicação não pode ser futura"})
+			return
+		}
+		appliedAt = *req.AppliedAt
+	}
+
+	answers := make([]helper.ScaleItemAnswer, len(req.Answers))
+	for i, answer := range req.Answers {
+		answers[i] = helper.ScaleItemAnswer{ItemID: answer.ItemID, Value: *answer.Value}
+	}
+	result, errs := helper.ScoreScale(scale, answers)
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Respostas inválidas", "details": errs})
+		return
+	}
+
+	application := model.ScaleApplication{
+		ID:        uuid.New(),
+		UserID:    userID,
+		PatientID: patient.ID,
+		SessionID: req.SessionID,
+		ScaleID:   scale.ID,
+		Scale:     *scale,
+		Score:     result.Score,
+		Severity:  result.Severity,
+		Flagged:   result.Flagged,
+		AppliedAt: appliedAt,
+		Notes:     req.Notes,
+		Answers:   result.Answers,
+		CreatedAt: time.Now(),
+	}
+	for i := range application.Answers {
+		application.Answers[i].ID = uuid.New()
+		application.Answers[i].ApplicationID = application.ID
+	}
+
+	if err := repository.NewScaleApplicationRepository(config.DB).Create(&application); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar aplicação da escala", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewScaleApplicationResponse(application))
+}
+
+// GetScaleApplications lists the scale applications of a patient, most recent first, optionally
+// of a single scale
+func GetScaleApplications(c *gin.Context) {
+	patientID, scaleID, ok := scaleApplicationFilter(c)
+	if !ok {
+		return
+	}
+
+	applications, err := repository.NewScaleApplicationRepository(config.DB).FindByPatient(patientID, scaleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar aplicações de escalas", "details": err.Error()})
+		return
+	}
+
+	responses := []dto.ScaleApplicationResponse{}
+	for _, application := range applications {
+		responses = append(responses, dto.NewScaleApplicationResponse(application))
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetScaleTimeline returns the scores of a patient over time, one timeline per scale, to track
+// the outcome of the treatment
+func GetScaleTimeline(c *gin.Context) {
+	patientID, scaleID, ok := scaleApplicationFilter(c)
+	if !ok {
+		return
+	}
+
+	applications, err := repository.NewScaleApplicationRepository(config.DB).FindByPatient(patientID, scaleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar aplicações de escalas", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewScaleTimelineResponses(applications))
+}
+
+// GetScaleApplication gets a scale application of a patient with its answers
+func GetScaleApplication(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	// Verify that the patient belongs to the user
+	if _, err := repository.NewPatientRepository(config.DB).FindByID(patientID, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return
+	}
+
+	application, err := repository.NewScaleApplicationRepository(config.DB).FindByID(id, patientID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Aplicação de escala não encontrada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewScaleApplicationResponse(*application))
+}
+
+// scaleApplicationFilter reads the patient of the user and the optional scale_id query
+// parameter, answering the error otherwise
+func scaleApplicationFilter(c *gin.Context) (uuid.UUID, *uuid.UUID, bool) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return uuid.Nil, nil, false
+	}
+
+	var scaleID *uuid.UUID
+	if value := c.Query("scale_id"); value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da escala inválido"})
+			return uuid.Nil, nil, false
+		}
+		scaleID = &parsed
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return uuid.Nil, nil, false
+	}
+
+	// Verify that the patient belongs to the user
+	if _, err := repository.NewPatientRepository(config.DB).FindByID(patientID, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return uuid.Nil, nil, false
+	}
+
+	return patientID, scaleID, true
+}
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"github.com/LacirJR/psygrow-api/src/internal/config"
 	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/appointment"
 	"github.com/LacirJR/psygrow-api/src/internal/core/model"
 	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"net/http"
 	"time"
 )
@@ -27,33 +30,36 @@ func GetSessions(c *gin.Context) {
 		return
 	}
 
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
 	// Create repository
 	repo := repository.NewSessionRepository(config.DB)
 
 	// Get sessions
-	sessions, err := repo.FindByUserID(clientID.String())
+	page, err := repo.List(clientID.String(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions", "details": err.Error()})
+		respondListError(c, err, "Failed to fetch sessions")
 		return
 	}
 
 	// Convert to response format
-	response := make([]gin.H, len(sessions))
-	for i, session := range sessions {
-		response[i] = gin.H{
+	respondList(c, page, func(session model.Session) gin.H {
+		return gin.H{
 			"id":              session.ID.String(),
 			"appointment_id":  session.AppointmentID.String(),
 			"client_id":       session.UserID.String(),
 			"patient_id":      session.PatientID.String(),
+			"episode_id":      session.EpisodeID,
 			"professional_id": session.ProfessionalID.String(),
 			"start_time":      session.StartTime,
 			"end_time":        session.EndTime,
 			"was_attended":    session.WasAttended,
 			"created_at":      session.CreatedAt,
 		}
-	}
-
-	c.JSON(http.StatusOK, response)
+	})
 }
 
 // GetSession returns a specific session
@@ -96,6 +102,7 @@ func GetSession(c *gin.Context) {
 		"appointment_id":  session.AppointmentID.String(),
 		"client_id":       session.UserID.String(),
 		"patient_id":      session.PatientID.String(),
+		"episode_id":      session.EpisodeID,
 		"professional_id": session.ProfessionalID.String(),
 		"start_time":      session.StartTime,
 		"end_time":        session.EndTime,
@@ -165,8 +172,19 @@ func CreateEvolution(c *gin.Context) {
 		CreatedAt:      time.Now(),
 	}
 
+	// Rate the progress on the goals of the active treatment plan, as of the session
+	progress, message, err := evolutionGoalProgress(evolution, session.StartTime, req.GoalProgress)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find treatment plan", "details": err.Error()})
+		return
+	}
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
 	// Save evolution
-	if err := evolutionRepo.Save(evolution); err != nil {
+	if err := evolutionRepo.SaveWithGoalProgress(evolution, progress); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create evolution", "details": err.Error()})
 		return
 	}
@@ -178,6 +196,7 @@ func CreateEvolution(c *gin.Context) {
 		"patient_id":      evolution.PatientID.String(),
 		"professional_id": evolution.ProfessionalID.String(),
 		"content":         evolution.Content,
+		"goal_progress":   dto.NewEvolutionGoalProgressResponses(progress),
 		"created_at":      evolution.CreatedAt,
 	})
 }
@@ -217,6 +236,12 @@ func GetEvolution(c *gin.Context) {
 		return
 	}
 
+	progress, err := repository.NewTreatmentPlanRepository(config.DB).FindProgressByEvolution(evolution.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get goal progress", "details": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"id":              evolution.ID.String(),
 		"session_id":      evolution.SessionID.String(),
@@ -224,6 +249,7 @@ func GetEvolution(c *gin.Context) {
 		"patient_id":      evolution.PatientID.String(),
 		"professional_id": evolution.ProfessionalID.String(),
 		"content":         evolution.Content,
+		"goal_progress":   dto.NewEvolutionGoalProgressResponses(progress),
 		"created_at":      evolution.CreatedAt,
 	})
 }
@@ -281,3 +307,55 @@ func GetEvolutionsByPatient(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// evolutionGoalProgress creates the progress ratings of an evolution on the goals of the current
+// version of the active treatment plan of its patient. The message describes why the ratings
+// were refused, when they were.
+func evolutionGoalProgress(evolution *model.Evolution, ratedAt time.Time, requests []dto.EvolutionGoalProgressRequest) ([]model.TreatmentGoalProgress, string, error) {
+	if len(requests) == 0 {
+		return nil, "", nil
+	}
+
+	planRepo := repository.NewTreatmentPlanRepository(config.DB)
+	plan, err := planRepo.FindActiveByPatient(evolution.PatientID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "Patient has no active treatment plan", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	version, err := planRepo.FindVersion(plan.ID, plan.CurrentVersion)
+	if err != nil {
+		return nil, "", err
+	}
+
+	goals := make(map[uuid.UUID]model.TreatmentGoal, len(version.Goals))
+	for _, goal := range version.Goals {
+		goals[goal.ID] = goal
+	}
+
+	progress := make([]model.TreatmentGoalProgress, 0, len(requests))
+	rated := make(map[uuid.UUID]bool)
+	for _, req := range requests {
+		goal, ok := goals[req.GoalID]
+		if !ok {
+			return nil, fmt.Sprintf("Goal %s is not in the current version of the treatment plan", req.GoalID), nil
+		}
+		if rated[goal.ID] {
+			return nil, fmt.Sprintf("Goal %s was rated more than once", req.GoalID), nil
+		}
+		rated[goal.ID] = true
+
+		progress = append(progress, model.TreatmentGoalProgress{
+			ID:          uuid.New(),
+			EvolutionID: evolution.ID,
+			PlanID:      plan.ID,
+			GoalID:      goal.ID,
+			GoalKey:     goal.GoalKey,
+			Rating:      *req.Rating,
+			Notes:       req.Notes,
+			RatedAt:     ratedAt,
+		})
+	}
+	return progress, "", nil
+}
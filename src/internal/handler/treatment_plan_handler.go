@@ -0,0 +1,361 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/treatment"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CreateTreatmentPlan creates the treatment plan of a patient with its first version. A patient
+// has at most one active plan.
+func CreateTreatmentPlan(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	var req dto.TreatmentPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	// Verify that the patient belongs to the user
+	if _, err := repository.NewPatientRepository(config.DB).FindByID(patientID, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return
+	}
+
+	startDate := time.Now()
+	if req.StartDate != nil {
+		startDate = *req.StartDate
+	}
+
+	plan := model.TreatmentPlan{
+		ID:             uuid.New(),
+		UserID:         userID,
+		PatientID:      patientID,
+		Title:          req.Title,
+		Status:         model.TreatmentPlanStatusActive,
+		StartDate:      startDate,
+		CurrentVersion: 1,
+	}
+	version := model.TreatmentPlanVersion{
+		ID:        uuid.New(),
+		PlanID:    plan.ID,
+		Version:   1,
+		Summary:   req.Summary,
+		CreatedBy: userID,
+		Goals:     treatmentGoals(req.Goals, nil),
+	}
+
+	planRepo := repository.NewTreatmentPlanRepository(config.DB)
+	if err := planRepo.Create(&plan, &version); err != nil {
+		if errors.Is(err, port.ErrTreatmentPlanActive) {
+			c.JSON(http.StatusConflict, gin.H{"error": "O paciente já possui um plano de tratamento ativo"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar plano de tratamento", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewTreatmentPlanResponse(plan, &version))
+}
+
+// GetTreatmentPlans lists the treatment plans of a patient, most recent first
+func GetTreatmentPlans(c *gin.Context) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	// Verify that the patient belongs to the user
+	if _, err := repository.NewPatientRepository(config.DB).FindByID(patientID, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return
+	}
+
+	plans, err := repository.NewTreatmentPlanRepository(config.DB).FindByPatient(patientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar planos de tratamento", "details": err.Error()})
+		return
+	}
+
+	responses := []dto.TreatmentPlanResponse{}
+	for _, plan := range plans {
+		responses = append(responses, dto.NewTreatmentPlanResponse(plan, nil))
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetTreatmentPlan gets a treatment plan with its current version, or the version given in the
+// version query parameter
+func GetTreatmentPlan(c *gin.Context) {
+	plan, ok := treatmentPlanFromPath(c)
+	if !ok {
+		return
+	}
+
+	number := plan.CurrentVersion
+	if value := c.Query("version"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Versão inválida"})
+			return
+		}
+		number = parsed
+	}
+
+	version, err := repository.NewTreatmentPlanRepository(config.DB).FindVersion(plan.ID, number)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Versão do plano de tratamento não encontrada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewTreatmentPlanResponse(*plan, version))
+}
+
+// GetTreatmentPlanVersions lists the versions of a treatment plan, most recent first
+func GetTreatmentPlanVersions(c *gin.Context) {
+	plan, ok := treatmentPlanFromPath(c)
+	if !ok {
+		return
+	}
+
+	versions, err := repository.NewTreatmentPlanRepository(config.DB).FindVersions(plan.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar versões do plano de tratamento", "details": err.Error()})
+		return
+	}
+
+	responses := []dto.TreatmentPlanVersionResponse{}
+	for _, version := range versions {
+		responses = append(responses, dto.NewTreatmentPlanVersionResponse(version))
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// ReviseTreatmentPlan creates a new version of an active treatment plan. Earlier versions, and
+// the progress rated on them, are kept unchanged.
+func ReviseTreatmentPlan(c *gin.Context) {
+	plan, ok := treatmentPlanFromPath(c)
+	if !ok {
+		return
+	}
+
+	var req dto.TreatmentPlanRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if !plan.IsActive() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Apenas planos de tratamento ativos podem ser revisados"})
+		return
+	}
+	if req.Version != plan.CurrentVersion {
+		c.JSON(http.StatusConflict, gin.H{"error": "O plano de tratamento foi revisado por outra pessoa, recarregue a versão atual"})
+		return
+	}
+
+	planRepo := repository.NewTreatmentPlanRepository(config.DB)
+	current, err := planRepo.FindVersion(plan.ID, plan.CurrentVersion)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar versão atual do plano de tratamento", "details": err.Error()})
+		return
+	}
+
+	// Goals kept from the current version keep their key, so their progress history follows them
+	keys := make(map[uuid.UUID]uuid.UUID, len(current.Goals))
+	for _, goal := range current.Goals {
+		keys[goal.ID] = goal.GoalKey
+	}
+	kept := make(map[uuid.UUID]bool)
+	for i, goal := range req.Goals {
+		if goal.ID == nil {
+			continue
+		}
+		if _, ok := keys[*goal.ID]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A meta %d não pertence à versão atual do plano", i+1)})
+			return
+		}
+		if kept[*goal.ID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A meta %d foi informada mais de uma vez", i+1)})
+			return
+		}
+		kept[*goal.ID] = true
+	}
+
+	userID, _ := getUserIDFromToken(c)
+	reason := req.Reason
+	version := model.TreatmentPlanVersion{
+		ID:             uuid.New(),
+		Summary:        req.Summary,
+		RevisionReason: &reason,
+		CreatedBy:      userID,
+		Goals:          treatmentGoals(req.Goals, keys),
+	}
+
+	if err := planRepo.Revise(plan, &version); err != nil {
+		if errors.Is(err, port.ErrTreatmentPlanOutdated) {
+			c.JSON(http.StatusConflict, gin.H{"error": "O plano de tratamento foi revisado por outra pessoa, recarregue a versão atual"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao revisar plano de tratamento", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewTreatmentPlanResponse(*plan, &version))
+}
+
+// UpdateTreatmentPlanStatus completes, discontinues or resumes a treatment plan
+func UpdateTreatmentPlanStatus(c *gin.Context) {
+	plan, ok := treatmentPlanFromPath(c)
+	if !ok {
+		return
+	}
+
+	var req dto.TreatmentPlanStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	planRepo := repository.NewTreatmentPlanRepository(config.DB)
+	if req.Status != plan.Status {
+		if req.Status == model.TreatmentPlanStatusActive {
+			plan.EndedAt = nil
+		} else {
+			now := time.Now()
+			plan.EndedAt = &now
+		}
+		plan.Status = req.Status
+
+		// Resuming a plan must not leave the patient with two active plans
+		if err := planRepo.UpdateStatus(plan); err != nil {
+			if errors.Is(err, port.ErrTreatmentPlanActive) {
+				c.JSON(http.StatusConflict, gin.H{"error": "O paciente já possui um plano de tratamento ativo"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar status do plano de tratamento", "details": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.NewTreatmentPlanResponse(*plan, nil))
+}
+
+// GetTreatmentPlanSummary aggregates the progress rated in evolutions on the goals of the
+// current version of a treatment plan, over time
+func GetTreatmentPlanSummary(c *gin.Context) {
+	plan, ok := treatmentPlanFromPath(c)
+	if !ok {
+		return
+	}
+
+	planRepo := repository.NewTreatmentPlanRepository(config.DB)
+	version, err := planRepo.FindVersion(plan.ID, plan.CurrentVersion)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar versão atual do plano de tratamento", "details": err.Error()})
+		return
+	}
+
+	progress, err := planRepo.FindProgressByPlan(plan.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar progresso do plano de tratamento", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewTreatmentPlanSummaryResponse(*plan, *version, progress, time.Now()))
+}
+
+// treatmentPlanFromPath finds the treatment plan in the path, of a patient of the user,
+// answering the error otherwise
+func treatmentPlanFromPath(c *gin.Context) (*model.TreatmentPlan, bool) {
+	patientID, err := uuid.Parse(c.Param("patient_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do paciente inválido"})
+		return nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return nil, false
+	}
+
+	// Get user ID from token
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return nil, false
+	}
+
+	// Verify that the patient belongs to the user
+	if _, err := repository.NewPatientRepository(config.DB).FindByID(patientID, userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Paciente não encontrado"})
+		return nil, false
+	}
+
+	plan, err := repository.NewTreatmentPlanRepository(config.DB).FindByID(id, patientID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plano de tratamento não encontrado"})
+		return nil, false
+	}
+	return plan, true
+}
+
+// treatmentGoals creates the goals of a new version, in the order given. Goals referring to a
+// goal of the current version keep its key.
+func treatmentGoals(requests []dto.TreatmentGoalRequest, keys map[uuid.UUID]uuid.UUID) []model.TreatmentGoal {
+	goals := make([]model.TreatmentGoal, len(requests))
+	for i, req := range requests {
+		// Goals of a new plan, or unknown to the current version, start a new history
+		key := uuid.New()
+		if req.ID != nil {
+			if existing, ok := keys[*req.ID]; ok {
+				key = existing
+			}
+		}
+		status := req.Status
+		if status == "" {
+			status = model.TreatmentGoalStatusPending
+		}
+		goals[i] = model.TreatmentGoal{
+			ID:          uuid.New(),
+			GoalKey:     key,
+			Position:    i + 1,
+			Description: req.Description,
+			TargetDate:  req.TargetDate,
+			Status:      status,
+		}
+	}
+	return goals
+}
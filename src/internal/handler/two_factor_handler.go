@@ -0,0 +1,386 @@
+package handler
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/user"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/security"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// LoginTwoFactor completes the login of a user with 2FA enabled, exchanging the challenge token
+// and a TOTP (or recovery) code for the access token
+func LoginTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	userID, err := parseScopedJWT(req.ChallengeToken, security.TokenTypeTwoFactorChallenge)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Desafio inválido ou expirado"})
+		return
+	}
+
+	userRepo := repository.NewUserRepository(config.DB)
+	user, err := userRepo.FindByID(userID)
+	if err != nil || !user.IsActive || !user.TwoFactorEnabled {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Desafio inválido ou expirado"})
+		return
+	}
+
+	if !checkLoginThrottle(c, user.Email, &user.ID) {
+		return
+	}
+
+	if req.Code != "" {
+		valid, err := verifyTOTP(user, req.Code)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar código de verificação"})
+			return
+		}
+		if !valid {
+			registerLoginFailure(c, user.Email, &user.ID, model.LoginAttemptReasonInvalidTwoFactor)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Código de verificação inválido"})
+			return
+		}
+	} else {
+		recoveryRepo := repository.NewRecoveryCodeRepository(config.DB)
+		consumed, err := recoveryRepo.Consume(user.ID, security.HashRecoveryCode(req.RecoveryCode))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar código de recuperação"})
+			return
+		}
+		if !consumed {
+			registerLoginFailure(c, user.Email, &user.ID, model.LoginAttemptReasonInvalidTwoFactor)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Código de recuperação inválido"})
+			return
+		}
+	}
+
+	completeLogin(c, user)
+}
+
+// GetTwoFactorStatus returns the 2FA state of the authenticated user
+func GetTwoFactorStatus(c *gin.Context) {
+	user, ok := getAuthenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	required, err := repository.NewTwoFactorPolicyRepository(config.DB).IsRequiredFor(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar política de 2FA"})
+		return
+	}
+
+	remaining, err := repository.NewRecoveryCodeRepository(config.DB).CountUnused(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao contar códigos de recuperação"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TwoFactorStatusResponse{
+		Enabled:                user.TwoFactorEnabled,
+		Required:               required,
+		RemainingRecoveryCodes: remaining,
+	})
+}
+
+// SetupTwoFactor generates a pending TOTP secret for the authenticated user.
+// The secret only takes effect after being confirmed with a valid code.
+func SetupTwoFactor(c *gin.Context) {
+	user, ok := getAuthenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Autenticação em dois fatores já está ativa"})
+		return
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar segredo"})
+		return
+	}
+
+	stored, err := security.EncryptSecret(secret, totpSecretKey())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao proteger segredo"})
+		return
+	}
+
+	user.TwoFactorSecret = &stored
+	user.UpdatedAt = time.Now()
+	if err := repository.NewUserRepository(config.DB).Update(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar usuário"})
+		return
+	}
+
+	issuer := config.GetEnvironmentWithDefault(config.TotpIssuer, "PsyGrow")
+	c.JSON(http.StatusOK, dto.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: security.TOTPProvisioningURI(secret, issuer, user.Email),
+	})
+}
+
+// ConfirmTwoFactor enables 2FA once the user proves the authenticator is configured,
+// returning the recovery codes (shown only once). When called with an enrollment token
+// it also returns the access token, completing the login.
+func ConfirmTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, ok := getAuthenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Autenticação em dois fatores já está ativa"})
+		return
+	}
+
+	if user.TwoFactorSecret == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Configuração de 2FA não iniciada"})
+		return
+	}
+
+	valid, err := verifyTOTP(user, req.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar código de verificação"})
+		return
+	}
+	if !valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Código de verificação inválido"})
+		return
+	}
+
+	codes, err := replaceRecoveryCodes(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar códigos de recuperação"})
+		return
+	}
+
+	now := time.Now()
+	user.TwoFactorEnabled = true
+	user.TwoFactorConfirmedAt = &now
+	user.UpdatedAt = now
+	if err := repository.NewUserRepository(config.DB).Update(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar usuário"})
+		return
+	}
+
+	response := gin.H{
+		"message":        "Autenticação em dois fatores ativada com sucesso",
+		"recovery_codes": codes,
+	}
+
+	if tokenType, _ := c.Get("token_type"); tokenType == security.TokenTypeTwoFactorEnrollment {
+		token, err := generateJWT(*user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar token"})
+			return
+		}
+		user.LastLoginAt = &now
+		if err := repository.NewUserRepository(config.DB).Update(user); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar último login"})
+			return
+		}
+		response["token"] = token
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DisableTwoFactor turns 2FA off, unless a policy makes it mandatory for the user
+func DisableTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, ok := getAuthenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	if !user.TwoFactorEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Autenticação em dois fatores não está ativa"})
+		return
+	}
+
+	required, err := repository.NewTwoFactorPolicyRepository(config.DB).IsRequiredFor(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar política de 2FA"})
+		return
+	}
+	if required {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Autenticação em dois fatores é obrigatória para sua conta"})
+		return
+	}
+
+	if !security.CheckPasswordHash(req.Password, user.PasswordHash) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Senha ou código de verificação inválidos"})
+		return
+	}
+	valid, err := verifyTOTP(user, req.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar código de verificação"})
+		return
+	}
+	if !valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Senha ou código de verificação inválidos"})
+		return
+	}
+
+	user.TwoFactorEnabled = false
+	user.TwoFactorSecret = nil
+	user.TwoFactorConfirmedAt = nil
+	user.TwoFactorLastStep = 0
+	user.UpdatedAt = time.Now()
+	if err := repository.NewUserRepository(config.DB).Update(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar usuário"})
+		return
+	}
+
+	if err := repository.NewRecoveryCodeRepository(config.DB).DeleteForUser(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover códigos de recuperação"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Autenticação em dois fatores desativada"})
+}
+
+// RegenerateRecoveryCodes invalidates the current recovery codes and issues a new set
+func RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, ok := getAuthenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	if !user.TwoFactorEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Autenticação em dois fatores não está ativa"})
+		return
+	}
+
+	valid, err := verifyTOTP(user, req.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar código de verificação"})
+		return
+	}
+	if !valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Código de verificação inválido"})
+		return
+	}
+
+	if err := repository.NewUserRepository(config.DB).Update(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar usuário"})
+		return
+	}
+
+	codes, err := replaceRecoveryCodes(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar códigos de recuperação"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// verifyTOTP validates the code against the user's secret and consumes its time step, so the
+// same code cannot be accepted twice. A secret stored before encryption at rest is encrypted in
+// the user, to be persisted by the caller.
+func verifyTOTP(user *model.User, code string) (bool, error) {
+	if user.TwoFactorSecret == nil {
+		return false, nil
+	}
+
+	key := totpSecretKey()
+	secret, err := security.DecryptSecret(*user.TwoFactorSecret, key)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := security.ValidateTOTPCode(secret, code, time.Now())
+	if !ok || step <= user.TwoFactorLastStep {
+		return false, nil
+	}
+
+	consumed, err := repository.NewUserRepository(config.DB).ConsumeTwoFactorStep(user.ID, step)
+	if err != nil || !consumed {
+		return false, err
+	}
+	user.TwoFactorLastStep = step
+
+	if !security.IsEncryptedSecret(*user.TwoFactorSecret) {
+		stored, err := security.EncryptSecret(secret, key)
+		if err != nil {
+			return false, err
+		}
+		user.TwoFactorSecret = &stored
+	}
+	return true, nil
+}
+
+// totpSecretKey derives the key that encrypts the TOTP secrets at rest
+func totpSecretKey() []byte {
+	return security.SecretKey(config.GetEnvironmentWithDefault(config.TotpSecretKey, ""))
+}
+
+// replaceRecoveryCodes generates new recovery codes, stores their hashes and returns the plain codes
+func replaceRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	codes, err := security.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*model.UserRecoveryCode, len(codes))
+	for i, code := range codes {
+		records[i] = &model.UserRecoveryCode{
+			ID:        uuid.New(),
+			UserID:    userID,
+			CodeHash:  security.HashRecoveryCode(code),
+			CreatedAt: time.Now(),
+		}
+	}
+
+	if err := repository.NewRecoveryCodeRepository(config.DB).ReplaceForUser(userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// getAuthenticatedUser loads the user referenced by the token, writing the error response when it fails
+func getAuthenticatedUser(c *gin.Context) (*model.User, bool) {
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return nil, false
+	}
+
+	user, err := repository.NewUserRepository(config.DB).FindByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return nil, false
+	}
+
+	return user, true
+}
@@ -4,70 +4,400 @@ import (
 	"github.com/LacirJR/psygrow-api/src/internal/config"
 	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/user"
 	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
 	"github.com/LacirJR/psygrow-api/src/internal/core/security"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"net/http"
+	"strconv"
 	"time"
 )
 
-// jwtSecretKey will be initialized when needed
-var jwtSecretKey []byte
+// RegisterUser creates a user with a password defined by the admin (admin only)
+func RegisterUser(c *gin.Context) {
+
+	var req dto.UserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, ok := buildNewUser(c, req.Name, req.Email, req.Role, req.OrganizationID, req.Phone, req.Address.ToModel())
+	if !ok {
+		return
+	}
+
+	hashedPassword, err := security.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar hash da senha"})
+		return
+	}
+
+	user.PasswordHash = hashedPassword
+	user.IsActive = true
 
-// getJWTSecretKey returns the JWT secret key, initializing it if necessary
-func getJWTSecretKey() []byte {
-	if len(jwtSecretKey) == 0 {
-		jwtSecretKey = []byte(config.GetEnvironment(config.JwtSecretKey))
+	if err := repository.NewUserRepository(config.DB).Save(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar usuário", "details": err.Error()})
+		return
 	}
-	return jwtSecretKey
+
+	c.JSON(http.StatusCreated, dto.NewUserResponse(*user))
 }
 
-func RegisterUser(c *gin.Context) {
+// InviteUser creates an inactive user and an invite token used to define the password (admin only)
+func InviteUser(c *gin.Context) {
+	var req dto.UserInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
 
-	var req dto.UserRequest
+	adminID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return
+	}
+
+	user, ok := buildNewUser(c, req.Name, req.Email, req.Role, req.OrganizationID, req.Phone, req.Address.ToModel())
+	if !ok {
+		return
+	}
+
+	// No password matches this hash until the invite is accepted
+	user.PasswordHash = "!"
+	user.IsActive = false
+
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar convite"})
+		return
+	}
+
+	invite := &model.UserInvite{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: security.HashOpaqueToken(token),
+		InvitedBy: adminID,
+		ExpiresAt: time.Now().Add(security.InviteTokenTTL),
+		CreatedAt: time.Now(),
+	}
+
+	if err := repository.NewUserInviteRepository(config.DB).SaveWithUser(user, invite); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar convite", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"user":         dto.NewUserResponse(*user),
+		"invite_token": token,
+		"expires_at":   invite.ExpiresAt,
+	})
+}
+
+// AcceptInvite defines the password of an invited user and activates the account
+func AcceptInvite(c *gin.Context) {
+	var req dto.AcceptInviteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
 		return
 	}
 
+	inviteRepo := repository.NewUserInviteRepository(config.DB)
+	invite, err := inviteRepo.FindPendingByTokenHash(security.HashOpaqueToken(req.Token))
+	if err != nil || time.Now().After(invite.ExpiresAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Convite inválido ou expirado"})
+		return
+	}
+
 	hashedPassword, err := security.HashPassword(req.Password)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar hash da senha"})
 		return
 	}
 
-	user := model.User{
-		ID:           uuid.New(),
-		Name:         req.Name,
-		Email:        req.Email,
-		PasswordHash: hashedPassword,
-		Role:         "professional",
-		Phone:        req.Phone,
-		IsActive:     true,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+	now := time.Now()
+	user := invite.User
+	user.PasswordHash = hashedPassword
+	user.IsActive = true
+	user.UpdatedAt = now
+	invite.AcceptedAt = &now
+
+	if err := inviteRepo.Accept(invite, &user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao aceitar convite", "details": err.Error()})
+		return
 	}
 
-	if err := config.DB.Create(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar usuário", "details": err.Error()})
+	c.JSON(http.StatusOK, dto.NewUserResponse(user))
+}
+
+// GetUsers lists users filtered by role, status, organization and name/email (admin only)
+func GetUsers(c *gin.Context) {
+	var filter port.UserFilter
+
+	if role := c.Query("role"); role != "" {
+		filter.Role = &role
+	}
+
+	if active := c.Query("is_active"); active != "" {
+		isActive, err := strconv.ParseBool(active)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro is_active inválido"})
+			return
+		}
+		filter.IsActive = &isActive
+	}
+
+	if organization := c.Query("organization_id"); organization != "" {
+		organizationID, err := uuid.Parse(organization)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID da organização inválido"})
+			return
+		}
+		filter.OrganizationID = &organizationID
+	}
+
+	filter.Search = c.Query("search")
+
+	query, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := repository.NewUserRepository(config.DB).List(filter, query)
+	if err != nil {
+		respondListError(c, err, "Erro ao buscar usuários")
+		return
+	}
+
+	respondList(c, page, dto.NewUserResponse)
+}
+
+// GetUser gets a user by ID (admin only)
+func GetUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	user, err := repository.NewUserRepository(config.DB).FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.NewUserResponse(user))
+	c.JSON(http.StatusOK, dto.NewUserResponse(*user))
 }
 
+// GetUserByEmail gets a user by email (admin only)
 func GetUserByEmail(c *gin.Context) {
 	email := c.Param("email")
 
-	var user model.User
-	if err := config.DB.Where("email = ?", email).First(&user).Error; err != nil {
+	user, err := repository.NewUserRepository(config.DB).FindByEmail(email)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.NewUserResponse(user))
+	c.JSON(http.StatusOK, dto.NewUserResponse(*user))
+
+}
+
+// UpdateUserStatus activates or deactivates a user (admin only)
+func UpdateUserStatus(c *gin.Context) {
+	var req dto.UserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, ok := findManagedUser(c)
+	if !ok {
+		return
+	}
+
+	user.IsActive = *req.IsActive
+	user.UpdatedAt = time.Now()
+	if !user.IsActive {
+		user.RevokeTokens(user.UpdatedAt)
+	}
+
+	if err := repository.NewUserRepository(config.DB).Update(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar usuário", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewUserResponse(*user))
+}
+
+// UpdateUserRole changes the role of a user (admin only)
+func UpdateUserRole(c *gin.Context) {
+	var req dto.UserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, ok := findManagedUser(c)
+	if !ok {
+		return
+	}
+
+	user.Role = req.Role
+	user.UpdatedAt = time.Now()
+
+	if err := repository.NewUserRepository(config.DB).Update(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar usuário", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewUserResponse(*user))
+}
+
+// GetMyProfile returns the profile of the authenticated user
+func GetMyProfile(c *gin.Context) {
+	user, ok := getAuthenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewUserResponse(*user))
+}
+
+// UpdateMyProfile updates the name, phone and address of the authenticated user
+func UpdateMyProfile(c *gin.Context) {
+	var req dto.UserProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, ok := getAuthenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	user.Name = req.Name
+	user.Phone = req.Phone
+	user.Address = req.Address.ToModel()
+	user.UpdatedAt = time.Now()
+
+	if errs := user.NormalizeContact(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return
+	}
+
+	if err := repository.NewUserRepository(config.DB).Update(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar perfil", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewUserResponse(*user))
+}
+
+// ChangeMyPassword changes the password of the authenticated user after checking the current one
+func ChangeMyPassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, ok := getAuthenticatedUser(c)
+	if !ok {
+		return
+	}
+
+	if !security.CheckPasswordHash(req.CurrentPassword, user.PasswordHash) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Senha atual incorreta"})
+		return
+	}
+
+	hashedPassword, err := security.HashPassword(req.NewPassword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar hash da senha"})
+		return
+	}
+
+	user.PasswordHash = hashedPassword
+	user.UpdatedAt = time.Now()
+	user.RevokeTokens(user.UpdatedAt)
+
+	if err := repository.NewUserRepository(config.DB).Update(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao alterar senha", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Senha alterada com sucesso"})
+}
 
+// buildNewUser validates the email and organization of a user being created by an admin
+func buildNewUser(c *gin.Context, name, email string, role, organization, phone *string, address model.Address) (*model.User, bool) {
+	userRepo := repository.NewUserRepository(config.DB)
+	if _, err := userRepo.FindByEmail(email); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "E-mail já cadastrado"})
+		return nil, false
+	}
+
+	user := &model.User{
+		ID:        uuid.New(),
+		Name:      name,
+		Email:     email,
+		Role:      model.UserRoleProfessional,
+		Phone:     phone,
+		Address:   address,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if role != nil {
+		user.Role = *role
+	}
+
+	if errs := user.NormalizeContact(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": errs})
+		return nil, false
+	}
+
+	if organization != nil {
+		organizationID, _ := uuid.Parse(*organization)
+		if _, err := repository.NewOrganizationRepository(config.DB).FindByID(organizationID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Organização não encontrada"})
+			return nil, false
+		}
+		user.OrganizationID = &organizationID
+	}
+
+	return user, true
+}
+
+// findManagedUser loads the user in the URL, preventing admins from changing their own status or role
+func findManagedUser(c *gin.Context) (*model.User, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return nil, false
+	}
+
+	adminID, err := getUserIDFromToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		return nil, false
+	}
+
+	if id == adminID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Não é permitido alterar o próprio status ou papel"})
+		return nil, false
+	}
+
+	user, err := repository.NewUserRepository(config.DB).FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return nil, false
+	}
+
+	return user, true
 }
 
 func Login(c *gin.Context) {
@@ -82,39 +412,94 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	var user model.User
-	if err := config.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
+	if !checkLoginThrottle(c, req.Email, nil) {
+		return
+	}
+
+	user, err := repository.NewUserRepository(config.DB).FindByEmail(req.Email)
+	if err != nil {
+		registerLoginFailure(c, req.Email, nil, model.LoginAttemptReasonInvalidCredentials)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário ou senha inválidos"})
 		return
 	}
 
 	if !security.CheckPasswordHash(req.Password, user.PasswordHash) {
+		registerLoginFailure(c, req.Email, &user.ID, model.LoginAttemptReasonInvalidCredentials)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário ou senha inválidos"})
 		return
 	}
 
 	// Check if user is active
 	if !user.IsActive {
+		registerLoginFailure(c, req.Email, &user.ID, model.LoginAttemptReasonInactiveAccount)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Conta inativa"})
 		return
 	}
 
-	// Update last login time
+	// Users with 2FA enabled must present a TOTP code before receiving the access token
+	if user.TwoFactorEnabled {
+		challengeToken, err := generateScopedJWT(*user, security.TokenTypeTwoFactorChallenge, security.ChallengeTokenTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar token"})
+			return
+		}
+
+		// The password was right, so the attempt is not a failure; the code is throttled on its own
+		releaseLoginAttempt(c, user.Email)
+		c.JSON(http.StatusOK, gin.H{
+			"two_factor_required": true,
+			"challenge_token":     challengeToken,
+		})
+		return
+	}
+
+	// Users covered by a 2FA policy must enroll before accessing the API
+	policyRepo := repository.NewTwoFactorPolicyRepository(config.DB)
+	required, err := policyRepo.IsRequiredFor(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar política de 2FA"})
+		return
+	}
+
+	if required {
+		enrollmentToken, err := generateScopedJWT(*user, security.TokenTypeTwoFactorEnrollment, security.ChallengeTokenTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar token"})
+			return
+		}
+
+		releaseLoginAttempt(c, user.Email)
+		c.JSON(http.StatusOK, gin.H{
+			"two_factor_enrollment_required": true,
+			"enrollment_token":               enrollmentToken,
+		})
+		return
+	}
+
+	completeLogin(c, user)
+}
+
+// completeLogin clears the throttling counters, updates the last login time and responds with the access token
+func completeLogin(c *gin.Context, user *model.User) {
+	if err := loginThrottler.RegisterSuccess(user.Email, c.ClientIP()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar tentativas de login"})
+		return
+	}
+
 	now := time.Now()
 	user.LastLoginAt = &now
-	if err := config.DB.Save(&user).Error; err != nil {
+	if err := repository.NewUserRepository(config.DB).Update(user); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar último login"})
 		return
 	}
 
-	token, err := generateJWT(user)
+	token, err := generateJWT(*user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar token"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"token": token})
-
 }
 
 func VerifyAuth(c *gin.Context) {
@@ -122,14 +507,15 @@ func VerifyAuth(c *gin.Context) {
 }
 
 func generateJWT(user model.User) (string, error) {
-	// Define as claims do token conforme especificação
-	claims := jwt.MapClaims{
-		"sub":  user.ID.String(),                      // Subject (ID do usuário)
-		"role": user.Role,                             // Papel do usuário
-		"exp":  time.Now().Add(time.Hour * 24).Unix(), // Token válido por 24 horas
-	}
+	return generateScopedJWT(user, security.TokenTypeAccess, security.AccessTokenTTL)
+}
+
+// generateScopedJWT signs a token of the given type ("typ" claim) valid for ttl
+func generateScopedJWT(user model.User, tokenType string, ttl time.Duration) (string, error) {
+	return security.Tokens().Issue(user.ID, user.Role, tokenType, ttl)
+}
 
-	// Cria o token assinado
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(getJWTSecretKey())
+// parseScopedJWT validates a token of the given type and returns its subject
+func parseScopedJWT(tokenString string, tokenType string) (uuid.UUID, error) {
+	return security.Tokens().ParseSubject(tokenString, tokenType)
 }
@@ -0,0 +1,66 @@
+package backfill
+
+import "gorm.io/gorm"
+
+// AnamneseVersionReport counts what the anamnese version backfill publishes and points to it
+type AnamneseVersionReport struct {
+	Templates int64 // Templates with fields written before versioning
+	Fields    int64 // Fields without a version
+	Anamneses int64 // Patient anamneses without a version
+}
+
+// anamneseVersionStatements publish the fields of the templates created before versioning as
+// their first version and point the anamneses already answered to it. Those anamneses were
+// written at once, so they are finalized when they were answered. Templates without fields
+// are left without a version, their first one is the draft created on the first edit.
+var anamneseVersionStatements = []string{
+	`INSERT INTO anamnese_template_versions (id, template_id, version, status, published_at, created_at, updated_at)
+		SELECT uuid_generate_v4(), t.id, 1, 'published', now(), now(), now()
+		FROM anamnese_templates t
+		WHERE NOT EXISTS (SELECT 1 FROM anamnese_template_versions v WHERE v.template_id = t.id)
+		AND EXISTS (SELECT 1 FROM anamnese_fields f WHERE f.anamnese_id = t.id)`,
+	`UPDATE anamnese_fields f SET version_id = v.id
+		FROM anamnese_template_versions v
+		WHERE f.version_id IS NULL AND v.template_id = f.anamnese_id AND v.version = 1`,
+	`UPDATE anamnese_fields SET field_key = id WHERE field_key IS NULL`,
+	`UPDATE anamnese_field_options SET option_key = id WHERE option_key IS NULL`,
+	`UPDATE patient_anamneses pa SET template_version_id = v.id
+		FROM anamnese_template_versions v
+		WHERE pa.template_version_id IS NULL AND v.template_id = pa.anamnese_id AND v.version = 1`,
+	`UPDATE patient_anamneses SET finalized_at = answered_at WHERE status = 'finalized' AND finalized_at IS NULL`,
+}
+
+// VersionAnamneseTemplates publishes the fields of the anamnese templates written before
+// versioning as their first version. Nothing is written unless apply is true.
+func VersionAnamneseTemplates(db *gorm.DB, apply bool) (AnamneseVersionReport, error) {
+	var report AnamneseVersionReport
+
+	err := db.Table("anamnese_templates t").
+		Where("NOT EXISTS (SELECT 1 FROM anamnese_template_versions v WHERE v.template_id = t.id)").
+		Where("EXISTS (SELECT 1 FROM anamnese_fields f WHERE f.anamnese_id = t.id)").
+		Count(&report.Templates).Error
+	if err != nil {
+		return report, err
+	}
+	err = db.Table("anamnese_fields").Where("version_id IS NULL").Count(&report.Fields).Error
+	if err != nil {
+		return report, err
+	}
+	err = db.Table("patient_anamneses").Where("template_version_id IS NULL").Count(&report.Anamneses).Error
+	if err != nil {
+		return report, err
+	}
+
+	if !apply {
+		return report, nil
+	}
+
+	return report, db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range anamneseVersionStatements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,86 @@
+package backfill
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// CareEpisodeReport counts what the care episode backfill opens and attaches
+type CareEpisodeReport struct {
+	Opened       int   // Active patients without an open episode
+	Appointments int64 // Appointments of active patients not attached to an episode
+	Sessions     int64 // Sessions of active patients not attached to an episode
+}
+
+// patientWithoutEpisode is an active patient without an open episode, with the start of the
+// episode to open: their first appointment not attached to an episode, else their creation
+type patientWithoutEpisode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	StartDate time.Time
+}
+
+// OpenCareEpisodes opens an episode for the active patients created before episodes of care
+// existed and attaches to it their appointments and sessions that have no episode. Inactive
+// patients are left without an episode. Nothing is written unless apply is true.
+func OpenCareEpisodes(db *gorm.DB, apply bool) (CareEpisodeReport, error) {
+	var report CareEpisodeReport
+
+	var patients []patientWithoutEpisode
+	err := db.Table("patients").
+		Select("patients.id, patients.user_id, COALESCE(" +
+			"(SELECT MIN(a.start_time) FROM appointments a WHERE a.patient_id = patients.id AND a.episode_id IS NULL), " +
+			"patients.created_at) AS start_date").
+		Where("patients.active").
+		Where("NOT EXISTS (SELECT 1 FROM care_episodes e WHERE e.patient_id = patients.id AND e.end_date IS NULL)").
+		Order("patients.id").
+		Scan(&patients).Error
+	if err != nil {
+		return report, err
+	}
+	report.Opened = len(patients)
+
+	err = db.Table("appointments").
+		Joins("JOIN patients ON patients.id = appointments.patient_id").
+		Where("patients.active AND appointments.episode_id IS NULL").
+		Count(&report.Appointments).Error
+	if err != nil {
+		return report, err
+	}
+	err = db.Table("sessions").
+		Joins("JOIN patients ON patients.id = sessions.patient_id").
+		Where("patients.active AND sessions.episode_id IS NULL").
+		Count(&report.Sessions).Error
+	if err != nil {
+		return report, err
+	}
+
+	if !apply {
+		return report, nil
+	}
+
+	return report, db.Transaction(func(tx *gorm.DB) error {
+		episodeRepo := repository.NewCareEpisodeRepository(tx)
+		for _, patient := range patients {
+			if _, err := episodeRepo.FindOrOpen(patient.UserID, patient.ID, patient.StartDate); err != nil {
+				return err
+			}
+		}
+
+		// Sessions follow the episode of their appointment
+		statements := []string{
+			`UPDATE appointments a SET episode_id = e.id FROM care_episodes e, patients p
+				WHERE e.patient_id = a.patient_id AND e.end_date IS NULL AND p.id = a.patient_id AND p.active AND a.episode_id IS NULL`,
+			`UPDATE sessions s SET episode_id = a.episode_id FROM appointments a, patients p
+				WHERE a.id = s.appointment_id AND a.episode_id IS NOT NULL AND p.id = s.patient_id AND p.active AND s.episode_id IS NULL`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
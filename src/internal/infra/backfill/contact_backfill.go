@@ -0,0 +1,173 @@
+package backfill
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/validation"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"regexp"
+)
+
+const batchSize = 500
+
+// legacyCEPPattern finds a CEP inside the free-text address of a patient
+var legacyCEPPattern = regexp.MustCompile(`\b\d{5}-?\d{3}\b`)
+
+// RowFailure is a row whose contact data could not be normalized
+type RowFailure struct {
+	ID     uuid.UUID
+	Errors validation.FieldErrors
+}
+
+// TableReport summarizes the backfill of one table
+type TableReport struct {
+	Table    string
+	Scanned  int
+	Updated  int // Rows changed, or that would be changed on a dry run
+	Failures []RowFailure
+}
+
+// normalizable is a model whose contact data can be normalized in place
+type normalizable interface {
+	NormalizeContact() validation.FieldErrors
+}
+
+// NormalizeContacts normalizes the documents, phones and addresses stored in patients,
+// leads, patient family members and users. Valid values are rewritten in their normalized
+// form and the rows with invalid values are reported and left untouched. Nothing is
+// written unless apply is true.
+func NormalizeContacts(db *gorm.DB, apply bool) ([]TableReport, error) {
+	var reports []TableReport
+
+	report, err := normalizeTable(db, "patients", apply,
+		func(p *model.Patient) uuid.UUID { return p.ID },
+		func(p *model.Patient) {
+			// Recover the CEP written in the free-text address before the structured one existed
+			if p.PostalAddress.CEP == nil && p.Address != nil {
+				if cep := legacyCEPPattern.FindString(*p.Address); cep != "" {
+					p.PostalAddress.CEP = &cep
+				}
+			}
+		},
+		func(p *model.Patient) map[string]interface{} {
+			return map[string]interface{}{
+				"document":                p.Document,
+				"phone":                   p.Phone,
+				"emergency_contact_phone": p.EmergencyContactPhone,
+				"address_cep":             p.PostalAddress.CEP,
+				"address_state":           p.PostalAddress.State,
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	report, err = normalizeTable(db, "leads", apply,
+		func(l *model.Lead) uuid.UUID { return l.ID },
+		nil,
+		func(l *model.Lead) map[string]interface{} {
+			return map[string]interface{}{
+				"phone":         l.Phone,
+				"address_cep":   l.Address.CEP,
+				"address_state": l.Address.State,
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	report, err = normalizeTable(db, "patient_families", apply,
+		func(pf *model.PatientFamily) uuid.UUID { return pf.ID },
+		nil,
+		func(pf *model.PatientFamily) map[string]interface{} {
+			return map[string]interface{}{
+				"document":      pf.Document,
+				"phone":         pf.Phone,
+				"address_cep":   pf.Address.CEP,
+				"address_state": pf.Address.State,
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	report, err = normalizeTable(db, "users", apply,
+		func(u *model.User) uuid.UUID { return u.ID },
+		nil,
+		func(u *model.User) map[string]interface{} {
+			return map[string]interface{}{
+				"phone":         u.Phone,
+				"address_cep":   u.Address.CEP,
+				"address_state": u.Address.State,
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	return reports, nil
+}
+
+// normalizeTable walks a table in batches, normalizing each row and updating the columns that changed
+func normalizeTable[T any, PT interface {
+	*T
+	normalizable
+}](db *gorm.DB, table string, apply bool, id func(PT) uuid.UUID, prepare func(PT), columns func(PT) map[string]interface{}) (TableReport, error) {
+	report := TableReport{Table: table}
+	var rows []T
+
+	result := db.Order("id").FindInBatches(&rows, batchSize, func(tx *gorm.DB, batch int) error {
+		for i := range rows {
+			row := PT(&rows[i])
+			report.Scanned++
+
+			before := snapshot(columns(row))
+			if prepare != nil {
+				prepare(row)
+			}
+			if errs := row.NormalizeContact(); len(errs) > 0 {
+				report.Failures = append(report.Failures, RowFailure{ID: id(row), Errors: errs})
+				continue
+			}
+
+			changes := make(map[string]interface{})
+			for column, value := range columns(row) {
+				if after := deref(value); after != before[column] {
+					changes[column] = value
+				}
+			}
+			if len(changes) == 0 {
+				continue
+			}
+
+			report.Updated++
+			if apply {
+				if err := db.Model(row).UpdateColumns(changes).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+
+	return report, result.Error
+}
+
+func snapshot(columns map[string]interface{}) map[string]string {
+	values := make(map[string]string, len(columns))
+	for column, value := range columns {
+		values[column] = deref(value)
+	}
+	return values
+}
+
+func deref(value interface{}) string {
+	if s, ok := value.(*string); ok && s != nil {
+		return *s
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package backfill
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LeadPipelineReport counts the users given the default lead pipeline
+type LeadPipelineReport struct {
+	Stages      int // Users without stages
+	LostReasons int // Users without lost reasons
+}
+
+// SeedLeadPipelines creates the default stages and lost reasons of the users created before
+// they were seeded with the user. Users who already have stages or lost reasons keep them as
+// they are. Nothing is written unless apply is true.
+func SeedLeadPipelines(db *gorm.DB, apply bool) (LeadPipelineReport, error) {
+	var report LeadPipelineReport
+
+	var withoutStages []uuid.UUID
+	err := db.Table("users").
+		Where("NOT EXISTS (SELECT 1 FROM lead_stages s WHERE s.user_id = users.id)").
+		Order("id").Pluck("id", &withoutStages).Error
+	if err != nil {
+		return report, err
+	}
+	report.Stages = len(withoutStages)
+
+	var withoutReasons []uuid.UUID
+	err = db.Table("users").
+		Where("NOT EXISTS (SELECT 1 FROM lead_lost_reasons r WHERE r.user_id = users.id)").
+		Order("id").Pluck("id", &withoutReasons).Error
+	if err != nil {
+		return report, err
+	}
+	report.LostReasons = len(withoutReasons)
+
+	if !apply {
+		return report, nil
+	}
+
+	stageRepo := repository.NewLeadStageRepository(db)
+	for _, userID := range withoutStages {
+		if err := stageRepo.CreateDefaults(userID); err != nil {
+			return report, err
+		}
+	}
+	reasonRepo := repository.NewLeadLostReasonRepository(db)
+	for _, userID := range withoutReasons {
+		if err := reasonRepo.CreateDefaults(userID); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
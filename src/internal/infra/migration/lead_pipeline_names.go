@@ -0,0 +1,44 @@
+package migration
+
+import "gorm.io/gorm"
+
+// leadPipelineNameStatements merge the stages and lost reasons duplicated by the defaults that
+// used to be created on the first listing, keeping the oldest row of each name, and make the
+// names unique per user so the defaults can be created idempotently
+var leadPipelineNameStatements = []string{
+	`WITH duplicates AS (
+		SELECT id, first_value(id) OVER (PARTITION BY user_id, name ORDER BY created_at, id) AS keep_id FROM lead_stages
+	)
+	UPDATE leads l SET stage_id = d.keep_id FROM duplicates d WHERE l.stage_id = d.id AND d.id <> d.keep_id`,
+	`WITH duplicates AS (
+		SELECT id, first_value(id) OVER (PARTITION BY user_id, name ORDER BY created_at, id) AS keep_id FROM lead_stages
+	)
+	UPDATE lead_stage_transitions t SET from_stage_id = d.keep_id FROM duplicates d WHERE t.from_stage_id = d.id AND d.id <> d.keep_id`,
+	`WITH duplicates AS (
+		SELECT id, first_value(id) OVER (PARTITION BY user_id, name ORDER BY created_at, id) AS keep_id FROM lead_stages
+	)
+	UPDATE lead_stage_transitions t SET to_stage_id = d.keep_id FROM duplicates d WHERE t.to_stage_id = d.id AND d.id <> d.keep_id`,
+	`WITH duplicates AS (
+		SELECT id, first_value(id) OVER (PARTITION BY user_id, name ORDER BY created_at, id) AS keep_id FROM lead_stages
+	)
+	DELETE FROM lead_stages s USING duplicates d WHERE s.id = d.id AND d.id <> d.keep_id`,
+	`WITH duplicates AS (
+		SELECT id, first_value(id) OVER (PARTITION BY user_id, name ORDER BY created_at, id) AS keep_id FROM lead_lost_reasons
+	)
+	UPDATE leads l SET lost_reason_id = d.keep_id FROM duplicates d WHERE l.lost_reason_id = d.id AND d.id <> d.keep_id`,
+	`WITH duplicates AS (
+		SELECT id, first_value(id) OVER (PARTITION BY user_id, name ORDER BY created_at, id) AS keep_id FROM lead_lost_reasons
+	)
+	DELETE FROM lead_lost_reasons r USING duplicates d WHERE r.id = d.id AND d.id <> d.keep_id`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_lead_stages_user_name ON lead_stages (user_id, name)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_lead_lost_reasons_user_name ON lead_lost_reasons (user_id, name)`,
+}
+
+func migrateLeadPipelineNames(db *gorm.DB) error {
+	for _, statement := range leadPipelineNameStatements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -10,28 +10,74 @@ func Migrate() {
 	db := config.DB
 
 	err := db.AutoMigrate(
+		&model.Organization{},
 		&model.User{},
+		&model.UserRecoveryCode{},
+		&model.TwoFactorPolicy{},
+		&model.LoginAttempt{},
+		&model.UserInvite{},
+		&model.SigningKey{},
+		&model.APIKey{},
 		&model.AnamneseTemplate{},
+		&model.AnamneseTemplateVersion{},
 		&model.AnamneseField{},
 		&model.AnamneseFieldOption{},
 		&model.PatientAnamnese{},
 		&model.PatientAnamneseField{},
+		&model.PatientAnamneseFieldHistory{},
+		&model.PatientAnamneseLink{},
+		&model.PatientAnamneseLinkAccess{},
 		&model.Appointment{},
 		&model.Session{},
 		&model.Evolution{},
+		&model.Scale{},
+		&model.ScaleItem{},
+		&model.ScaleOption{},
+		&model.ScaleBand{},
+		&model.ScaleApplication{},
+		&model.ScaleAnswer{},
+		&model.TreatmentPlan{},
+		&model.TreatmentPlanVersion{},
+		&model.TreatmentGoal{},
+		&model.TreatmentGoalProgress{},
+		&model.CareEpisode{},
 		&model.CostCenter{},
 		&model.Payment{},
 		&model.PaymentAppointment{},
 		&model.Repasse{},
 		&model.Lead{},
+		&model.LeadConsent{},
+		&model.LeadStage{},
+		&model.LeadLostReason{},
+		&model.LeadActivity{},
+		&model.LeadStageTransition{},
 		&model.Patient{},
 		&model.PatientFamily{},
+		&model.PatientConsent{},
+		&model.PatientFamilyLink{},
+		&model.PatientMerge{},
 	)
 
 	if err != nil {
 		log.Fatalf("Erro ao rodar migrations: %v", err)
 	}
 
+	if err := migrateSearchIndexes(db); err != nil {
+		log.Fatalf("Erro ao criar índices de busca: %v", err)
+	}
+
+	if err := migrateLeadPipelineNames(db); err != nil {
+		log.Fatalf("Erro ao unificar etapas e motivos de perda: %v", err)
+	}
+
+	if err := migrateTreatmentPlans(db); err != nil {
+		log.Fatalf("Erro ao restringir planos de tratamento ativos: %v", err)
+	}
+
+	if err := migratePatientAnamneseAnswers(db); err != nil {
+		log.Fatalf("Erro ao restringir respostas duplicadas de anamnese: %v", err)
+	}
+
 	log.Println("Migrations aplicadas com sucesso.")
 
 }
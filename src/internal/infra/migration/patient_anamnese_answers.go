@@ -0,0 +1,23 @@
+package migration
+
+import "gorm.io/gorm"
+
+// patientAnamneseAnswerStatements remove the answers of a field duplicated by concurrent
+// changes, keeping the most recently updated one, and make the answers unique per field
+var patientAnamneseAnswerStatements = []string{
+	`WITH ranked AS (
+		SELECT id, row_number() OVER (PARTITION BY patient_anamnese_id, field_id ORDER BY updated_at DESC, id) AS position
+		FROM patient_anamnese_fields
+	)
+	DELETE FROM patient_anamnese_fields f USING ranked r WHERE f.id = r.id AND r.position > 1`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_patient_anamnese_fields_anamnese_field ON patient_anamnese_fields (patient_anamnese_id, field_id)`,
+}
+
+func migratePatientAnamneseAnswers(db *gorm.DB) error {
+	for _, statement := range patientAnamneseAnswerStatements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package migration
+
+import "gorm.io/gorm"
+
+// searchStatements enable accent-insensitive trigram search on patient names and index
+// the other columns filtered by the patient search
+var searchStatements = []string{
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE EXTENSION IF NOT EXISTS unaccent`,
+	// unaccent is only STABLE, an IMMUTABLE wrapper is required to use it in an index
+	`CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text
+		AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$
+		LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT`,
+	`CREATE INDEX IF NOT EXISTS idx_patients_full_name_trgm ON patients USING gin (immutable_unaccent(lower(full_name)) gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_patients_social_name_trgm ON patients USING gin (immutable_unaccent(lower(social_name)) gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_patients_user_document ON patients (user_id, document)`,
+	`CREATE INDEX IF NOT EXISTS idx_patients_user_phone ON patients (user_id, phone)`,
+	`CREATE INDEX IF NOT EXISTS idx_patients_user_birth_date ON patients (user_id, birth_date)`,
+	`CREATE INDEX IF NOT EXISTS idx_sessions_patient_start_time ON sessions (patient_id, start_time)`,
+}
+
+func migrateSearchIndexes(db *gorm.DB) error {
+	for _, statement := range searchStatements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package migration
+
+import "gorm.io/gorm"
+
+// treatmentPlanStatements discontinue all but the most recent active plan of each patient,
+// left by merges, and make sure a patient has at most one active plan
+var treatmentPlanStatements = []string{
+	`WITH ranked AS (
+		SELECT id, row_number() OVER (PARTITION BY patient_id ORDER BY start_date DESC, created_at DESC, id) AS position
+		FROM treatment_plans WHERE status = 'active'
+	)
+	UPDATE treatment_plans p SET status = 'discontinued', ended_at = now() FROM ranked r WHERE p.id = r.id AND r.position > 1`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_treatment_plans_patient_active ON treatment_plans (patient_id) WHERE status = 'active'`,
+}
+
+func migrateTreatmentPlans(db *gorm.DB) error {
+	for _, statement := range treatmentPlanStatements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/LacirJR/psygrow-api/src/internal/core/port"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AnamneseTemplateRepository implementation
@@ -52,13 +53,83 @@ func (r *anamneseTemplateRepository) Update(template *model.AnamneseTemplate) er
 	return r.db.Save(template).Error
 }
 
+func (r *anamneseTemplateRepository) CreateWithVersion(template *model.AnamneseTemplate, version *model.AnamneseTemplateVersion, fields []*model.AnamneseField) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createTemplateWithVersion(tx, template, version, fields)
+	})
+}
+
+func (r *anamneseTemplateRepository) CreateCopies(copies []port.AnamneseTemplateCopy) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, copied := range copies {
+			if err := createTemplateWithVersion(tx, copied.Template, copied.Version, copied.Fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// createTemplateWithVersion creates a template with a version and its fields and options
+func createTemplateWithVersion(tx *gorm.DB, template *model.AnamneseTemplate, version *model.AnamneseTemplateVersion, fields []*model.AnamneseField) error {
+	if err := tx.Create(template).Error; err != nil {
+		return err
+	}
+	if err := tx.Omit("Fields").Create(version).Error; err != nil {
+		return err
+	}
+	for _, field := range fields {
+		// Select every column so that inactive fields are not created with the default
+		if err := tx.Select("*").Omit("Options").Create(field).Error; err != nil {
+			return err
+		}
+		if len(field.Options) == 0 {
+			continue
+		}
+		if err := tx.Create(&field.Options).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *anamneseTemplateRepository) FindBuiltIn() ([]*model.AnamneseTemplate, error) {
+	var templates []*model.AnamneseTemplate
+	err := r.db.Where("built_in_key IS NOT NULL").Order("title").Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *anamneseTemplateRepository) FindByBuiltInKey(key string) (*model.AnamneseTemplate, error) {
+	var template model.AnamneseTemplate
+	err := r.db.Where("built_in_key = ?", key).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
 func (r *anamneseTemplateRepository) Delete(id string) error {
 	templateID, err := uuid.Parse(id)
 	if err != nil {
 		return err
 	}
 
-	return r.db.Delete(&model.AnamneseTemplate{}, templateID).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		fields := tx.Model(&model.AnamneseField{}).Select("id").Where("anamnese_id = ?", templateID)
+		if err := tx.Where("anamnese_field_id IN (?)", fields).Delete(&model.AnamneseFieldOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("anamnese_id = ?", templateID).Delete(&model.AnamneseField{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", templateID).Delete(&model.AnamneseTemplateVersion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.AnamneseTemplate{}, templateID).Error
+	})
 }
 
 // AnamneseFieldRepository implementation
@@ -88,20 +159,36 @@ func (r *anamneseFieldRepository) FindByID(id string) (*model.AnamneseField, err
 	return &field, nil
 }
 
-func (r *anamneseFieldRepository) FindByAnamneseID(anamneseID string) ([]*model.AnamneseField, error) {
+func (r *anamneseFieldRepository) FindByVersionID(versionID string) ([]*model.AnamneseField, error) {
 	var fields []*model.AnamneseField
-	parsedAnamneseID, err := uuid.Parse(anamneseID)
+	parsedVersionID, err := uuid.Parse(versionID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = r.db.Where("anamnese_id = ?", parsedAnamneseID).Preload("Options").Order("field_number").Find(&fields).Error
+	err = r.db.Where("version_id = ?", parsedVersionID).Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("option_order")
+	}).Order("field_number").Find(&fields).Error
 	if err != nil {
 		return nil, err
 	}
 	return fields, nil
 }
 
+func (r *anamneseFieldRepository) FindByKey(versionID string, fieldKey uuid.UUID) (*model.AnamneseField, error) {
+	var field model.AnamneseField
+	parsedVersionID, err := uuid.Parse(versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Where("version_id = ? AND field_key = ?", parsedVersionID, fieldKey).Preload("Options").First(&field).Error
+	if err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
 func (r *anamneseFieldRepository) Update(field *model.AnamneseField) error {
 	return r.db.Save(field).Error
 }
@@ -115,6 +202,35 @@ func (r *anamneseFieldRepository) Delete(id string) error {
 	return r.db.Delete(&model.AnamneseField{}, fieldID).Error
 }
 
+func (r *anamneseFieldRepository) Reorder(versionID uuid.UUID, fieldIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the fields so that none is added or deleted while they are numbered
+		var fields []model.AnamneseField
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("version_id = ?", versionID).
+			Find(&fields).Error; err != nil {
+			return err
+		}
+		if len(fields) != len(fieldIDs) {
+			return port.ErrFieldOrderIncomplete
+		}
+
+		for i, fieldID := range fieldIDs {
+			result := tx.Model(&model.AnamneseField{}).
+				Where("id = ? AND version_id = ?", fieldID, versionID).
+				Update("field_number", i+1)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+}
+
 // PatientAnamneseRepository implementation
 type patientAnamneseRepository struct {
 	db *gorm.DB
@@ -128,6 +244,97 @@ func (r *patientAnamneseRepository) Save(patientAnamnese *model.PatientAnamnese)
 	return r.db.Create(patientAnamnese).Error
 }
 
+func (r *patientAnamneseRepository) SaveWithFields(patientAnamnese *model.PatientAnamnese, fields []*model.PatientAnamneseField) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(patientAnamnese).Error; err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			return nil
+		}
+		return tx.Create(&fields).Error
+	})
+}
+
+func (r *patientAnamneseRepository) Update(patientAnamnese *model.PatientAnamnese) error {
+	return r.db.Save(patientAnamnese).Error
+}
+
+func (r *patientAnamneseRepository) UpdateAnswers(patientAnamnese *model.PatientAnamnese, fn func(stored []*model.PatientAnamneseField) (port.PatientAnamneseAnswerChanges, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		stored, err := lockDraftAnswers(tx, patientAnamnese.ID)
+		if err != nil {
+			return err
+		}
+		changes, err := fn(stored)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.PatientAnamnese{}).
+			Where("id = ?", patientAnamnese.ID).
+			Update("updated_at", patientAnamnese.UpdatedAt).Error; err != nil {
+			return err
+		}
+		for _, field := range changes.Saved {
+			if err := tx.Save(field).Error; err != nil {
+				return err
+			}
+		}
+		if len(changes.Removed) > 0 {
+			if err := tx.Where("id IN ?", changes.Removed).Delete(&model.PatientAnamneseField{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(changes.History) > 0 {
+			return tx.Create(&changes.History).Error
+		}
+		return nil
+	})
+}
+
+func (r *patientAnamneseRepository) Finalize(patientAnamnese *model.PatientAnamnese, check func(stored []*model.PatientAnamneseField) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		stored, err := lockDraftAnswers(tx, patientAnamnese.ID)
+		if err != nil {
+			return err
+		}
+		if err := check(stored); err != nil {
+			return err
+		}
+
+		return tx.Model(&model.PatientAnamnese{}).
+			Where("id = ?", patientAnamnese.ID).
+			Updates(map[string]interface{}{
+				"status":       patientAnamnese.Status,
+				"answered_at":  patientAnamnese.AnsweredAt,
+				"finalized_at": patientAnamnese.FinalizedAt,
+				"updated_at":   patientAnamnese.UpdatedAt,
+			}).Error
+	})
+}
+
+// lockDraftAnswers locks a patient anamnese within a transaction, so that its answers cannot
+// change nor be finalized concurrently, and loads its answers. It fails with
+// ErrPatientAnamneseFinalized when the anamnese is no longer a draft.
+func lockDraftAnswers(tx *gorm.DB, patientAnamneseID uuid.UUID) ([]*model.PatientAnamneseField, error) {
+	var current model.PatientAnamnese
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", patientAnamneseID).
+		First(&current).Error; err != nil {
+		return nil, err
+	}
+	if !current.IsDraft() {
+		return nil, port.ErrPatientAnamneseFinalized
+	}
+
+	var stored []*model.PatientAnamneseField
+	if err := tx.Where("patient_anamnese_id = ?", patientAnamneseID).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
 func (r *patientAnamneseRepository) FindByID(id string) (*model.PatientAnamnese, error) {
 	var patientAnamnese model.PatientAnamnese
 	patientAnamneseID, err := uuid.Parse(id)
@@ -170,6 +377,17 @@ func (r *patientAnamneseRepository) FindByUserID(userID string) ([]*model.Patien
 	return patientAnamneses, nil
 }
 
+func (r *patientAnamneseRepository) CountByTemplateID(templateID string) (int64, error) {
+	var count int64
+	parsedTemplateID, err := uuid.Parse(templateID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.db.Model(&model.PatientAnamnese{}).Where("anamnese_id = ?", parsedTemplateID).Count(&count).Error
+	return count, err
+}
+
 // AnamneseFieldOptionRepository implementation
 type anamneseFieldOptionRepository struct {
 	db *gorm.DB
@@ -237,6 +455,99 @@ func (r *anamneseFieldOptionRepository) DeleteByAnamneseFieldID(anamneseFieldID
 	return r.db.Where("anamnese_field_id = ?", parsedAnamneseFieldID).Delete(&model.AnamneseFieldOption{}).Error
 }
 
+func (r *anamneseFieldOptionRepository) Reorder(anamneseFieldID uuid.UUID, optionIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, optionID := range optionIDs {
+			result := tx.Model(&model.AnamneseFieldOption{}).
+				Where("id = ? AND anamnese_field_id = ?", optionID, anamneseFieldID).
+				Update("option_order", i+1)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+}
+
+func (r *anamneseFieldOptionRepository) DeleteUnused(option *model.AnamneseFieldOption) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current model.AnamneseFieldOption
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", option.ID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if err := checkOptionsUnused(tx, []model.AnamneseFieldOption{current}); err != nil {
+			return err
+		}
+		return tx.Delete(&current).Error
+	})
+}
+
+func (r *anamneseFieldOptionRepository) ReplaceByAnamneseFieldID(anamneseFieldID uuid.UUID, options []*model.AnamneseFieldOption) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		kept := make([]uuid.UUID, len(options))
+		for i, option := range options {
+			kept[i] = option.ID
+		}
+
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("anamnese_field_id = ?", anamneseFieldID)
+		if len(kept) > 0 {
+			query = query.Where("id NOT IN ?", kept)
+		}
+		var removed []model.AnamneseFieldOption
+		if err := query.Find(&removed).Error; err != nil {
+			return err
+		}
+		if err := checkOptionsUnused(tx, removed); err != nil {
+			return err
+		}
+		if len(removed) > 0 {
+			if err := tx.Delete(&removed).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, option := range options {
+			if err := tx.Save(option).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// checkOptionsUnused fails with an AnamneseOptionInUseError for the first of the options that
+// was chosen in patient answers
+func checkOptionsUnused(tx *gorm.DB, options []model.AnamneseFieldOption) error {
+	for _, option := range options {
+		answers, err := countAnswersByOptionKey(tx, option.OptionKey)
+		if err != nil {
+			return err
+		}
+		if answers > 0 {
+			return &port.AnamneseOptionInUseError{OptionID: option.ID}
+		}
+	}
+	return nil
+}
+
+// countAnswersByOptionKey counts the patient answers choosing any copy of an option
+func countAnswersByOptionKey(db *gorm.DB, optionKey uuid.UUID) (int64, error) {
+	// Answers hold the option value of the version they were given against
+	var count int64
+	err := db.Table("patient_anamnese_fields").
+		Joins("JOIN anamnese_field_options ON anamnese_field_options.anamnese_field_id = patient_anamnese_fields.field_id").
+		Where("anamnese_field_options.option_key = ?", optionKey).
+		Where("(patient_anamnese_fields.value = anamnese_field_options.option_value OR " +
+			"patient_anamnese_fields.\"values\" @> jsonb_build_array(anamnese_field_options.option_value))").
+		Count(&count).Error
+	return count, err
+}
+
 // PatientAnamneseFieldRepository implementation
 type patientAnamneseFieldRepository struct {
 	db *gorm.DB
@@ -263,3 +574,17 @@ func (r *patientAnamneseFieldRepository) FindByPatientAnamneseID(patientAnamnese
 	}
 	return fields, nil
 }
+
+func (r *patientAnamneseFieldRepository) FindHistoryByPatientAnamneseID(patientAnamneseID string) ([]*model.PatientAnamneseFieldHistory, error) {
+	var history []*model.PatientAnamneseFieldHistory
+	parsedPatientAnamneseID, err := uuid.Parse(patientAnamneseID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Where("patient_anamnese_id = ?", parsedPatientAnamneseID).Order("changed_at DESC").Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
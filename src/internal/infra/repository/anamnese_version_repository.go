@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// AnamneseTemplateVersionRepository implementation
+type anamneseTemplateVersionRepository struct {
+	db *gorm.DB
+}
+
+func NewAnamneseTemplateVersionRepository(db *gorm.DB) port.AnamneseTemplateVersionRepository {
+	return &anamneseTemplateVersionRepository{db: db}
+}
+
+func (r *anamneseTemplateVersionRepository) FindByID(id string) (*model.AnamneseTemplateVersion, error) {
+	var version model.AnamneseTemplateVersion
+	versionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Where("id = ?", versionID).First(&version).Error
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+func (r *anamneseTemplateVersionRepository) FindByIDWithFields(id string) (*model.AnamneseTemplateVersion, error) {
+	var version model.AnamneseTemplateVersion
+	versionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Where("id = ?", versionID).
+		Preload("Fields", func(db *gorm.DB) *gorm.DB {
+			return db.Order("field_number")
+		}).
+		Preload("Fields.Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("option_order")
+		}).
+		First(&version).Error
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+func (r *anamneseTemplateVersionRepository) FindByTemplateID(templateID string) ([]*model.AnamneseTemplateVersion, error) {
+	var versions []*model.AnamneseTemplateVersion
+	parsedTemplateID, err := uuid.Parse(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Where("template_id = ?", parsedTemplateID).Order("version DESC").Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *anamneseTemplateVersionRepository) FindDraft(templateID string) (*model.AnamneseTemplateVersion, error) {
+	return r.findLatest(r.db, templateID, model.AnamneseVersionStatusDraft)
+}
+
+func (r *anamneseTemplateVersionRepository) FindLatestPublished(templateID string) (*model.AnamneseTemplateVersion, error) {
+	return r.findLatest(r.db, templateID, model.AnamneseVersionStatusPublished)
+}
+
+func (r *anamneseTemplateVersionRepository) findLatest(db *gorm.DB, templateID string, status string) (*model.AnamneseTemplateVersion, error) {
+	var version model.AnamneseTemplateVersion
+	parsedTemplateID, err := uuid.Parse(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Where("template_id = ? AND status = ?", parsedTemplateID, status).
+		Order("version DESC").
+		First(&version).Error
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+func (r *anamneseTemplateVersionRepository) FindOrCreateDraft(templateID string) (*model.AnamneseTemplateVersion, error) {
+	parsedTemplateID, err := uuid.Parse(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var draft *model.AnamneseTemplateVersion
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the template so that concurrent edits do not create two drafts
+		var template model.AnamneseTemplate
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", parsedTemplateID).
+			First(&template).Error; err != nil {
+			return err
+		}
+
+		existing, err := r.findLatest(tx, templateID, model.AnamneseVersionStatusDraft)
+		if err == nil {
+			draft = existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&model.AnamneseTemplateVersion{}).
+			Where("template_id = ?", parsedTemplateID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		draft = &model.AnamneseTemplateVersion{
+			ID:         uuid.New(),
+			TemplateID: parsedTemplateID,
+			Version:    latest + 1,
+			Status:     model.AnamneseVersionStatusDraft,
+		}
+		if err := tx.Create(draft).Error; err != nil {
+			return err
+		}
+
+		published, err := r.findLatest(tx, templateID, model.AnamneseVersionStatusPublished)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return copyVersionFields(tx, published.ID, draft.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// copyVersionFields copies the fields and options of a version into another one, keeping
+// their keys so that the copies can be matched with the originals
+func copyVersionFields(tx *gorm.DB, fromVersionID uuid.UUID, toVersionID uuid.UUID) error {
+	var fields []model.AnamneseField
+	if err := tx.Where("version_id = ?", fromVersionID).Preload("Options").Find(&fields).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, field := range fields {
+		options := field.Options
+		field.ID = uuid.New()
+		field.VersionID = toVersionID
+		field.Options = nil
+		field.CreatedAt, field.UpdatedAt = now, now
+		if err := tx.Create(&field).Error; err != nil {
+			return err
+		}
+
+		for _, option := range options {
+			option.ID = uuid.New()
+			option.AnamneseFieldID = field.ID
+			option.CreatedAt, option.UpdatedAt = now, now
+			if err := tx.Create(&option).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *anamneseTemplateVersionRepository) Publish(version *model.AnamneseTemplateVersion) error {
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the template as FindOrCreateDraft does, so that a draft is never published
+		// while another one is being created from the previous version
+		var template model.AnamneseTemplate
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", version.TemplateID).
+			First(&template).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.AnamneseTemplateVersion{}).
+			Where("id = ? AND status = ?", version.ID, model.AnamneseVersionStatusDraft).
+			Updates(map[string]interface{}{
+				"status":       model.AnamneseVersionStatusPublished,
+				"published_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	version.Status = model.AnamneseVersionStatusPublished
+	version.PublishedAt = &now
+	return nil
+}
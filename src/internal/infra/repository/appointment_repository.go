@@ -34,18 +34,31 @@ func (r *appointmentRepository) FindByID(id string) (*model.Appointment, error)
 	return &appointment, nil
 }
 
-func (r *appointmentRepository) FindByUserID(userID string) ([]*model.Appointment, error) {
-	var appointments []*model.Appointment
+// appointmentListSpec declares the sorts and filters accepted when listing appointments
+var appointmentListSpec = listSpec{
+	sorts: map[string]string{
+		"start_time": "start_time",
+		"end_time":   "end_time",
+		"created_at": "created_at",
+	},
+	defaultSort: "start_time",
+	defaultDesc: true,
+	dateColumn:  "start_time",
+	filters: map[string]listFilter{
+		"status":          stringFilter("status"),
+		"patient_id":      uuidFilter("patient_id"),
+		"professional_id": uuidFilter("professional_id"),
+		"cost_center_id":  uuidFilter("cost_center_id"),
+	},
+}
+
+func (r *appointmentRepository) List(userID string, query port.ListQuery) (port.Page[model.Appointment], error) {
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, err
+		return port.Page[model.Appointment]{}, err
 	}
 
-	err = r.db.Where("user_id = ?", parsedUserID).Find(&appointments).Error
-	if err != nil {
-		return nil, err
-	}
-	return appointments, nil
+	return listPage[model.Appointment](r.db.Where("user_id = ?", parsedUserID), appointmentListSpec, query)
 }
 
 func (r *appointmentRepository) FindByPatientID(patientID string) ([]*model.Appointment, error) {
@@ -130,18 +143,29 @@ func (r *sessionRepository) FindByAppointmentID(appointmentID string) (*model.Se
 	return &session, nil
 }
 
-func (r *sessionRepository) FindByUserID(userID string) ([]*model.Session, error) {
-	var sessions []*model.Session
+// sessionListSpec declares the sorts and filters accepted when listing sessions
+var sessionListSpec = listSpec{
+	sorts: map[string]string{
+		"start_time": "start_time",
+		"created_at": "created_at",
+	},
+	defaultSort: "start_time",
+	defaultDesc: true,
+	dateColumn:  "start_time",
+	filters: map[string]listFilter{
+		"patient_id":      uuidFilter("patient_id"),
+		"professional_id": uuidFilter("professional_id"),
+		"was_attended":    boolFilter("was_attended"),
+	},
+}
+
+func (r *sessionRepository) List(userID string, query port.ListQuery) (port.Page[model.Session], error) {
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, err
+		return port.Page[model.Session]{}, err
 	}
 
-	err = r.db.Where("user_id = ?", parsedUserID).Find(&sessions).Error
-	if err != nil {
-		return nil, err
-	}
-	return sessions, nil
+	return listPage[model.Session](r.db.Where("user_id = ?", parsedUserID), sessionListSpec, query)
 }
 
 func (r *sessionRepository) FindByPatientID(patientID string) ([]*model.Session, error) {
@@ -185,6 +209,18 @@ func (r *evolutionRepository) Save(evolution *model.Evolution) error {
 	return r.db.Create(evolution).Error
 }
 
+func (r *evolutionRepository) SaveWithGoalProgress(evolution *model.Evolution, progress []model.TreatmentGoalProgress) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(evolution).Error; err != nil {
+			return err
+		}
+		if len(progress) == 0 {
+			return nil
+		}
+		return tx.Create(&progress).Error
+	})
+}
+
 func (r *evolutionRepository) FindByID(id string) (*model.Evolution, error) {
 	var evolution model.Evolution
 	evolutionID, err := uuid.Parse(id)
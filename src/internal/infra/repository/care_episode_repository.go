@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type careEpisodeRepository struct {
+	db *gorm.DB
+}
+
+func NewCareEpisodeRepository(db *gorm.DB) port.CareEpisodeRepository {
+	return &careEpisodeRepository{db: db}
+}
+
+func (r *careEpisodeRepository) Open(episode *model.CareEpisode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return openCareEpisode(tx, episode)
+	})
+}
+
+func (r *careEpisodeRepository) FindOrOpen(userID uuid.UUID, patientID uuid.UUID, start time.Time) (*model.CareEpisode, error) {
+	var episode *model.CareEpisode
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		episode, err = findOrOpenCareEpisode(tx, userID, patientID, start)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return episode, nil
+}
+
+func (r *careEpisodeRepository) Schedule(appointment *model.Appointment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		episode, err := findOrOpenCareEpisode(tx, appointment.UserID, appointment.PatientID, appointment.StartTime)
+		if err != nil {
+			return err
+		}
+		appointment.EpisodeID = &episode.ID
+		return tx.Create(appointment).Error
+	})
+}
+
+func (r *careEpisodeRepository) FindByID(id uuid.UUID, patientID uuid.UUID) (*model.CareEpisode, error) {
+	var episode model.CareEpisode
+	err := r.db.Where("id = ? AND patient_id = ?", id, patientID).First(&episode).Error
+	if err != nil {
+		return nil, err
+	}
+	return &episode, nil
+}
+
+func (r *careEpisodeRepository) FindByPatient(patientID uuid.UUID) ([]model.CareEpisode, error) {
+	var episodes []model.CareEpisode
+	err := r.db.Where("patient_id = ?", patientID).Order("number DESC").Find(&episodes).Error
+	return episodes, err
+}
+
+func (r *careEpisodeRepository) Close(episode *model.CareEpisode) (int64, error) {
+	var canceled int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the episode so concurrent requests cannot close it twice
+		var current model.CareEpisode
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", episode.ID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if !current.IsOpen() {
+			return port.ErrCareEpisodeClosed
+		}
+
+		if err := closeCareEpisode(tx, episode); err != nil {
+			return err
+		}
+
+		var err error
+		canceled, err = deactivatePatient(tx, episode.PatientID)
+		return err
+	})
+	return canceled, err
+}
+
+func (r *careEpisodeRepository) Deactivate(patientID uuid.UUID, reason string) (int64, error) {
+	var canceled int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		canceled, err = deactivateWithEpisode(tx, patientID, reason)
+		return err
+	})
+	return canceled, err
+}
+
+func (r *careEpisodeRepository) CreatePatient(patient *model.Patient) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(patient).Error; err != nil {
+			return err
+		}
+		if !patient.IsActive {
+			return nil
+		}
+		_, err := findOrOpenCareEpisode(tx, patient.UserID, patient.ID, time.Now())
+		return err
+	})
+}
+
+func (r *careEpisodeRepository) UpdatePatient(patient *model.Patient, wasActive bool, reason string) (int64, error) {
+	var canceled int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(patient).Error; err != nil {
+			return err
+		}
+
+		var err error
+		if wasActive && !patient.IsActive {
+			canceled, err = deactivateWithEpisode(tx, patient.ID, reason)
+		} else if !wasActive && patient.IsActive {
+			_, err = findOrOpenCareEpisode(tx, patient.UserID, patient.ID, time.Now())
+		}
+		return err
+	})
+	return canceled, err
+}
+
+// findOrOpenCareEpisode finds the open episode of a patient within a transaction, opening one
+// that starts at the given time when there is none
+func findOrOpenCareEpisode(tx *gorm.DB, userID uuid.UUID, patientID uuid.UUID, start time.Time) (*model.CareEpisode, error) {
+	episode := &model.CareEpisode{ID: uuid.New(), UserID: userID, PatientID: patientID, StartDate: start}
+	err := openCareEpisode(tx, episode)
+	if errors.Is(err, port.ErrCareEpisodeAlreadyOpen) {
+		var open model.CareEpisode
+		if err := tx.Where("patient_id = ? AND end_date IS NULL", patientID).First(&open).Error; err != nil {
+			return nil, err
+		}
+		return &open, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return episode, nil
+}
+
+// deactivateWithEpisode deactivates a patient within a transaction, closing their open episode,
+// if any, for the given reason and canceling their appointments still scheduled from now on
+func deactivateWithEpisode(tx *gorm.DB, patientID uuid.UUID, reason string) (int64, error) {
+	var episode model.CareEpisode
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("patient_id = ? AND end_date IS NULL", patientID).
+		First(&episode).Error
+	if err == nil {
+		now := time.Now()
+		episode.EndDate = &now
+		episode.EndReason = &reason
+		if err := closeCareEpisode(tx, &episode); err != nil {
+			return 0, err
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	return deactivatePatient(tx, patientID)
+}
+
+// openCareEpisode opens an episode within a transaction. The patient is locked so concurrent
+// requests cannot open two episodes, nor give two of them the same number.
+func openCareEpisode(tx *gorm.DB, episode *model.CareEpisode) error {
+	var patient model.Patient
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", episode.PatientID).
+		First(&patient).Error; err != nil {
+		return err
+	}
+
+	var open int64
+	if err := tx.Model(&model.CareEpisode{}).
+		Where("patient_id = ? AND end_date IS NULL", episode.PatientID).
+		Count(&open).Error; err != nil {
+		return err
+	}
+	if open > 0 {
+		return port.ErrCareEpisodeAlreadyOpen
+	}
+
+	var last int
+	if err := tx.Model(&model.CareEpisode{}).
+		Where("patient_id = ?", episode.PatientID).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&last).Error; err != nil {
+		return err
+	}
+
+	episode.Number = last + 1
+	if err := tx.Create(episode).Error; err != nil {
+		return err
+	}
+	return tx.Model(&patient).Update("active", true).Error
+}
+
+// closeCareEpisode records the end of an episode within a transaction
+func closeCareEpisode(tx *gorm.DB, episode *model.CareEpisode) error {
+	return tx.Model(episode).Updates(map[string]interface{}{
+		"end_date":          episode.EndDate,
+		"end_reason":        episode.EndReason,
+		"referred_to":       episode.ReferredTo,
+		"discharge_summary": episode.DischargeSummary,
+	}).Error
+}
+
+// deactivatePatient deactivates a patient within a transaction and cancels their appointments
+// still scheduled from now on, returning how many were canceled
+func deactivatePatient(tx *gorm.DB, patientID uuid.UUID) (int64, error) {
+	if err := tx.Model(&model.Patient{}).Where("id = ?", patientID).Update("active", false).Error; err != nil {
+		return 0, err
+	}
+
+	result := tx.Model(&model.Appointment{}).
+		Where("patient_id = ? AND status = ? AND start_time >= ?", patientID, model.AppointmentStatusScheduled, time.Now()).
+		Updates(map[string]interface{}{"status": model.AppointmentStatusCanceled, "updated_at": time.Now()})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import "gorm.io/gorm"
+
+// contactCondition matches rows whose email (case-insensitive) or phone digits equal the given values.
+// Empty values are ignored; with both empty the query matches nothing.
+func contactCondition(db *gorm.DB, email string, phoneDigits string) *gorm.DB {
+	condition := db.Where("1 = 0")
+	if email != "" {
+		condition = condition.Or("LOWER(email) = ?", email)
+	}
+	if phoneDigits != "" {
+		condition = condition.Or("regexp_replace(phone, '[^0-9]', '', 'g') = ?", phoneDigits)
+	}
+	return condition
+}
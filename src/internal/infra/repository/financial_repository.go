@@ -34,18 +34,27 @@ func (r *costCenterRepository) FindByID(id string) (*model.CostCenter, error) {
 	return &costCenter, nil
 }
 
-func (r *costCenterRepository) FindByUserID(userID string) ([]*model.CostCenter, error) {
-	var costCenters []*model.CostCenter
+// costCenterListSpec declares the sorts and filters accepted when listing cost centers
+var costCenterListSpec = listSpec{
+	sorts: map[string]string{
+		"name":       "name",
+		"created_at": "created_at",
+	},
+	defaultSort: "name",
+	dateColumn:  "created_at",
+	filters: map[string]listFilter{
+		"active":        boolFilter("active"),
+		"repasse_model": stringFilter("repasse_model"),
+	},
+}
+
+func (r *costCenterRepository) List(userID string, query port.ListQuery) (port.Page[model.CostCenter], error) {
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, err
+		return port.Page[model.CostCenter]{}, err
 	}
 
-	err = r.db.Where("user_id = ?", parsedUserID).Find(&costCenters).Error
-	if err != nil {
-		return nil, err
-	}
-	return costCenters, nil
+	return listPage[model.CostCenter](r.db.Where("user_id = ?", parsedUserID), costCenterListSpec, query)
 }
 
 func (r *costCenterRepository) Update(costCenter *model.CostCenter) error {
@@ -88,18 +97,31 @@ func (r *paymentRepository) FindByID(id string) (*model.Payment, error) {
 	return &payment, nil
 }
 
-func (r *paymentRepository) FindByUserID(userID string) ([]*model.Payment, error) {
-	var payments []*model.Payment
+// paymentListSpec declares the sorts and filters accepted when listing payments
+var paymentListSpec = listSpec{
+	sorts: map[string]string{
+		"payment_date": "payment_date",
+		"amount":       "amount",
+		"created_at":   "created_at",
+	},
+	defaultSort: "payment_date",
+	defaultDesc: true,
+	dateColumn:  "payment_date",
+	filters: map[string]listFilter{
+		"patient_id":     uuidFilter("patient_id"),
+		"payer_id":       uuidFilter("payer_id"),
+		"cost_center_id": uuidFilter("cost_center_id"),
+		"method":         stringFilter("method"),
+	},
+}
+
+func (r *paymentRepository) List(userID string, query port.ListQuery) (port.Page[model.Payment], error) {
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, err
+		return port.Page[model.Payment]{}, err
 	}
 
-	err = r.db.Where("user_id = ?", parsedUserID).Find(&payments).Error
-	if err != nil {
-		return nil, err
-	}
-	return payments, nil
+	return listPage[model.Payment](r.db.Where("user_id = ?", parsedUserID), paymentListSpec, query)
 }
 
 func (r *paymentRepository) FindByPatientID(patientID string) ([]*model.Payment, error) {
@@ -207,18 +229,29 @@ func (r *repasseRepository) FindByID(id string) (*model.Repasse, error) {
 	return &repasse, nil
 }
 
-func (r *repasseRepository) FindByUserID(userID string) ([]*model.Repasse, error) {
-	var repasses []*model.Repasse
+// repasseListSpec declares the sorts and filters accepted when listing repasses
+var repasseListSpec = listSpec{
+	sorts: map[string]string{
+		"created_at": "created_at",
+		"value":      "value",
+	},
+	defaultSort: "created_at",
+	defaultDesc: true,
+	dateColumn:  "created_at",
+	filters: map[string]listFilter{
+		"status":         stringFilter("status"),
+		"cost_center_id": uuidFilter("cost_center_id"),
+		"appointment_id": uuidFilter("appointment_id"),
+	},
+}
+
+func (r *repasseRepository) List(userID string, query port.ListQuery) (port.Page[model.Repasse], error) {
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, err
+		return port.Page[model.Repasse]{}, err
 	}
 
-	err = r.db.Where("user_id = ?", parsedUserID).Find(&repasses).Error
-	if err != nil {
-		return nil, err
-	}
-	return repasses, nil
+	return listPage[model.Repasse](r.db.Where("user_id = ?", parsedUserID), repasseListSpec, query)
 }
 
 func (r *repasseRepository) FindByAppointmentID(appointmentID string) (*model.Repasse, error) {
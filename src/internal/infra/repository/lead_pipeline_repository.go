@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// LeadStageRepository implementation
+type leadStageRepository struct {
+	db *gorm.DB
+}
+
+func NewLeadStageRepository(db *gorm.DB) port.LeadStageRepository {
+	return &leadStageRepository{db: db}
+}
+
+func (r *leadStageRepository) Create(stage *model.LeadStage) error {
+	return r.db.Create(stage).Error
+}
+
+func (r *leadStageRepository) FindByID(id uuid.UUID, userID uuid.UUID) (*model.LeadStage, error) {
+	var stage model.LeadStage
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&stage).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stage, nil
+}
+
+func (r *leadStageRepository) FindAll(userID uuid.UUID) ([]model.LeadStage, error) {
+	var stages []model.LeadStage
+	err := r.db.Where("user_id = ?", userID).Order("position ASC, created_at ASC").Find(&stages).Error
+	return stages, err
+}
+
+func (r *leadStageRepository) CreateDefaults(userID uuid.UUID) error {
+	return createDefaultLeadStages(r.db, userID)
+}
+
+func (r *leadStageRepository) NameTaken(userID uuid.UUID, name string, exceptID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.LeadStage{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, name, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *leadStageRepository) Update(stage *model.LeadStage) error {
+	return r.db.Save(stage).Error
+}
+
+func (r *leadStageRepository) Delete(id uuid.UUID, userID uuid.UUID) error {
+	return r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.LeadStage{}).Error
+}
+
+func (r *leadStageRepository) CountLeads(id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Lead{}).Where("stage_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *leadStageRepository) MoveLead(lead *model.Lead, transition *model.LeadStageTransition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(lead).Error; err != nil {
+			return err
+		}
+		return tx.Create(transition).Error
+	})
+}
+
+// LeadLostReasonRepository implementation
+type leadLostReasonRepository struct {
+	db *gorm.DB
+}
+
+func NewLeadLostReasonRepository(db *gorm.DB) port.LeadLostReasonRepository {
+	return &leadLostReasonRepository{db: db}
+}
+
+func (r *leadLostReasonRepository) Create(reason *model.LeadLostReason) error {
+	return r.db.Create(reason).Error
+}
+
+func (r *leadLostReasonRepository) FindByID(id uuid.UUID, userID uuid.UUID) (*model.LeadLostReason, error) {
+	var reason model.LeadLostReason
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&reason).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reason, nil
+}
+
+func (r *leadLostReasonRepository) FindAll(userID uuid.UUID) ([]model.LeadLostReason, error) {
+	var reasons []model.LeadLostReason
+	err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&reasons).Error
+	return reasons, err
+}
+
+func (r *leadLostReasonRepository) CreateDefaults(userID uuid.UUID) error {
+	return createDefaultLeadLostReasons(r.db, userID)
+}
+
+func (r *leadLostReasonRepository) NameTaken(userID uuid.UUID, name string, exceptID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.LeadLostReason{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, name, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *leadLostReasonRepository) Update(reason *model.LeadLostReason) error {
+	return r.db.Save(reason).Error
+}
+
+func (r *leadLostReasonRepository) Delete(id uuid.UUID, userID uuid.UUID) error {
+	return r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.LeadLostReason{}).Error
+}
+
+func (r *leadLostReasonRepository) CountLeads(id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Lead{}).Where("lost_reason_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// LeadActivityRepository implementation
+type leadActivityRepository struct {
+	db *gorm.DB
+}
+
+func NewLeadActivityRepository(db *gorm.DB) port.LeadActivityRepository {
+	return &leadActivityRepository{db: db}
+}
+
+func (r *leadActivityRepository) CreateWithLead(activity *model.LeadActivity, lead *model.Lead) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(activity).Error; err != nil {
+			return err
+		}
+		return tx.Save(lead).Error
+	})
+}
+
+var leadActivityListSpec = listSpec{
+	sorts:       map[string]string{"occurred_at": "occurred_at"},
+	defaultSort: "occurred_at",
+	defaultDesc: true,
+	dateColumn:  "occurred_at",
+	filters: map[string]listFilter{
+		"type":    stringFilter("type"),
+		"outcome": stringFilter("outcome"),
+	},
+}
+
+func (r *leadActivityRepository) List(leadID uuid.UUID, query port.ListQuery) (port.Page[model.LeadActivity], error) {
+	return listPage[model.LeadActivity](r.db.Where("lead_id = ?", leadID), leadActivityListSpec, query)
+}
+
+func (r *leadActivityRepository) FindLastByLeadIDs(leadIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	result := make(map[uuid.UUID]time.Time, len(leadIDs))
+	if len(leadIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		LeadID     uuid.UUID
+		OccurredAt time.Time
+	}
+	err := r.db.Model(&model.LeadActivity{}).
+		Select("lead_id, MAX(occurred_at) AS occurred_at").
+		Where("lead_id IN ?", leadIDs).
+		Group("lead_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.LeadID] = row.OccurredAt
+	}
+	return result, nil
+}
+
+// createDefaultLeadPipeline creates the default stages and lost reasons of a new user
+func createDefaultLeadPipeline(tx *gorm.DB, userID uuid.UUID) error {
+	if err := createDefaultLeadStages(tx, userID); err != nil {
+		return err
+	}
+	return createDefaultLeadLostReasons(tx, userID)
+}
+
+// createDefaultLeadStages creates the default stages of a user. Names the user already has are
+// skipped, so running it again never duplicates them.
+func createDefaultLeadStages(tx *gorm.DB, userID uuid.UUID) error {
+	now := time.Now()
+	stages := make([]model.LeadStage, len(model.DefaultLeadStages))
+	for i, def := range model.DefaultLeadStages {
+		stages[i] = model.LeadStage{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Name:      def.Name,
+			Position:  i,
+			Status:    def.Status,
+			IsActive:  true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&stages).Error
+}
+
+// createDefaultLeadLostReasons creates the default lost reasons of a user, skipping names it already has
+func createDefaultLeadLostReasons(tx *gorm.DB, userID uuid.UUID) error {
+	now := time.Now()
+	reasons := make([]model.LeadLostReason, len(model.DefaultLeadLostReasons))
+	for i, name := range model.DefaultLeadLostReasons {
+		reasons[i] = model.LeadLostReason{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Name:      name,
+			IsActive:  true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&reasons).Error
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type leadReportRepository struct {
+	db *gorm.DB
+}
+
+func NewLeadReportRepository(db *gorm.DB) port.LeadReportRepository {
+	return &leadReportRepository{db: db}
+}
+
+// daysBetweenContactAndConversion is the SQL expression for the days a lead took to convert
+const daysBetweenContactAndConversion = "EXTRACT(EPOCH FROM (l.converted_at - l.contact_date)) / 86400"
+
+// leads selects the leads of the filter, aliased as l
+func (r *leadReportRepository) leads(filter port.LeadFunnelFilter) *gorm.DB {
+	query := r.db.Table("leads AS l").Where("l.user_id = ?", filter.UserID)
+	if filter.StartDate != nil {
+		query = query.Where("l.contact_date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("l.contact_date < ?", *filter.EndDate)
+	}
+	if len(filter.Origins) > 0 {
+		query = query.Where("COALESCE(l.origin, '') IN ?", filter.Origins)
+	}
+	return query
+}
+
+// revenue selects the payments of the patients converted from the filtered leads.
+// Payments are counted regardless of their date, since they are the outcome of the lead.
+func (r *leadReportRepository) revenue(filter port.LeadFunnelFilter) *gorm.DB {
+	return r.leads(filter).
+		Joins("JOIN patients pt ON pt.lead_id = l.id").
+		Joins("JOIN payments pay ON pay.patient_id = pt.id")
+}
+
+func (r *leadReportRepository) FunnelByOrigin(filter port.LeadFunnelFilter) ([]port.LeadOriginFunnel, error) {
+	var rows []port.LeadOriginFunnel
+	err := r.leads(filter).
+		Select("COALESCE(l.origin, '') AS origin, COUNT(*) AS leads, "+
+			"COUNT(*) FILTER (WHERE l.status = ?) AS converted, "+
+			"COUNT(*) FILTER (WHERE l.status = ?) AS lost, "+
+			"AVG("+daysBetweenContactAndConversion+") FILTER (WHERE l.converted_at IS NOT NULL) AS avg_days_to_conversion",
+			model.LeadStatusConverted, model.LeadStatusLost).
+		Group("COALESCE(l.origin, '')").
+		Order("leads DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var revenues []struct {
+		Origin  string
+		Revenue int64
+	}
+	err = r.revenue(filter).
+		Select("COALESCE(l.origin, '') AS origin, SUM(pay.amount) AS revenue").
+		Group("COALESCE(l.origin, '')").
+		Scan(&revenues).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byOrigin := make(map[string]int64, len(revenues))
+	for _, revenue := range revenues {
+		byOrigin[revenue.Origin] = revenue.Revenue
+	}
+	for i := range rows {
+		rows[i].Revenue = byOrigin[rows[i].Origin]
+	}
+	return rows, nil
+}
+
+func (r *leadReportRepository) ConversionTime(filter port.LeadFunnelFilter) (*port.LeadConversionTime, error) {
+	var result port.LeadConversionTime
+	err := r.leads(filter).
+		Where("l.converted_at IS NOT NULL").
+		Select("COUNT(*) AS conversions, " +
+			"AVG(" + daysBetweenContactAndConversion + ") AS avg_days, " +
+			"percentile_cont(0.5) WITHIN GROUP (ORDER BY " + daysBetweenContactAndConversion + ") AS median_days, " +
+			"MIN(" + daysBetweenContactAndConversion + ") AS min_days, " +
+			"MAX(" + daysBetweenContactAndConversion + ") AS max_days").
+		Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *leadReportRepository) StageFunnel(filter port.LeadFunnelFilter) ([]port.LeadStageFunnel, error) {
+	var stages []model.LeadStage
+	if err := r.db.Where("user_id = ?", filter.UserID).Order("position ASC, created_at ASC").Find(&stages).Error; err != nil {
+		return nil, err
+	}
+
+	type stageCount struct {
+		StageID uuid.UUID
+		Count   int64
+	}
+
+	// A lead reached a stage if it is there now or ever moved into it
+	var reached []stageCount
+	err := r.leads(filter).
+		Joins("JOIN (SELECT lead_id, to_stage_id AS stage_id FROM lead_stage_transitions " +
+			"UNION SELECT id, stage_id FROM leads WHERE stage_id IS NOT NULL) visits ON visits.lead_id = l.id").
+		Select("visits.stage_id AS stage_id, COUNT(DISTINCT l.id) AS count").
+		Group("visits.stage_id").
+		Scan(&reached).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var current []stageCount
+	err = r.leads(filter).
+		Where("l.stage_id IS NOT NULL").
+		Select("l.stage_id AS stage_id, COUNT(*) AS count").
+		Group("l.stage_id").
+		Scan(&current).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var lostFrom []stageCount
+	err = r.leads(filter).
+		Joins("JOIN lead_stage_transitions t ON t.lead_id = l.id").
+		Joins("JOIN lead_stages target ON target.id = t.to_stage_id").
+		Where("target.status = ? AND t.from_stage_id IS NOT NULL", model.LeadStatusLost).
+		Select("t.from_stage_id AS stage_id, COUNT(DISTINCT l.id) AS count").
+		Group("t.from_stage_id").
+		Scan(&lostFrom).Error
+	if err != nil {
+		return nil, err
+	}
+
+	index := func(counts []stageCount) map[uuid.UUID]int64 {
+		m := make(map[uuid.UUID]int64, len(counts))
+		for _, c := range counts {
+			m[c.StageID] = c.Count
+		}
+		return m
+	}
+	reachedByStage, currentByStage, lostByStage := index(reached), index(current), index(lostFrom)
+
+	result := make([]port.LeadStageFunnel, len(stages))
+	for i, stage := range stages {
+		result[i] = port.LeadStageFunnel{
+			StageID:  stage.ID,
+			Name:     stage.Name,
+			Position: stage.Position,
+			Status:   stage.Status,
+			Reached:  reachedByStage[stage.ID],
+			Current:  currentByStage[stage.ID],
+			LostFrom: lostByStage[stage.ID],
+		}
+	}
+	return result, nil
+}
+
+func (r *leadReportRepository) MonthlyCohorts(filter port.LeadFunnelFilter) ([]port.LeadMonthlyCohort, error) {
+	const month = "to_char(date_trunc('month', l.contact_date), 'YYYY-MM')"
+
+	var rows []port.LeadMonthlyCohort
+	err := r.leads(filter).
+		Select(month+" AS month, COUNT(*) AS leads, "+
+			"COUNT(*) FILTER (WHERE l.status = ?) AS converted, "+
+			"COUNT(*) FILTER (WHERE l.status = ?) AS lost",
+			model.LeadStatusConverted, model.LeadStatusLost).
+		Group(month).
+		Order("month ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var revenues []struct {
+		Month   string
+		Revenue int64
+	}
+	err = r.revenue(filter).
+		Select(month + " AS month, SUM(pay.amount) AS revenue").
+		Group(month).
+		Scan(&revenues).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byMonth := make(map[string]int64, len(revenues))
+	for _, revenue := range revenues {
+		byMonth[revenue.Month] = revenue.Revenue
+	}
+	for i := range rows {
+		rows[i].Revenue = byMonth[rows[i].Month]
+	}
+	return rows, nil
+}
@@ -1,10 +1,12 @@
 ﻿package repository
 
 import (
+	"errors"
 	"github.com/LacirJR/psygrow-api/src/internal/core/model"
 	"github.com/LacirJR/psygrow-api/src/internal/core/port"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -29,16 +31,6 @@ func (r *leadRepository) FindByID(id uuid.UUID, userID uuid.UUID) (*model.Lead,
 	return &lead, nil
 }
 
-func (r *leadRepository) FindAll(userID uuid.UUID, limit, offset int) ([]model.Lead, error) {
-	var leads []model.Lead
-	err := r.db.Where("user_id = ?", userID).
-		Limit(limit).
-		Offset(offset).
-		Order("created_at DESC").
-		Find(&leads).Error
-	return leads, err
-}
-
 func (r *leadRepository) Update(lead *model.Lead) error {
 	return r.db.Save(lead).Error
 }
@@ -47,104 +39,144 @@ func (r *leadRepository) Delete(id uuid.UUID, userID uuid.UUID) error {
 	return r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Lead{}).Error
 }
 
-func (r *leadRepository) ConvertToPatient(leadID uuid.UUID, userID uuid.UUID, costCenterID uuid.UUID) (*model.Patient, error) {
-	// Start a transaction
-	tx := r.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+func (r *leadRepository) ConvertToPatient(leadID uuid.UUID, userID uuid.UUID, patient *model.Patient, appointment *model.Appointment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the lead so concurrent requests cannot convert it twice
+		var lead model.Lead
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", leadID, userID).
+			First(&lead).Error; err != nil {
+			return err
 		}
-	}()
 
-	// Find the lead
-	var lead model.Lead
-	if err := tx.Where("id = ? AND user_id = ?", leadID, userID).First(&lead).Error; err != nil {
-		tx.Rollback()
-		return nil, err
-	}
+		if lead.Status == model.LeadStatusConverted || lead.ConvertedAt != nil {
+			return port.ErrLeadAlreadyConverted
+		}
+		if lead.GdprBlockContact {
+			return port.ErrLeadContactBlocked
+		}
 
-	// Create a new patient from the lead
-	patient := model.Patient{
-		ID:           uuid.New(),
-		UserID:       userID,
-		CostCenterID: costCenterID,
-		FullName:     lead.FullName,
-		BirthDate:    time.Now(), // Default value, should be updated if lead.BirthDate is not nil
-		Phone:        lead.Phone,
-		Email:        lead.Email,
-		IsActive:     true,
-	}
+		patient.LeadID = &lead.ID
+		if err := tx.Create(patient).Error; err != nil {
+			return err
+		}
 
-	// Set birth date if available
-	if lead.BirthDate != nil {
-		patient.BirthDate = *lead.BirthDate
-	}
+		// The converted patient starts their first episode of care
+		episode := model.CareEpisode{ID: uuid.New(), UserID: userID, PatientID: patient.ID, StartDate: time.Now()}
+		if appointment != nil {
+			episode.StartDate = appointment.StartTime
+		}
+		if err := openCareEpisode(tx, &episode); err != nil {
+			return err
+		}
 
-	// Create the patient
-	if err := tx.Create(&patient).Error; err != nil {
-		tx.Rollback()
-		return nil, err
-	}
+		if appointment != nil {
+			appointment.PatientID = patient.ID
+			appointment.EpisodeID = &episode.ID
+			if err := tx.Create(appointment).Error; err != nil {
+				return err
+			}
+		}
 
-	// Update the lead status to converted
-	now := time.Now()
-	lead.Status = model.LeadStatusConverted
-	lead.ConvertedAt = &now
-	if err := tx.Save(&lead).Error; err != nil {
-		tx.Rollback()
-		return nil, err
-	}
+		now := time.Now()
+
+		// Move the lead to the first converted stage of the pipeline, if the user has one
+		var stage model.LeadStage
+		err := tx.Where("user_id = ? AND status = ? AND is_active = ?", userID, model.LeadStatusConverted, true).
+			Order("position ASC").
+			First(&stage).Error
+		if err == nil {
+			transition := model.LeadStageTransition{
+				ID:          uuid.New(),
+				LeadID:      lead.ID,
+				FromStageID: lead.StageID,
+				ToStageID:   stage.ID,
+				UserID:      userID,
+				ChangedAt:   now,
+			}
+			if err := tx.Create(&transition).Error; err != nil {
+				return err
+			}
+			lead.StageID = &stage.ID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		lead.Status = model.LeadStatusConverted
+		lead.ConvertedAt = &now
+		lead.LostReasonID = nil
+		lead.NextFollowUpAt = nil
+		lead.UpdatedAt = now
+		return tx.Save(&lead).Error
+	})
+}
 
-	// Commit the transaction
-	if err := tx.Commit().Error; err != nil {
+func (r *leadRepository) FindByContact(userID uuid.UUID, email string, phoneDigits string) (*model.Lead, error) {
+	var lead model.Lead
+	err := r.db.Where("user_id = ?", userID).
+		Where(contactCondition(r.db, email, phoneDigits)).
+		Order("created_at DESC").
+		First(&lead).Error
+	if err != nil {
 		return nil, err
 	}
+	return &lead, nil
+}
 
-	return &patient, nil
+func (r *leadRepository) CreateWithConsent(lead *model.Lead, consent *model.LeadConsent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(lead).Error; err != nil {
+			return err
+		}
+		consent.LeadID = lead.ID
+		return tx.Create(consent).Error
+	})
 }
 
-func (r *leadRepository) FindByStatus(userID uuid.UUID, status string, limit, offset int) ([]model.Lead, error) {
-	var leads []model.Lead
-	err := r.db.Where("user_id = ? AND status = ?", userID, status).
-		Limit(limit).
-		Offset(offset).
-		Order("created_at DESC").
-		Find(&leads).Error
-	return leads, err
+// dueFollowUps selects open leads that may be contacted and have a follow-up due until the given time
+func (r *leadRepository) dueFollowUps(userID uuid.UUID, until time.Time) *gorm.DB {
+	return r.db.Model(&model.Lead{}).
+		Where("user_id = ?", userID).
+		Where("next_follow_up_at IS NOT NULL AND next_follow_up_at <= ?", until).
+		Where("status NOT IN ?", []string{model.LeadStatusConverted, model.LeadStatusLost}).
+		Where("gdpr_block_contact = ?", false)
 }
 
-func (r *leadRepository) FindByOrigin(userID uuid.UUID, origin string, limit, offset int) ([]model.Lead, error) {
-	var leads []model.Lead
-	err := r.db.Where("user_id = ? AND origin = ?", userID, origin).
-		Limit(limit).
-		Offset(offset).
-		Order("created_at DESC").
-		Find(&leads).Error
-	return leads, err
+// dueFollowUpListSpec declares the sorts and filters accepted when listing due follow-ups
+var dueFollowUpListSpec = listSpec{
+	sorts: map[string]string{
+		"next_follow_up_at": "next_follow_up_at", // Never NULL among due follow-ups
+		"full_name":         "full_name",
+	},
+	defaultSort: "next_follow_up_at",
+	dateColumn:  "next_follow_up_at",
+	filters: map[string]listFilter{
+		"stage_id": uuidFilter("stage_id"),
+	},
 }
 
-func (r *leadRepository) FindByContactDate(userID uuid.UUID, startDate, endDate time.Time, limit, offset int) ([]model.Lead, error) {
-	var leads []model.Lead
-	err := r.db.Where("user_id = ? AND contact_date BETWEEN ? AND ?", userID, startDate, endDate).
-		Limit(limit).
-		Offset(offset).
-		Order("contact_date DESC").
-		Find(&leads).Error
-	return leads, err
+func (r *leadRepository) ListDueFollowUps(userID uuid.UUID, until time.Time, query port.ListQuery) (port.Page[model.Lead], error) {
+	return listPage[model.Lead](r.dueFollowUps(userID, until), dueFollowUpListSpec, query)
 }
 
-func (r *leadRepository) FindByWasAttended(userID uuid.UUID, wasAttended bool, limit, offset int) ([]model.Lead, error) {
-	var leads []model.Lead
-	err := r.db.Where("user_id = ? AND was_attended = ?", userID, wasAttended).
-		Limit(limit).
-		Offset(offset).
-		Order("created_at DESC").
-		Find(&leads).Error
-	return leads, err
+// leadListSpec declares the sorts and filters accepted when listing leads
+var leadListSpec = listSpec{
+	sorts: map[string]string{
+		"contact_date": "contact_date",
+		"created_at":   "created_at",
+		"full_name":    "full_name",
+	},
+	defaultSort: "contact_date",
+	defaultDesc: true,
+	dateColumn:  "contact_date",
+	filters: map[string]listFilter{
+		"status":       stringFilter("status"),
+		"origin":       stringFilter("origin"),
+		"was_attended": boolFilter("was_attended"),
+		"stage_id":     uuidFilter("stage_id"),
+	},
 }
 
-func (r *leadRepository) Count(userID uuid.UUID) (int64, error) {
-	var count int64
-	err := r.db.Model(&model.Lead{}).Where("user_id = ?", userID).Count(&count).Error
-	return count, err
-}
\ No newline at end of file
+func (r *leadRepository) List(userID uuid.UUID, query port.ListQuery) (port.Page[model.Lead], error) {
+	return listPage[model.Lead](r.db.Where("user_id = ?", userID), leadListSpec, query)
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// listFilter maps a filter name of a ListQuery to a column, converting the raw value
+type listFilter struct {
+	column string
+	parse  func(raw string) (interface{}, error)
+}
+
+// listSpec declares what a list method accepts. Sort columns must not be nullable unless listed in nullable.
+type listSpec struct {
+	sorts       map[string]string // Sort field name to column
+	nullable    map[string]bool   // Sort fields whose column may be NULL; NULLs come last in both orders
+	defaultSort string
+	defaultDesc bool
+	dateColumn  string // Column the From/To range applies to
+	filters     map[string]listFilter
+	preloads    []string // Associations loaded with the rows of the page
+}
+
+func stringFilter(column string) listFilter {
+	return listFilter{column: column, parse: func(raw string) (interface{}, error) { return raw, nil }}
+}
+
+func uuidFilter(column string) listFilter {
+	return listFilter{column: column, parse: func(raw string) (interface{}, error) { return uuid.Parse(raw) }}
+}
+
+func boolFilter(column string) listFilter {
+	return listFilter{column: column, parse: func(raw string) (interface{}, error) { return strconv.ParseBool(raw) }}
+}
+
+// listPage applies the filters, sort and cursor of the query to a base query already scoped
+// to the owner and returns the page. Pagination is keyset based on (sort column, id), so
+// pages stay stable while rows are inserted.
+func listPage[T any](base *gorm.DB, spec listSpec, q port.ListQuery) (port.Page[T], error) {
+	var page port.Page[T]
+
+	sortBy := q.SortBy
+	if sortBy == "" {
+		sortBy = spec.defaultSort
+	}
+	column, ok := spec.sorts[sortBy]
+	if !ok {
+		return page, fmt.Errorf("%w: %s", port.ErrInvalidSort, sortBy)
+	}
+	desc := spec.defaultDesc
+	switch q.SortOrder {
+	case port.SortAsc:
+		desc = false
+	case port.SortDesc:
+		desc = true
+	}
+
+	query := base.Model(new(T))
+	if q.From != nil {
+		query = query.Where(spec.dateColumn+" >= ?", *q.From)
+	}
+	if q.To != nil {
+		query = query.Where(spec.dateColumn+" < ?", *q.To)
+	}
+	for name, raw := range q.Filters {
+		filter, ok := spec.filters[name]
+		if !ok || raw == "" {
+			continue
+		}
+		value, err := filter.parse(raw)
+		if err != nil {
+			return page, fmt.Errorf("%w: %s", port.ErrInvalidFilter, name)
+		}
+		query = query.Where(filter.column+" = ?", value)
+	}
+	query = query.Session(&gorm.Session{})
+
+	if err := query.Count(&page.Total).Error; err != nil {
+		return page, err
+	}
+
+	stmt := &gorm.Statement{DB: base}
+	if err := stmt.Parse(new(T)); err != nil {
+		return page, err
+	}
+	sortField := stmt.Schema.LookUpField(column)
+	idField := stmt.Schema.PrioritizedPrimaryField
+	if sortField == nil || idField == nil {
+		return page, fmt.Errorf("%w: %s", port.ErrInvalidSort, sortBy)
+	}
+
+	direction, comparison := "ASC", ">"
+	if desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	nullable := spec.nullable[sortBy]
+	order := column + " " + direction
+	if nullable {
+		order += " NULLS LAST"
+	}
+
+	rows := query
+	for _, association := range spec.preloads {
+		rows = rows.Preload(association)
+	}
+	if q.Cursor != "" {
+		value, id, err := decodeCursor(q.Cursor, sortField)
+		if err != nil {
+			return page, err
+		}
+		switch {
+		case value != nil:
+			keyset := fmt.Sprintf("(%s, %s) %s (?, ?)", column, idField.DBName, comparison)
+			if nullable {
+				keyset = fmt.Sprintf("(%s IS NULL OR %s)", column, keyset)
+			}
+			rows = rows.Where(keyset, value, id)
+		case nullable:
+			// The previous page ended among the NULLs, which are ordered by ID alone
+			rows = rows.Where(fmt.Sprintf("%s IS NULL AND %s %s ?", column, idField.DBName, comparison), id)
+		default:
+			return page, port.ErrInvalidCursor
+		}
+	}
+
+	var data []T
+	err := rows.Order(order).
+		Order(idField.DBName + " " + direction).
+		Limit(q.Limit + 1).
+		Find(&data).Error
+	if err != nil {
+		return page, err
+	}
+
+	if len(data) > q.Limit {
+		data = data[:q.Limit]
+		last := reflect.ValueOf(&data[len(data)-1])
+		page.NextCursor, err = encodeCursor(sortField, idField, last)
+		if err != nil {
+			return page, err
+		}
+	}
+	page.Data = data
+	return page, nil
+}
+
+// encodeCursor writes the sort value, null for a NULL column, and the ID of the last row of a page
+func encodeCursor(sortField, idField *schema.Field, row reflect.Value) (string, error) {
+	value, zero := sortField.ValueOf(context.Background(), row)
+	id, _ := idField.ValueOf(context.Background(), row)
+
+	var encoded *string
+	if !zero || sortField.FieldType.Kind() != reflect.Ptr {
+		var text string
+		switch v := value.(type) {
+		case time.Time:
+			text = v.Format(time.RFC3339Nano)
+		case *time.Time:
+			text = v.Format(time.RFC3339Nano)
+		default:
+			text = fmt.Sprint(reflect.Indirect(reflect.ValueOf(v)))
+		}
+		encoded = &text
+	}
+
+	idText := fmt.Sprint(id)
+	raw, err := json.Marshal([]*string{encoded, &idText})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// decodeCursor reads a cursor back, converting the sort value to the type of its column.
+// The value is nil when the last row of the previous page had a NULL sort column.
+func decodeCursor(cursor string, sortField *schema.Field) (interface{}, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, uuid.Nil, port.ErrInvalidCursor
+	}
+	var parts []*string
+	if err := json.Unmarshal(raw, &parts); err != nil || len(parts) != 2 || parts[1] == nil {
+		return nil, uuid.Nil, port.ErrInvalidCursor
+	}
+	id, err := uuid.Parse(*parts[1])
+	if err != nil {
+		return nil, uuid.Nil, port.ErrInvalidCursor
+	}
+	if parts[0] == nil {
+		return nil, id, nil
+	}
+
+	var value interface{}
+	text := *parts[0]
+	fieldType := sortField.IndirectFieldType
+	switch {
+	case fieldType == reflect.TypeOf(time.Time{}):
+		value, err = time.Parse(time.RFC3339Nano, text)
+	case fieldType == reflect.TypeOf(uuid.UUID{}):
+		value, err = uuid.Parse(text)
+	case fieldType.Kind() >= reflect.Int && fieldType.Kind() <= reflect.Int64:
+		value, err = strconv.ParseInt(text, 10, 64)
+	case fieldType.Kind() == reflect.Bool:
+		value, err = strconv.ParseBool(text)
+	default:
+		value = text
+	}
+	if err != nil {
+		return nil, uuid.Nil, port.ErrInvalidCursor
+	}
+	return value, id, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sync"
+	"time"
+)
+
+// LoginAttemptRepository implementation
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) port.LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Save(attempt *model.LoginAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+var loginAttemptListSpec = listSpec{
+	sorts:       map[string]string{"created_at": "created_at"},
+	defaultSort: "created_at",
+	defaultDesc: true,
+	dateColumn:  "created_at",
+	filters: map[string]listFilter{
+		"reason": stringFilter("reason"),
+	},
+}
+
+func (r *loginAttemptRepository) List(userID uuid.UUID, query port.ListQuery) (port.Page[model.LoginAttempt], error) {
+	return listPage[model.LoginAttempt](r.db.Where("user_id = ?", userID), loginAttemptListSpec, query)
+}
+
+// LoginThrottleStore in-memory implementation.
+// State is lost on restart and not shared between instances; entries idle for
+// longer than the retention period are pruned as the store is used.
+type memoryLoginThrottleStore struct {
+	mu        sync.Mutex
+	states    map[string]model.LoginThrottle
+	retention time.Duration
+	lastPrune time.Time
+}
+
+func NewMemoryLoginThrottleStore(retention time.Duration) port.LoginThrottleStore {
+	return &memoryLoginThrottleStore{
+		states:    make(map[string]model.LoginThrottle),
+		retention: retention,
+		lastPrune: time.Now(),
+	}
+}
+
+func (s *memoryLoginThrottleStore) Get(key string) (*model.LoginThrottle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[key]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+func (s *memoryLoginThrottleStore) Update(key string, fn func(state *model.LoginThrottle)) (*model.LoginThrottle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+
+	state, ok := s.states[key]
+	if !ok {
+		state = model.LoginThrottle{Key: key}
+	}
+	fn(&state)
+	s.states[key] = state
+
+	return &state, nil
+}
+
+func (s *memoryLoginThrottleStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, key)
+	return nil
+}
+
+// prune drops idle entries, at most once per retention period. Must be called with the lock held.
+func (s *memoryLoginThrottleStore) prune() {
+	now := time.Now()
+	if now.Sub(s.lastPrune) < s.retention {
+		return
+	}
+
+	for key, state := range s.states {
+		if now.Sub(state.LastFailureAt) > s.retention && (state.BlockedUntil == nil || now.After(*state.BlockedUntil)) {
+			delete(s.states, key)
+		}
+	}
+	s.lastPrune = now
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type patientAnamneseLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewPatientAnamneseLinkRepository(db *gorm.DB) port.PatientAnamneseLinkRepository {
+	return &patientAnamneseLinkRepository{db: db}
+}
+
+func (r *patientAnamneseLinkRepository) Save(link *model.PatientAnamneseLink) error {
+	return r.db.Create(link).Error
+}
+
+func (r *patientAnamneseLinkRepository) FindByID(id uuid.UUID, userID uuid.UUID) (*model.PatientAnamneseLink, error) {
+	var link model.PatientAnamneseLink
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *patientAnamneseLinkRepository) FindByPatient(patientID uuid.UUID, userID uuid.UUID) ([]model.PatientAnamneseLink, error) {
+	var links []model.PatientAnamneseLink
+	err := r.db.Where("patient_id = ? AND user_id = ?", patientID, userID).
+		Order("created_at DESC").
+		Find(&links).Error
+	return links, err
+}
+
+func (r *patientAnamneseLinkRepository) FindByTokenHash(tokenHash string) (*model.PatientAnamneseLink, error) {
+	var link model.PatientAnamneseLink
+	err := r.db.Where("token_hash = ?", tokenHash).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *patientAnamneseLinkRepository) Consume(link *model.PatientAnamneseLink, patientAnamnese *model.PatientAnamnese, fields []*model.PatientAnamneseField) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.PatientAnamneseLink{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > now()", link.ID).
+			Updates(map[string]interface{}{
+				"used_at":             link.UsedAt,
+				"patient_anamnese_id": patientAnamnese.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Create(patientAnamnese).Error; err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			return nil
+		}
+		return tx.Create(&fields).Error
+	})
+}
+
+func (r *patientAnamneseLinkRepository) Revoke(link *model.PatientAnamneseLink) error {
+	return r.db.Model(&model.PatientAnamneseLink{}).Where("id = ?", link.ID).Update("revoked_at", link.RevokedAt).Error
+}
+
+func (r *patientAnamneseLinkRepository) LogAccess(access *model.PatientAnamneseLinkAccess) error {
+	return r.db.Create(access).Error
+}
+
+func (r *patientAnamneseLinkRepository) FindAccesses(linkID uuid.UUID) ([]model.PatientAnamneseLinkAccess, error) {
+	var accesses []model.PatientAnamneseLinkAccess
+	err := r.db.Where("link_id = ?", linkID).Order("created_at DESC").Find(&accesses).Error
+	return accesses, err
+}
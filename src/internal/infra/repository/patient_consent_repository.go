@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type patientConsentRepository struct {
+	db *gorm.DB
+}
+
+func NewPatientConsentRepository(db *gorm.DB) port.PatientConsentRepository {
+	return &patientConsentRepository{db: db}
+}
+
+func (r *patientConsentRepository) Create(consent *model.PatientConsent) error {
+	return r.db.Create(consent).Error
+}
+
+func (r *patientConsentRepository) FindByID(id uuid.UUID, patientID uuid.UUID) (*model.PatientConsent, error) {
+	var consent model.PatientConsent
+	err := r.db.Where("id = ? AND patient_id = ?", id, patientID).First(&consent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+func (r *patientConsentRepository) FindByPatient(patientID uuid.UUID) ([]model.PatientConsent, error) {
+	var consents []model.PatientConsent
+	err := r.db.Where("patient_id = ?", patientID).
+		Order("signed_at DESC").
+		Find(&consents).Error
+	return consents, err
+}
+
+func (r *patientConsentRepository) Update(consent *model.PatientConsent) error {
+	return r.db.Save(consent).Error
+}
+
+func (r *patientConsentRepository) FindValidByGuardian(patientID uuid.UUID, consentType string, at time.Time) (*model.PatientConsent, error) {
+	var consent model.PatientConsent
+	err := r.db.Joins("JOIN patient_families ON patient_families.id = patient_consents.guardian_id").
+		Where("patient_consents.patient_id = ? AND patient_consents.type = ?", patientID, consentType).
+		Where("patient_families.patient_id = patient_consents.patient_id AND patient_families.is_legal_responsible").
+		Where("patient_consents.signed_at <= ?", at).
+		Where("patient_consents.revoked_at IS NULL OR patient_consents.revoked_at > ?", at).
+		Where("patient_consents.valid_until IS NULL OR patient_consents.valid_until > ?", at).
+		Order("patient_consents.signed_at DESC").
+		First(&consent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type patientFamilyLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewPatientFamilyLinkRepository(db *gorm.DB) port.PatientFamilyLinkRepository {
+	return &patientFamilyLinkRepository{db: db}
+}
+
+func (r *patientFamilyLinkRepository) Create(link *model.PatientFamilyLink) error {
+	return r.db.Create(link).Error
+}
+
+func (r *patientFamilyLinkRepository) FindByID(id uuid.UUID, patientID uuid.UUID) (*model.PatientFamilyLink, error) {
+	var link model.PatientFamilyLink
+	err := r.db.Where("id = ? AND patient_id = ?", id, patientID).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *patientFamilyLinkRepository) FindByPatient(patientID uuid.UUID) ([]model.PatientFamilyLink, error) {
+	var links []model.PatientFamilyLink
+	err := r.db.Where("patient_id = ?", patientID).
+		Order("created_at ASC").
+		Find(&links).Error
+	return links, err
+}
+
+func (r *patientFamilyLinkRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&model.PatientFamilyLink{}).Error
+}
@@ -41,7 +41,13 @@ func (r *patientFamilyRepository) Update(patientFamily *model.PatientFamily) err
 }
 
 func (r *patientFamilyRepository) Delete(id uuid.UUID) error {
-	return r.db.Where("id = ?", id).Delete(&model.PatientFamily{}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Links of the family tree cannot outlive the member
+		if err := tx.Where("from_id = ? OR to_id = ?", id, id).Delete(&model.PatientFamilyLink{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.PatientFamily{}).Error
+	})
 }
 
 func (r *patientFamilyRepository) FindByRelationship(patientID uuid.UUID, relationship string) ([]model.PatientFamily, error) {
@@ -50,4 +56,12 @@ func (r *patientFamilyRepository) FindByRelationship(patientID uuid.UUID, relati
 		Order("name ASC").
 		Find(&patientFamilies).Error
 	return patientFamilies, err
-}
\ No newline at end of file
+}
+
+func (r *patientFamilyRepository) FindFinancialResponsibles(patientID uuid.UUID) ([]model.PatientFamily, error) {
+	var patientFamilies []model.PatientFamily
+	err := r.db.Where("patient_id = ? AND is_financial_responsible", patientID).
+		Order("name ASC").
+		Find(&patientFamilies).Error
+	return patientFamilies, err
+}
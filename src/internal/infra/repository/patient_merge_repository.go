@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"encoding/json"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"time"
+)
+
+// patientReference is a column pointing to a patient that must follow the survivor on a merge
+type patientReference struct {
+	name   string
+	model  interface{}
+	column string
+}
+
+// patientReferences lists every column holding a patient ID
+var patientReferences = []patientReference{
+	{"appointments", &model.Appointment{}, "patient_id"},
+	{"sessions", &model.Session{}, "patient_id"},
+	{"evolutions", &model.Evolution{}, "patient_id"},
+	{"payments", &model.Payment{}, "patient_id"},
+	{"patient_anamneses", &model.PatientAnamnese{}, "patient_id"},
+	{"patient_anamnese_links", &model.PatientAnamneseLink{}, "patient_id"},
+	{"scale_applications", &model.ScaleApplication{}, "patient_id"},
+	{"treatment_plans", &model.TreatmentPlan{}, "patient_id"},
+	{"care_episodes", &model.CareEpisode{}, "patient_id"},
+	{"patient_families", &model.PatientFamily{}, "patient_id"},
+	{"patient_consents", &model.PatientConsent{}, "patient_id"},
+	{"patient_family_links", &model.PatientFamilyLink{}, "patient_id"},
+	{"patient_family_link_ends", &model.PatientFamilyLink{}, "from_id"},
+	{"patient_family_link_ends", &model.PatientFamilyLink{}, "to_id"},
+	{"leads", &model.Lead{}, "duplicate_of_patient_id"},
+}
+
+type patientMergeRepository struct {
+	db *gorm.DB
+}
+
+func NewPatientMergeRepository(db *gorm.DB) port.PatientMergeRepository {
+	return &patientMergeRepository{db: db}
+}
+
+func (r *patientMergeRepository) Merge(userID uuid.UUID, survivorID uuid.UUID, duplicateID uuid.UUID) (*model.PatientMerge, error) {
+	var merge *model.PatientMerge
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock both patients so they cannot be changed or merged concurrently
+		var patients []model.Patient
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND user_id = ?", []uuid.UUID{survivorID, duplicateID}, userID).
+			Find(&patients).Error; err != nil {
+			return err
+		}
+		if len(patients) != 2 {
+			return gorm.ErrRecordNotFound
+		}
+
+		survivor, duplicate := patients[0], patients[1]
+		if survivor.ID != survivorID {
+			survivor, duplicate = duplicate, survivor
+		}
+
+		snapshot, err := json.Marshal(duplicate)
+		if err != nil {
+			return err
+		}
+
+		// A patient has at most one active treatment plan: the survivor's goes on
+		if err := tx.Model(&model.TreatmentPlan{}).
+			Where("patient_id = ? AND status = ?", duplicate.ID, model.TreatmentPlanStatusActive).
+			Where("EXISTS (SELECT 1 FROM treatment_plans s WHERE s.patient_id = ? AND s.status = ?)", survivor.ID, model.TreatmentPlanStatusActive).
+			Updates(map[string]interface{}{
+				"status":   model.TreatmentPlanStatusDiscontinued,
+				"ended_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := mergeCareEpisodes(tx, survivor.ID, duplicate.ID); err != nil {
+			return err
+		}
+
+		moved := make(map[string]int64, len(patientReferences))
+		for _, ref := range patientReferences {
+			result := tx.Model(ref.model).
+				Where(ref.column+" = ?", duplicate.ID).
+				Update(ref.column, survivor.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+			moved[ref.name] += result.RowsAffected
+		}
+
+		movedJSON, err := json.Marshal(moved)
+		if err != nil {
+			return err
+		}
+
+		filled := survivor.FillMissingFrom(duplicate)
+		if duplicate.IsActive {
+			survivor.IsActive = true
+		}
+		survivor.UpdatedAt = time.Now()
+		if err := tx.Omit("CostCenter").Save(&survivor).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&model.Patient{}, "id = ?", duplicate.ID).Error; err != nil {
+			return err
+		}
+
+		merge = &model.PatientMerge{
+			ID:              uuid.New(),
+			UserID:          userID,
+			SurvivorID:      survivor.ID,
+			MergedPatientID: duplicate.ID,
+			MergedSnapshot:  string(snapshot),
+			MovedRecords:    string(movedJSON),
+		}
+		if len(filled) > 0 {
+			fields := strings.Join(filled, ",")
+			merge.FilledFields = &fields
+		}
+		return tx.Create(merge).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return merge, nil
+}
+
+// mergeCareEpisodes prepares the episodes of the duplicate to follow the survivor within a
+// transaction. When both have an open episode, the duplicate's is folded into the survivor's,
+// which then starts at the earliest of both. The other episodes of the duplicate are numbered
+// after the survivor's.
+func mergeCareEpisodes(tx *gorm.DB, survivorID uuid.UUID, duplicateID uuid.UUID) error {
+	var open []model.CareEpisode
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("patient_id IN ? AND end_date IS NULL", []uuid.UUID{survivorID, duplicateID}).
+		Find(&open).Error; err != nil {
+		return err
+	}
+	if len(open) == 2 {
+		survivorOpen, duplicateOpen := open[0], open[1]
+		if survivorOpen.PatientID != survivorID {
+			survivorOpen, duplicateOpen = duplicateOpen, survivorOpen
+		}
+
+		for _, attached := range []interface{}{&model.Appointment{}, &model.Session{}} {
+			if err := tx.Model(attached).
+				Where("episode_id = ?", duplicateOpen.ID).
+				Update("episode_id", survivorOpen.ID).Error; err != nil {
+				return err
+			}
+		}
+		if duplicateOpen.StartDate.Before(survivorOpen.StartDate) {
+			if err := tx.Model(&survivorOpen).Update("start_date", duplicateOpen.StartDate).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&duplicateOpen).Error; err != nil {
+			return err
+		}
+	}
+
+	var last int
+	if err := tx.Model(&model.CareEpisode{}).
+		Where("patient_id = ?", survivorID).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&last).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`UPDATE care_episodes e SET number = ? + r.position
+		FROM (SELECT id, row_number() OVER (ORDER BY number, start_date) AS position FROM care_episodes WHERE patient_id = ?) r
+		WHERE e.id = r.id`, last, duplicateID).Error
+}
+
+var patientMergeListSpec = listSpec{
+	sorts:       map[string]string{"merged_at": "merged_at"},
+	defaultSort: "merged_at",
+	defaultDesc: true,
+	dateColumn:  "merged_at",
+	filters: map[string]listFilter{
+		"survivor_id": uuidFilter("survivor_id"),
+	},
+}
+
+func (r *patientMergeRepository) List(userID uuid.UUID, query port.ListQuery) (port.Page[model.PatientMerge], error) {
+	return listPage[model.PatientMerge](r.db.Where("user_id = ?", userID), patientMergeListSpec, query)
+}
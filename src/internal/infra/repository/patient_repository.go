@@ -5,6 +5,8 @@ import (
 	"github.com/LacirJR/psygrow-api/src/internal/core/port"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"strings"
+	"time"
 )
 
 type patientRepository struct {
@@ -28,16 +30,6 @@ func (r *patientRepository) FindByID(id uuid.UUID, userID uuid.UUID) (*model.Pat
 	return &patient, nil
 }
 
-func (r *patientRepository) FindAll(userID uuid.UUID, limit, offset int) ([]model.Patient, error) {
-	var patients []model.Patient
-	err := r.db.Preload("CostCenter").Where("user_id = ?", userID).
-		Limit(limit).
-		Offset(offset).
-		Order("full_name ASC").
-		Find(&patients).Error
-	return patients, err
-}
-
 func (r *patientRepository) Update(patient *model.Patient) error {
 	return r.db.Save(patient).Error
 }
@@ -46,75 +38,159 @@ func (r *patientRepository) Delete(id uuid.UUID, userID uuid.UUID) error {
 	return r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Patient{}).Error
 }
 
-func (r *patientRepository) FindByName(userID uuid.UUID, name string, limit, offset int) ([]model.Patient, error) {
-	var patients []model.Patient
-	err := r.db.Preload("CostCenter").Where("user_id = ? AND full_name ILIKE ?", userID, "%"+name+"%").
-		Limit(limit).
-		Offset(offset).
-		Order("full_name ASC").
-		Find(&patients).Error
-	return patients, err
-}
-
-func (r *patientRepository) FindByEmail(userID uuid.UUID, email string) (*model.Patient, error) {
+func (r *patientRepository) FindByContact(userID uuid.UUID, email string, phoneDigits string) (*model.Patient, error) {
 	var patient model.Patient
-	err := r.db.Preload("CostCenter").Where("user_id = ? AND email = ?", userID, email).First(&patient).Error
+	err := r.db.Where("user_id = ?", userID).
+		Where(contactCondition(r.db, email, phoneDigits)).
+		Order("created_at ASC").
+		First(&patient).Error
 	if err != nil {
 		return nil, err
 	}
 	return &patient, nil
 }
 
-func (r *patientRepository) FindByPhone(userID uuid.UUID, phone string) (*model.Patient, error) {
-	var patient model.Patient
-	err := r.db.Preload("CostCenter").Where("user_id = ? AND phone = ?", userID, phone).First(&patient).Error
-	if err != nil {
-		return nil, err
-	}
-	return &patient, nil
+func (r *patientRepository) FindAllByUser(userID uuid.UUID) ([]model.Patient, error) {
+	var patients []model.Patient
+	err := r.db.Preload("CostCenter").Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&patients).Error
+	return patients, err
 }
 
-func (r *patientRepository) FindByDocument(userID uuid.UUID, document string) (*model.Patient, error) {
-	var patient model.Patient
-	err := r.db.Preload("CostCenter").Where("user_id = ? AND document = ?", userID, document).First(&patient).Error
+// patientSearchRow is a patient with the date of its last attended session, which the search sorts by
+type patientSearchRow struct {
+	model.Patient
+	LastSessionAt *time.Time `gorm:"->"`
+}
+
+func (patientSearchRow) TableName() string {
+	return "patients"
+}
+
+var patientSearchSpec = listSpec{
+	sorts: map[string]string{
+		"name":         "full_name",
+		"birth_date":   "birth_date",
+		"created_at":   "created_at",
+		"last_session": "last_session_at",
+	},
+	nullable:    map[string]bool{"last_session": true},
+	defaultSort: "name",
+	dateColumn:  "patients.created_at",
+}
+
+func (r *patientRepository) Search(filter port.PatientSearchFilter, query port.ListQuery) (port.Page[model.Patient], error) {
+	var page port.Page[model.Patient]
+
+	rows, err := listPage[patientSearchRow](r.searchQuery(filter).Select("patients.*, last_sessions.last_session_at"), patientSearchSpec, query)
 	if err != nil {
-		return nil, err
+		return page, err
 	}
-	return &patient, nil
-}
+	page.NextCursor, page.Total = rows.NextCursor, rows.Total
 
-func (r *patientRepository) FindByCostCenter(userID uuid.UUID, costCenterID uuid.UUID, limit, offset int) ([]model.Patient, error) {
+	ids := make([]uuid.UUID, len(rows.Data))
+	for i, row := range rows.Data {
+		ids[i] = row.ID
+	}
 	var patients []model.Patient
-	err := r.db.Preload("CostCenter").Where("user_id = ? AND cost_center_id = ?", userID, costCenterID).
-		Limit(limit).
-		Offset(offset).
-		Order("full_name ASC").
-		Find(&patients).Error
-	return patients, err
+	if err := r.db.Preload("CostCenter").Where("id IN ?", ids).Find(&patients).Error; err != nil {
+		return page, err
+	}
+
+	// Keep the order of the page, which the cost centers were loaded without
+	byID := make(map[uuid.UUID]model.Patient, len(patients))
+	for _, patient := range patients {
+		byID[patient.ID] = patient
+	}
+	page.Data = make([]model.Patient, 0, len(ids))
+	for _, id := range ids {
+		if patient, ok := byID[id]; ok {
+			page.Data = append(page.Data, patient)
+		}
+	}
+	return page, nil
 }
 
-func (r *patientRepository) FindActive(userID uuid.UUID, limit, offset int) ([]model.Patient, error) {
-	var patients []model.Patient
-	err := r.db.Preload("CostCenter").Where("user_id = ? AND active = ?", userID, true).
-		Limit(limit).
-		Offset(offset).
-		Order("full_name ASC").
-		Find(&patients).Error
-	return patients, err
+// searchQuery builds the filtered query of Search, joined with the date of each patient's last attended session
+func (r *patientRepository) searchQuery(filter port.PatientSearchFilter) *gorm.DB {
+	lastSessions := r.db.Model(&model.Session{}).
+		Select("patient_id, MAX(start_time) AS last_session_at").
+		Where("user_id = ? AND was_attended = ?", filter.UserID, true).
+		Group("patient_id")
+
+	query := r.db.Model(&model.Patient{}).
+		Joins("LEFT JOIN (?) AS last_sessions ON last_sessions.patient_id = patients.id", lastSessions).
+		Where("patients.user_id = ?", filter.UserID)
+
+	if filter.Query != "" {
+		pattern := "%" + escapeLike(filter.Query) + "%"
+		query = query.Where(
+			"(immutable_unaccent(lower(patients.full_name)) LIKE immutable_unaccent(lower(?)) OR immutable_unaccent(lower(patients.social_name)) LIKE immutable_unaccent(lower(?)))",
+			pattern, pattern)
+	}
+	if filter.Document != "" {
+		query = query.Where("patients.document = ?", filter.Document)
+	}
+	if filter.Phone != "" {
+		if strings.HasPrefix(filter.Phone, "+") {
+			query = query.Where("(patients.phone = ? OR patients.emergency_contact_phone = ?)", filter.Phone, filter.Phone)
+		} else {
+			query = query.Where("regexp_replace(patients.phone, '[^0-9]', '', 'g') LIKE ?", "%"+filter.Phone)
+		}
+	}
+	if filter.IsActive != nil {
+		query = query.Where("patients.active = ?", *filter.IsActive)
+	}
+	if filter.CostCenterID != nil {
+		query = query.Where("patients.cost_center_id = ?", *filter.CostCenterID)
+	}
+	if filter.BornBefore != nil {
+		query = query.Where("patients.birth_date <= ?", *filter.BornBefore)
+	}
+	if filter.BornAfter != nil {
+		query = query.Where("patients.birth_date > ?", *filter.BornAfter)
+	}
+	if filter.LastSessionBefore != nil {
+		query = query.Where("last_sessions.last_session_at < ?", *filter.LastSessionBefore)
+	}
+	if filter.LastSessionAfter != nil {
+		query = query.Where("last_sessions.last_session_at >= ?", *filter.LastSessionAfter)
+	}
+	if filter.HasOpenBalance != nil {
+		openBalance := "EXISTS (SELECT 1 FROM appointments WHERE appointments.patient_id = patients.id AND appointments.status = ? " +
+			"AND NOT EXISTS (SELECT 1 FROM payment_appointments WHERE payment_appointments.appointment_id = appointments.id))"
+		if *filter.HasOpenBalance {
+			query = query.Where(openBalance, model.AppointmentStatusDone)
+		} else {
+			query = query.Where("NOT "+openBalance, model.AppointmentStatusDone)
+		}
+	}
+
+	return query
 }
 
-func (r *patientRepository) FindInactive(userID uuid.UUID, limit, offset int) ([]model.Patient, error) {
-	var patients []model.Patient
-	err := r.db.Preload("CostCenter").Where("user_id = ? AND active = ?", userID, false).
-		Limit(limit).
-		Offset(offset).
-		Order("full_name ASC").
-		Find(&patients).Error
-	return patients, err
+// escapeLike escapes the LIKE wildcards of a user-provided search term
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
 }
 
-func (r *patientRepository) Count(userID uuid.UUID) (int64, error) {
-	var count int64
-	err := r.db.Model(&model.Patient{}).Where("user_id = ?", userID).Count(&count).Error
-	return count, err
-}
\ No newline at end of file
+// patientListSpec declares the sorts and filters accepted when listing patients
+var patientListSpec = listSpec{
+	sorts: map[string]string{
+		"full_name":  "full_name",
+		"birth_date": "birth_date",
+		"created_at": "created_at",
+	},
+	defaultSort: "full_name",
+	dateColumn:  "created_at",
+	filters: map[string]listFilter{
+		"active":         boolFilter("active"),
+		"cost_center_id": uuidFilter("cost_center_id"),
+	},
+	preloads: []string{"CostCenter"},
+}
+
+func (r *patientRepository) List(userID uuid.UUID, query port.ListQuery) (port.Page[model.Patient], error) {
+	return listPage[model.Patient](r.db.Where("user_id = ?", userID), patientListSpec, query)
+}
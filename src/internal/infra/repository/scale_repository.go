@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type scaleRepository struct {
+	db *gorm.DB
+}
+
+func NewScaleRepository(db *gorm.DB) port.ScaleRepository {
+	return &scaleRepository{db: db}
+}
+
+func (r *scaleRepository) Create(scale *model.Scale) error {
+	return r.db.Create(scale).Error
+}
+
+func (r *scaleRepository) FindAll() ([]model.Scale, error) {
+	var scales []model.Scale
+	err := r.db.Order("name").Find(&scales).Error
+	return scales, err
+}
+
+func (r *scaleRepository) FindByID(id uuid.UUID) (*model.Scale, error) {
+	var scale model.Scale
+	err := r.db.Where("id = ?", id).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("item_number")
+		}).
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("value")
+		}).
+		Preload("Bands", func(db *gorm.DB) *gorm.DB {
+			return db.Order("min_score")
+		}).
+		First(&scale).Error
+	if err != nil {
+		return nil, err
+	}
+	return &scale, nil
+}
+
+func (r *scaleRepository) FindByCode(code string) (*model.Scale, error) {
+	var scale model.Scale
+	err := r.db.Where("code = ?", code).First(&scale).Error
+	if err != nil {
+		return nil, err
+	}
+	return &scale, nil
+}
+
+type scaleApplicationRepository struct {
+	db *gorm.DB
+}
+
+func NewScaleApplicationRepository(db *gorm.DB) port.ScaleApplicationRepository {
+	return &scaleApplicationRepository{db: db}
+}
+
+func (r *scaleApplicationRepository) Create(application *model.ScaleApplication) error {
+	// Answers are created along with the application, in the same transaction
+	return r.db.Omit("Scale").Create(application).Error
+}
+
+func (r *scaleApplicationRepository) FindByID(id uuid.UUID, patientID uuid.UUID) (*model.ScaleApplication, error) {
+	var application model.ScaleApplication
+	err := r.db.Where("id = ? AND patient_id = ?", id, patientID).
+		Preload("Scale").
+		Preload("Answers").
+		First(&application).Error
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+func (r *scaleApplicationRepository) FindByPatient(patientID uuid.UUID, scaleID *uuid.UUID) ([]model.ScaleApplication, error) {
+	var applications []model.ScaleApplication
+	query := r.db.Where("patient_id = ?", patientID)
+	if scaleID != nil {
+		query = query.Where("scale_id = ?", *scaleID)
+	}
+	err := query.Preload("Scale").Order("applied_at DESC").Find(&applications).Error
+	return applications, err
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"gorm.io/gorm"
+	"time"
+)
+
+type signingKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepository(db *gorm.DB) port.SigningKeyRepository {
+	return &signingKeyRepository{db: db}
+}
+
+func (r *signingKeyRepository) Save(key *model.SigningKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *signingKeyRepository) FindUsable(at time.Time) ([]model.SigningKey, error) {
+	var keys []model.SigningKey
+	err := r.db.Where("expires_at IS NULL OR expires_at > ?", at).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+func (r *signingKeyRepository) Retire(id string, retiredAt time.Time, expiresAt time.Time) error {
+	return r.db.Model(&model.SigningKey{}).
+		Where("id = ? AND retired_at IS NULL", id).
+		Updates(map[string]interface{}{"retired_at": retiredAt, "expires_at": expiresAt}).Error
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type treatmentPlanRepository struct {
+	db *gorm.DB
+}
+
+func NewTreatmentPlanRepository(db *gorm.DB) port.TreatmentPlanRepository {
+	return &treatmentPlanRepository{db: db}
+}
+
+func (r *treatmentPlanRepository) Create(plan *model.TreatmentPlan, version *model.TreatmentPlanVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkNoActivePlan(tx, plan); err != nil {
+			return err
+		}
+		if err := tx.Create(plan).Error; err != nil {
+			return err
+		}
+		// Goals are created along with the version
+		version.PlanID = plan.ID
+		return tx.Create(version).Error
+	})
+}
+
+func (r *treatmentPlanRepository) FindByID(id uuid.UUID, patientID uuid.UUID) (*model.TreatmentPlan, error) {
+	var plan model.TreatmentPlan
+	err := r.db.Where("id = ? AND patient_id = ?", id, patientID).First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *treatmentPlanRepository) FindByPatient(patientID uuid.UUID) ([]model.TreatmentPlan, error) {
+	var plans []model.TreatmentPlan
+	err := r.db.Where("patient_id = ?", patientID).Order("start_date DESC, created_at DESC").Find(&plans).Error
+	return plans, err
+}
+
+func (r *treatmentPlanRepository) FindActiveByPatient(patientID uuid.UUID) (*model.TreatmentPlan, error) {
+	var plan model.TreatmentPlan
+	err := r.db.Where("patient_id = ? AND status = ?", patientID, model.TreatmentPlanStatusActive).First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *treatmentPlanRepository) FindVersion(planID uuid.UUID, version int) (*model.TreatmentPlanVersion, error) {
+	var planVersion model.TreatmentPlanVersion
+	err := r.db.Where("plan_id = ? AND version = ?", planID, version).
+		Preload("Goals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position")
+		}).
+		First(&planVersion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &planVersion, nil
+}
+
+func (r *treatmentPlanRepository) FindVersions(planID uuid.UUID) ([]model.TreatmentPlanVersion, error) {
+	var versions []model.TreatmentPlanVersion
+	err := r.db.Where("plan_id = ?", planID).
+		Preload("Goals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position")
+		}).
+		Order("version DESC").
+		Find(&versions).Error
+	return versions, err
+}
+
+func (r *treatmentPlanRepository) Revise(plan *model.TreatmentPlan, version *model.TreatmentPlanVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the plan so concurrent revisions cannot create the same version twice
+		var current model.TreatmentPlan
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", plan.ID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if current.CurrentVersion != plan.CurrentVersion {
+			return port.ErrTreatmentPlanOutdated
+		}
+
+		version.PlanID = plan.ID
+		version.Version = current.CurrentVersion + 1
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(plan).Update("current_version", version.Version).Error; err != nil {
+			return err
+		}
+		plan.CurrentVersion = version.Version
+		return nil
+	})
+}
+
+func (r *treatmentPlanRepository) UpdateStatus(plan *model.TreatmentPlan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if plan.IsActive() {
+			if err := checkNoActivePlan(tx, plan); err != nil {
+				return err
+			}
+		}
+		return tx.Model(plan).Updates(map[string]interface{}{
+			"status":   plan.Status,
+			"ended_at": plan.EndedAt,
+		}).Error
+	})
+}
+
+// checkNoActivePlan locks the patient of a plan and fails with ErrTreatmentPlanActive when they
+// have another active plan
+func checkNoActivePlan(tx *gorm.DB, plan *model.TreatmentPlan) error {
+	var patient model.Patient
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", plan.PatientID).
+		First(&patient).Error; err != nil {
+		return err
+	}
+
+	var active int64
+	if err := tx.Model(&model.TreatmentPlan{}).
+		Where("patient_id = ? AND status = ? AND id <> ?", plan.PatientID, model.TreatmentPlanStatusActive, plan.ID).
+		Count(&active).Error; err != nil {
+		return err
+	}
+	if active > 0 {
+		return port.ErrTreatmentPlanActive
+	}
+	return nil
+}
+
+func (r *treatmentPlanRepository) FindProgressByPlan(planID uuid.UUID) ([]model.TreatmentGoalProgress, error) {
+	var progress []model.TreatmentGoalProgress
+	err := r.db.Where("plan_id = ?", planID).Order("rated_at, created_at").Find(&progress).Error
+	return progress, err
+}
+
+func (r *treatmentPlanRepository) FindProgressByEvolution(evolutionID uuid.UUID) ([]model.TreatmentGoalProgress, error) {
+	var progress []model.TreatmentGoalProgress
+	err := r.db.Where("evolution_id = ?", evolutionID).Find(&progress).Error
+	return progress, err
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// RecoveryCodeRepository implementation
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) port.RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+func (r *recoveryCodeRepository) ReplaceForUser(userID uuid.UUID, codes []*model.UserRecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(codes).Error
+	})
+}
+
+func (r *recoveryCodeRepository) Consume(userID uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.Model(&model.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *recoveryCodeRepository) CountUnused(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.UserRecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *recoveryCodeRepository) DeleteForUser(userID uuid.UUID) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.UserRecoveryCode{}).Error
+}
+
+// TwoFactorPolicyRepository implementation
+type twoFactorPolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorPolicyRepository(db *gorm.DB) port.TwoFactorPolicyRepository {
+	return &twoFactorPolicyRepository{db: db}
+}
+
+func (r *twoFactorPolicyRepository) Save(policy *model.TwoFactorPolicy) error {
+	return r.db.Create(policy).Error
+}
+
+func (r *twoFactorPolicyRepository) FindAll() ([]model.TwoFactorPolicy, error) {
+	var policies []model.TwoFactorPolicy
+	err := r.db.Order("created_at ASC").Find(&policies).Error
+	return policies, err
+}
+
+func (r *twoFactorPolicyRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&model.TwoFactorPolicy{}).Error
+}
+
+func (r *twoFactorPolicyRepository) IsRequiredFor(user *model.User) (bool, error) {
+	var policies []model.TwoFactorPolicy
+	err := r.db.Where("(organization_id IS NULL OR organization_id = ?) AND (role IS NULL OR role = ?)", user.OrganizationID, user.Role).
+		Find(&policies).Error
+	if err != nil {
+		return false, err
+	}
+
+	for _, policy := range policies {
+		if policy.AppliesTo(user) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// OrganizationRepository implementation
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) port.OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) Save(organization *model.Organization) error {
+	return r.db.Create(organization).Error
+}
+
+func (r *organizationRepository) FindByID(id uuid.UUID) (*model.Organization, error) {
+	var organization model.Organization
+	err := r.db.Where("id = ?", id).First(&organization).Error
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (r *organizationRepository) FindBySlug(slug string) (*model.Organization, error) {
+	var organization model.Organization
+	err := r.db.Where("slug = ?", slug).First(&organization).Error
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (r *organizationRepository) FindAll() ([]model.Organization, error) {
+	var organizations []model.Organization
+	err := r.db.Order("name ASC").Find(&organizations).Error
+	return organizations, err
+}
+
+func (r *organizationRepository) Update(organization *model.Organization) error {
+	return r.db.Save(organization).Error
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"github.com/LacirJR/psygrow-api/src/internal/core/model"
 	"github.com/LacirJR/psygrow-api/src/internal/core/port"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -15,7 +16,12 @@ func NewUserRepository(db *gorm.DB) port.UserRepository {
 }
 
 func (r *userRepository) Save(user *model.User) error {
-	return r.db.Create(user).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return createDefaultLeadPipeline(tx, user.ID)
+	})
 }
 
 func (r *userRepository) FindByEmail(email string) (*model.User, error) {
@@ -26,3 +32,101 @@ func (r *userRepository) FindByEmail(email string) (*model.User, error) {
 	}
 	return &user, nil
 }
+
+func (r *userRepository) FindByID(id uuid.UUID) (*model.User, error) {
+	var user model.User
+	err := r.db.Where("id = ?", id).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindAll(filter port.UserFilter) ([]model.User, error) {
+	var users []model.User
+	err := r.filtered(filter).Order("name ASC").Find(&users).Error
+	return users, err
+}
+
+var userListSpec = listSpec{
+	sorts: map[string]string{
+		"name":          "name",
+		"email":         "email",
+		"created_at":    "created_at",
+		"last_login_at": "last_login_at",
+	},
+	nullable:    map[string]bool{"last_login_at": true},
+	defaultSort: "name",
+	dateColumn:  "created_at",
+}
+
+func (r *userRepository) List(filter port.UserFilter, query port.ListQuery) (port.Page[model.User], error) {
+	return listPage[model.User](r.filtered(filter), userListSpec, query)
+}
+
+func (r *userRepository) Update(user *model.User) error {
+	return r.db.Save(user).Error
+}
+
+func (r *userRepository) ConsumeTwoFactorStep(userID uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND two_factor_last_step < ?", userID, step).
+		Update("two_factor_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *userRepository) filtered(filter port.UserFilter) *gorm.DB {
+	query := r.db.Model(&model.User{})
+	if filter.Role != nil {
+		query = query.Where("role = ?", *filter.Role)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+	if filter.OrganizationID != nil {
+		query = query.Where("organization_id = ?", *filter.OrganizationID)
+	}
+	if filter.Search != "" {
+		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+filter.Search+"%", "%"+filter.Search+"%")
+	}
+	return query
+}
+
+// UserInviteRepository implementation
+type userInviteRepository struct {
+	db *gorm.DB
+}
+
+func NewUserInviteRepository(db *gorm.DB) port.UserInviteRepository {
+	return &userInviteRepository{db: db}
+}
+
+func (r *userInviteRepository) SaveWithUser(user *model.User, invite *model.UserInvite) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if err := createDefaultLeadPipeline(tx, user.ID); err != nil {
+			return err
+		}
+		return tx.Omit("User").Create(invite).Error
+	})
+}
+
+func (r *userInviteRepository) FindPendingByTokenHash(tokenHash string) (*model.UserInvite, error) {
+	var invite model.UserInvite
+	err := r.db.Preload("User").Where("token_hash = ? AND accepted_at IS NULL", tokenHash).First(&invite).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *userInviteRepository) Accept(invite *model.UserInvite, user *model.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.UserInvite{}).Where("id = ?", invite.ID).Update("accepted_at", invite.AcceptedAt).Error
+	})
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	"github.com/LacirJR/psygrow-api/src/internal/core/security"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// apiKeyLastUsedInterval limits how often the last-used timestamp of a key is written
+const apiKeyLastUsedInterval = time.Minute
+
+// extractAPIKey returns the API key sent in the X-API-Key header or as "Authorization: ApiKey {key}"
+func extractAPIKey(c *gin.Context, authHeader string) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if strings.HasPrefix(authHeader, "ApiKey ") {
+		return strings.TrimPrefix(authHeader, "ApiKey ")
+	}
+	return ""
+}
+
+// authenticateAPIKey validates an API key and sets the key owner and permissions in the context
+func authenticateAPIKey(c *gin.Context, key string) {
+	prefix, ok := security.APIKeyPrefix(key)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		c.Abort()
+		return
+	}
+
+	apiKeyRepo := repository.NewAPIKeyRepository(config.DB)
+	apiKey, err := apiKeyRepo.FindByPrefix(prefix)
+	if err != nil || !security.VerifyAPIKey(key, apiKey.KeyHash) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		c.Abort()
+		return
+	}
+
+	now := time.Now()
+	if !apiKey.IsUsable(now) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key revoked or expired"})
+		c.Abort()
+		return
+	}
+
+	// The key acts on behalf of its owner, who must still be active
+	owner, err := repository.NewUserRepository(config.DB).FindByID(apiKey.UserID)
+	if err != nil || !owner.IsActive {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key owner is inactive"})
+		c.Abort()
+		return
+	}
+
+	if err := apiKeyRepo.TouchLastUsed(apiKey.ID, now, apiKeyLastUsedInterval); err != nil {
+		log.Printf("Erro ao registrar uso da chave de API %s: %v", apiKey.ID, err)
+	}
+
+	c.Set("user_id", apiKey.UserID.String())
+	c.Set("user_role", owner.Role)
+	c.Set("token_type", security.TokenTypeAPIKey)
+	c.Set("api_key_id", apiKey.ID.String())
+	// Permissions follow the owner's current role, which may have changed since the key was created
+	c.Set("api_key_permissions", apiKey.PermissionListForRole(owner.Role))
+	c.Next()
+}
+
+// PermissionMiddleware requires API keys to carry all the given permissions.
+// Requests authenticated with a user token are not affected.
+func PermissionMiddleware(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tokenType, _ := c.Get("token_type"); tokenType != security.TokenTypeAPIKey {
+			c.Next()
+			return
+		}
+
+		value, _ := c.Get("api_key_permissions")
+		granted, _ := value.([]string)
+		for _, permission := range permissions {
+			if !hasPermission(granted, permission) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "API key lacks permission", "permission": permission})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func hasPermission(granted []string, permission string) bool {
+	for _, p := range granted {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
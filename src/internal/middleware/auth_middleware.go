@@ -1,30 +1,39 @@
 package middleware
 
 import (
-	"errors"
 	"github.com/LacirJR/psygrow-api/src/internal/config"
+	"github.com/LacirJR/psygrow-api/src/internal/core/security"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"net/http"
 	"strings"
 )
 
-// jwtSecretKey will be initialized when needed
-var jwtSecretKey []byte
-
-// getJWTSecretKey returns the JWT secret key, initializing it if necessary
-func getJWTSecretKey() []byte {
-	if len(jwtSecretKey) == 0 {
-		jwtSecretKey = []byte(config.GetEnvironment(config.JwtSecretKey))
+// AuthMiddleware verifies the JWT token and sets the user ID and role in the context.
+// Only access tokens are accepted unless other token types are explicitly allowed.
+// When security.TokenTypeAPIKey is allowed, an API key sent in the X-API-Key header
+// or as "Authorization: ApiKey {key}" is accepted instead of a Bearer token.
+func AuthMiddleware(allowedTokenTypes ...string) gin.HandlerFunc {
+	if len(allowedTokenTypes) == 0 {
+		allowedTokenTypes = []string{security.TokenTypeAccess}
 	}
-	return jwtSecretKey
-}
 
-// AuthMiddleware verifies the JWT token and sets the user ID in the context
-func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get the Authorization header
 		authHeader := c.GetHeader("Authorization")
+
+		// API keys are only accepted on routes that explicitly allow them
+		if apiKey := extractAPIKey(c, authHeader); apiKey != "" {
+			if !isAllowedTokenType(security.TokenTypeAPIKey, allowedTokenTypes) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "API keys are not accepted for this resource"})
+				c.Abort()
+				return
+			}
+			authenticateAPIKey(c, apiKey)
+			return
+		}
+
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
 			c.Abort()
@@ -41,33 +50,55 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Extract the token
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		// Parse and validate the token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Validate the signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("unexpected signing method")
-			}
-			return getJWTSecretKey(), nil
-		})
-
+		// Parse and validate the token against the signing keyring
+		claims, err := security.Tokens().Parse(tokenString)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token", "details": err.Error()})
 			c.Abort()
 			return
 		}
 
-		// Check if the token is valid
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			// Set user ID and role in the context
-			c.Set("user_id", claims["sub"])
-			c.Set("user_role", claims["role"])
-			c.Next()
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+		if !isAllowedTokenType(claims.Type, allowedTokenTypes) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token type not allowed for this resource"})
+			c.Abort()
+			return
+		}
+
+		// The user is loaded on every request so that deactivation, role changes and
+		// password changes take effect before the token expires
+		userID, err := uuid.Parse(claims.Subject)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.Abort()
+			return
+		}
+		user, err := repository.NewUserRepository(config.DB).FindByID(userID)
+		if err != nil || !user.IsActive {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found or inactive"})
+			c.Abort()
+			return
+		}
+		if !user.AcceptsTokenIssuedAt(claims.IssuedAt) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
 			c.Abort()
 			return
 		}
+
+		// Set user ID, current role and token type in the context
+		c.Set("user_id", user.ID.String())
+		c.Set("user_role", user.Role)
+		c.Set("token_type", claims.Type)
+		c.Next()
+	}
+}
+
+func isAllowedTokenType(tokenType string, allowed []string) bool {
+	for _, t := range allowed {
+		if t == tokenType {
+			return true
+		}
 	}
+	return false
 }
 
 // RoleMiddleware checks if the user has the required role
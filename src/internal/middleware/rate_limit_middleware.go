@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateWindow counts the requests of one client within a fixed window
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimitMiddleware allows at most limit requests per window for each client IP and route.
+// Counters are kept in memory, so each API instance enforces its own limit.
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	windows := make(map[string]*rateWindow)
+	lastPrune := time.Now()
+
+	return func(c *gin.Context) {
+		key := c.ClientIP() + " " + c.Request.URL.Path
+		now := time.Now()
+
+		mu.Lock()
+		if now.Sub(lastPrune) > window {
+			for k, w := range windows {
+				if now.Sub(w.start) >= window {
+					delete(windows, k)
+				}
+			}
+			lastPrune = now
+		}
+
+		w, ok := windows[key]
+		if !ok || now.Sub(w.start) >= window {
+			w = &rateWindow{start: now}
+			windows[key] = w
+		}
+		w.count++
+		exceeded := w.count > limit
+		retryAfter := w.start.Add(window).Sub(now)
+		mu.Unlock()
+
+		if exceeded {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Muitas requisições, tente novamente mais tarde", "retry_after_seconds": seconds})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -2,9 +2,20 @@ package router
 
 import (
 	"github.com/LacirJR/psygrow-api/src/internal/config"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/core/security"
 	"github.com/LacirJR/psygrow-api/src/internal/handler"
 	"github.com/LacirJR/psygrow-api/src/internal/middleware"
 	"github.com/gin-gonic/gin"
+	"time"
+)
+
+const (
+	publicLeadRateLimit  = 5
+	publicLeadRateWindow = time.Minute * 10
+
+	publicAnamneseRateLimit  = 30
+	publicAnamneseRateWindow = time.Minute * 10
 )
 
 func RegisterRoutes(r *gin.Engine) {
@@ -24,6 +35,9 @@ func RegisterRoutes(r *gin.Engine) {
 		c.Next()
 	})
 
+	// Public keys used to verify the tokens issued by the API
+	r.GET("/.well-known/jwks.json", handler.GetJWKS)
+
 	api := r.Group("/api")
 	{
 		v1 := api.Group("/v1")
@@ -31,12 +45,36 @@ func RegisterRoutes(r *gin.Engine) {
 			auth := v1.Group("/auth")
 			{
 				auth.POST("/login", handler.Login)
+				auth.POST("/login/2fa", handler.LoginTwoFactor)
+				auth.POST("/invites/accept", handler.AcceptInvite)
+
+				// Two-factor enrollment also accepts the enrollment token issued when a policy requires 2FA
+				twoFactor := auth.Group("/2fa")
+				twoFactor.Use(middleware.AuthMiddleware(security.TokenTypeAccess, security.TokenTypeTwoFactorEnrollment))
+				{
+					twoFactor.GET("", handler.GetTwoFactorStatus)
+					twoFactor.POST("/setup", handler.SetupTwoFactor)
+					twoFactor.POST("/confirm", handler.ConfirmTwoFactor)
+				}
+
+				twoFactorManagement := auth.Group("/2fa")
+				twoFactorManagement.Use(middleware.AuthMiddleware())
+				{
+					twoFactorManagement.POST("/disable", handler.DisableTwoFactor)
+					twoFactorManagement.POST("/recovery-codes", handler.RegenerateRecoveryCodes)
+				}
+
+				loginAttempts := auth.Group("/login-attempts")
+				loginAttempts.Use(middleware.AuthMiddleware())
+				{
+					loginAttempts.GET("", handler.GetLoginAttempts)
+				}
 
 				protectedAuth := auth.Group("")
 				{
 					protectedAuth.Use(middleware.AuthMiddleware())
 					{
-						verify := auth.Group("/verify")
+						verify := protectedAuth.Group("/verify")
 						{
 							verify.GET("", handler.VerifyAuth)
 						}
@@ -45,15 +83,91 @@ func RegisterRoutes(r *gin.Engine) {
 
 			}
 
+			// Public contact forms of the organizations' websites
+			public := v1.Group("/public")
+			{
+				public.POST("/:org_slug/leads", middleware.RateLimitMiddleware(publicLeadRateLimit, publicLeadRateWindow), handler.CreatePublicLead)
+
+				// Anamneses filled by the patient through a self-service link
+				publicAnamnese := public.Group("/anamnese/:token")
+				publicAnamnese.Use(middleware.RateLimitMiddleware(publicAnamneseRateLimit, publicAnamneseRateWindow))
+				{
+					publicAnamnese.GET("", handler.GetPublicAnamneseForm)
+					publicAnamnese.POST("", handler.SubmitPublicAnamnese)
+				}
+			}
+
+			// Routes also reachable by integrations with an API key holding the required permission
+			integrations := v1.Group("")
+			integrations.Use(middleware.AuthMiddleware(security.TokenTypeAccess, security.TokenTypeAPIKey))
+			{
+				integrations.POST("/leads", middleware.PermissionMiddleware(model.APIKeyPermissionLeadsCreate), handler.CreateLead)
+				integrations.GET("/leads", middleware.PermissionMiddleware(model.APIKeyPermissionLeadsRead), handler.GetLeads)
+				integrations.GET("/leads/:id", middleware.PermissionMiddleware(model.APIKeyPermissionLeadsRead), handler.GetLead)
+
+				financialRead := integrations.Group("/financial")
+				financialRead.Use(
+					middleware.PermissionMiddleware(model.APIKeyPermissionFinancialRead),
+					middleware.RoleMiddleware(model.FinancialReadRoles...),
+				)
+				{
+					financialRead.GET("/cost-centers", handler.GetCostCenters)
+					financialRead.GET("/cost-centers/:id", handler.GetCostCenter)
+					financialRead.GET("/payments", handler.GetPayments)
+					financialRead.GET("/payments/:id", handler.GetPayment)
+					financialRead.GET("/repasses", handler.GetRepasses)
+				}
+			}
+
 			// Protected routes
 			protected := v1.Group("")
 			protected.Use(middleware.AuthMiddleware())
 			{
 
-				users := v1.Group("/users")
+				// Authenticated user's own profile
+				me := protected.Group("/users/me")
 				{
-					users.POST("", handler.RegisterUser)
-					users.GET("/:email", handler.GetUserByEmail)
+					me.GET("", handler.GetMyProfile)
+					me.PUT("", handler.UpdateMyProfile)
+					me.PUT("/password", handler.ChangeMyPassword)
+				}
+
+				// API keys of the authenticated user
+				apiKeys := protected.Group("/api-keys")
+				{
+					apiKeys.POST("", handler.CreateAPIKey)
+					apiKeys.GET("", handler.GetAPIKeys)
+					apiKeys.DELETE("/:id", handler.RevokeAPIKey)
+				}
+
+				// Admin routes
+				admin := protected.Group("/admin")
+				admin.Use(middleware.RoleMiddleware(model.UserRoleAdmin))
+				{
+					users := admin.Group("/users")
+					{
+						users.POST("", handler.RegisterUser)
+						users.POST("/invite", handler.InviteUser)
+						users.GET("", handler.GetUsers)
+						users.GET("/:id", handler.GetUser)
+						users.GET("/email/:email", handler.GetUserByEmail)
+						users.PUT("/:id/status", handler.UpdateUserStatus)
+						users.PUT("/:id/role", handler.UpdateUserRole)
+					}
+
+					organizations := admin.Group("/organizations")
+					{
+						organizations.POST("", handler.CreateOrganization)
+						organizations.GET("", handler.GetOrganizations)
+						organizations.PUT("/:id", handler.UpdateOrganization)
+					}
+
+					twoFactorPolicies := admin.Group("/two-factor-policies")
+					{
+						twoFactorPolicies.POST("", handler.CreateTwoFactorPolicy)
+						twoFactorPolicies.GET("", handler.GetTwoFactorPolicies)
+						twoFactorPolicies.DELETE("/:id", handler.DeleteTwoFactorPolicy)
+					}
 				}
 
 				// Anamnese routes
@@ -66,12 +180,19 @@ func RegisterRoutes(r *gin.Engine) {
 						templates.GET("/:template_id", handler.GetAnamneseTemplate)
 						templates.PUT("/:template_id", handler.UpdateAnamneseTemplate)
 						templates.DELETE("/:template_id", handler.DeleteAnamneseTemplate)
+						templates.POST("/:template_id/publish", handler.PublishAnamneseTemplate)
+						templates.GET("/:template_id/versions", handler.GetAnamneseTemplateVersions)
+						templates.GET("/:template_id/versions/:version_id", handler.GetAnamneseTemplateVersion)
+						templates.GET("/:template_id/export", handler.ExportAnamneseTemplate)
+						templates.POST("/:template_id/clone", handler.CloneAnamneseTemplate)
+						templates.POST("/import", handler.ImportAnamneseTemplate)
 
 						// Anamnese field routes
 						fields := templates.Group("/:template_id/fields")
 						{
 							fields.POST("", handler.CreateAnamneseField)
 							fields.GET("", handler.GetAnamneseFields)
+							fields.PUT("/order", handler.ReorderAnamneseFields)
 							fields.PUT("/:field_id", handler.UpdateAnamneseField)
 							fields.DELETE("/:field_id", handler.DeleteAnamneseField)
 
@@ -81,16 +202,33 @@ func RegisterRoutes(r *gin.Engine) {
 								options.POST("", handler.CreateAnamneseFieldOption)
 								options.POST("/bulk", handler.CreateAnamneseFieldOptionsBulk)
 								options.GET("", handler.GetAnamneseFieldOptions)
+								options.PUT("", handler.ReplaceAnamneseFieldOptions)
+								options.PUT("/order", handler.ReorderAnamneseFieldOptions)
+								options.GET("/:option_id", handler.GetAnamneseFieldOption)
+								options.PUT("/:option_id", handler.UpdateAnamneseFieldOption)
+								options.DELETE("/:option_id", handler.DeleteAnamneseFieldOption)
 							}
 						}
 					}
 
+					// Starter templates shipped with the API
+					anamnese.GET("/library", handler.GetAnamneseLibrary)
+
 					// Patient anamnese routes
 					patientAnamnese := anamnese.Group("/patients")
 					{
 						patientAnamnese.POST("", handler.CreatePatientAnamnese)
 						patientAnamnese.GET("/:patient_id", handler.GetPatientAnamneses)
-						patientAnamnese.GET("/:patient_id/details", handler.GetPatientAnamneseDetails)
+						patientAnamnese.GET("/:patient_id/details/:id", handler.GetPatientAnamneseDetails)
+						patientAnamnese.PATCH("/:patient_id/details/:id", handler.UpdatePatientAnamneseAnswers)
+						patientAnamnese.POST("/:patient_id/details/:id/finalize", handler.FinalizePatientAnamnese)
+						patientAnamnese.GET("/:patient_id/details/:id/history", handler.GetPatientAnamneseHistory)
+
+						// Self-service links sent to the patient
+						patientAnamnese.POST("/:patient_id/links", handler.CreatePatientAnamneseLink)
+						patientAnamnese.GET("/:patient_id/links", handler.GetPatientAnamneseLinks)
+						patientAnamnese.POST("/:patient_id/links/:link_id/revoke", handler.RevokePatientAnamneseLink)
+						patientAnamnese.GET("/:patient_id/links/:link_id/accesses", handler.GetPatientAnamneseLinkAccesses)
 					}
 				}
 
@@ -102,7 +240,10 @@ func RegisterRoutes(r *gin.Engine) {
 					patients.GET("/:patient_id", handler.GetPatient)
 					patients.PUT("/:patient_id", handler.UpdatePatient)
 					patients.DELETE("/:patient_id", handler.DeletePatient)
-					patients.GET("/search", handler.SearchPatientsByName)
+					patients.GET("/search", handler.SearchPatients)
+					patients.GET("/duplicates", handler.GetPatientDuplicates)
+					patients.GET("/merges", handler.GetPatientMerges)
+					patients.POST("/:patient_id/merge", handler.MergePatients)
 					patients.GET("/cost-center/:cost_center_id", handler.GetPatientsByCostCenter)
 
 					// Patient family routes
@@ -115,6 +256,60 @@ func RegisterRoutes(r *gin.Engine) {
 						families.DELETE("/:id", handler.DeletePatientFamily)
 						families.GET("/relationship/:relationship", handler.GetPatientFamiliesByRelationship)
 					}
+
+					// Genogram routes
+					patients.GET("/:patient_id/genogram", handler.GetPatientGenogram)
+					familyLinks := patients.Group("/:patient_id/family-links")
+					{
+						familyLinks.POST("", handler.CreatePatientFamilyLink)
+						familyLinks.GET("", handler.GetPatientFamilyLinks)
+						familyLinks.DELETE("/:id", handler.DeletePatientFamilyLink)
+					}
+
+					// Patient consent routes
+					consents := patients.Group("/:patient_id/consents")
+					{
+						consents.POST("", handler.CreatePatientConsent)
+						consents.GET("", handler.GetPatientConsents)
+						consents.POST("/:id/revoke", handler.RevokePatientConsent)
+					}
+
+					// Psychometric scale application routes
+					scaleApplications := patients.Group("/:patient_id/scales")
+					{
+						scaleApplications.POST("", handler.CreateScaleApplication)
+						scaleApplications.GET("", handler.GetScaleApplications)
+						scaleApplications.GET("/timeline", handler.GetScaleTimeline)
+						scaleApplications.GET("/:id", handler.GetScaleApplication)
+					}
+
+					// Treatment plan routes
+					treatmentPlans := patients.Group("/:patient_id/treatment-plans")
+					{
+						treatmentPlans.POST("", handler.CreateTreatmentPlan)
+						treatmentPlans.GET("", handler.GetTreatmentPlans)
+						treatmentPlans.GET("/:id", handler.GetTreatmentPlan)
+						treatmentPlans.GET("/:id/versions", handler.GetTreatmentPlanVersions)
+						treatmentPlans.POST("/:id/revisions", handler.ReviseTreatmentPlan)
+						treatmentPlans.PUT("/:id/status", handler.UpdateTreatmentPlanStatus)
+						treatmentPlans.GET("/:id/summary", handler.GetTreatmentPlanSummary)
+					}
+
+					// Episode of care routes
+					careEpisodes := patients.Group("/:patient_id/episodes")
+					{
+						careEpisodes.POST("", handler.CreateCareEpisode)
+						careEpisodes.GET("", handler.GetCareEpisodes)
+						careEpisodes.GET("/:id", handler.GetCareEpisode)
+						careEpisodes.POST("/:id/close", handler.CloseCareEpisode)
+					}
+				}
+
+				// Psychometric scale routes
+				scales := protected.Group("/scales")
+				{
+					scales.GET("", handler.GetScales)
+					scales.GET("/:scale_id", handler.GetScale)
 				}
 
 				// Appointment routes
@@ -147,12 +342,38 @@ func RegisterRoutes(r *gin.Engine) {
 				// Lead routes
 				leads := protected.Group("/leads")
 				{
-					leads.POST("", handler.CreateLead)
-					leads.GET("", handler.GetLeads)
-					leads.GET("/:id", handler.GetLead)
 					leads.PUT("/:id", handler.UpdateLead)
 					leads.DELETE("/:id", handler.DeleteLead)
 					leads.POST("/:id/convert", handler.ConvertLeadToPatient)
+
+					// Pipeline
+					leads.PUT("/:id/stage", handler.MoveLeadStage)
+					leads.PUT("/:id/follow-up", handler.UpdateLeadFollowUp)
+					leads.POST("/:id/activities", handler.CreateLeadActivity)
+					leads.GET("/:id/activities", handler.GetLeadActivities)
+					leads.GET("/follow-ups/due", handler.GetDueLeadFollowUps)
+
+					stages := leads.Group("/stages")
+					{
+						stages.POST("", handler.CreateLeadStage)
+						stages.GET("", handler.GetLeadStages)
+						stages.PUT("/:id", handler.UpdateLeadStage)
+						stages.DELETE("/:id", handler.DeleteLeadStage)
+					}
+
+					lostReasons := leads.Group("/lost-reasons")
+					{
+						lostReasons.POST("", handler.CreateLeadLostReason)
+						lostReasons.GET("", handler.GetLeadLostReasons)
+						lostReasons.PUT("/:id", handler.UpdateLeadLostReason)
+						lostReasons.DELETE("/:id", handler.DeleteLeadLostReason)
+					}
+				}
+
+				// Report routes
+				reports := protected.Group("/reports")
+				{
+					reports.GET("/leads/funnel", handler.GetLeadFunnelReport)
 				}
 
 				// Financial routes
@@ -162,8 +383,6 @@ func RegisterRoutes(r *gin.Engine) {
 					costCenters := financial.Group("/cost-centers")
 					{
 						costCenters.POST("", handler.CreateCostCenter)
-						costCenters.GET("", handler.GetCostCenters)
-						costCenters.GET("/:id", handler.GetCostCenter)
 						costCenters.PUT("/:id", handler.UpdateCostCenter)
 						costCenters.DELETE("/:id", handler.DeleteCostCenter)
 					}
@@ -172,15 +391,12 @@ func RegisterRoutes(r *gin.Engine) {
 					payments := financial.Group("/payments")
 					{
 						payments.POST("", handler.CreatePayment)
-						payments.GET("", handler.GetPayments)
-						payments.GET("/:id", handler.GetPayment)
 					}
 
 					// Repasse routes
 					repasses := financial.Group("/repasses")
 					{
 						repasses.POST("", handler.CreateRepasse)
-						repasses.GET("", handler.GetRepasses)
 						repasses.PUT("/:id/status", handler.UpdateRepasseStatus)
 					}
 				}
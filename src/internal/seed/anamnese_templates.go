@@ -0,0 +1,83 @@
+package seed
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	dto "github.com/LacirJR/psygrow-api/src/internal/core/dto/anamnese"
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"path"
+	"strings"
+)
+
+// Starter templates in the export format; the file name is the key of the template
+//
+//go:embed anamnese_templates/*.json
+var starterAnamneseTemplates embed.FS
+
+// SeedStarterAnamneseTemplates creates the starter anamnese templates that are still missing.
+// They belong to no user and are cloned by users into their own accounts.
+func SeedStarterAnamneseTemplates() {
+	templates, err := loadStarterAnamneseTemplates()
+	if err != nil {
+		log.Printf("Erro ao carregar modelos de anamnese iniciais: %v", err)
+		return
+	}
+
+	templateRepo := repository.NewAnamneseTemplateRepository(config.DB)
+	for key, export := range templates {
+		_, err := templateRepo.FindByBuiltInKey(key)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Erro ao verificar modelo de anamnese inicial '%s': %v", key, err)
+			continue
+		}
+
+		template, version, fields, err := export.ToModels(uuid.Nil)
+		if err != nil {
+			log.Printf("Modelo de anamnese inicial '%s' inválido: %v", key, err)
+			continue
+		}
+		if errs := helper.NormalizeAnamneseRules(fields); len(errs) > 0 {
+			log.Printf("Modelo de anamnese inicial '%s' com regras inválidas: %v", key, errs)
+			continue
+		}
+		builtInKey := key
+		template.BuiltInKey = &builtInKey
+
+		if err := templateRepo.CreateWithVersion(template, version, fields); err != nil {
+			log.Printf("Erro ao criar modelo de anamnese inicial '%s': %v", key, err)
+			continue
+		}
+		log.Printf("Modelo de anamnese inicial '%s' criado com sucesso!", template.Title)
+	}
+}
+
+// loadStarterAnamneseTemplates reads the embedded starter templates, keyed by file name
+func loadStarterAnamneseTemplates() (map[string]dto.AnamneseTemplateExport, error) {
+	entries, err := starterAnamneseTemplates.ReadDir("anamnese_templates")
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]dto.AnamneseTemplateExport, len(entries))
+	for _, entry := range entries {
+		content, err := starterAnamneseTemplates.ReadFile(path.Join("anamnese_templates", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var export dto.AnamneseTemplateExport
+		if err := json.Unmarshal(content, &export); err != nil {
+			return nil, err
+		}
+		templates[strings.TrimSuffix(entry.Name(), ".json")] = export
+	}
+	return templates, nil
+}
@@ -0,0 +1,33 @@
+package seed
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStarterAnamneseTemplates(t *testing.T) {
+	templates, err := loadStarterAnamneseTemplates()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"adult_intake", "child_intake", "couples"}, keysOf(templates))
+
+	for key, export := range templates {
+		template, version, fields, err := export.ToModels(uuid.Nil)
+		if !assert.NoError(t, err, key) {
+			continue
+		}
+		assert.Equal(t, uuid.Nil, template.UserID, key)
+		assert.Equal(t, template.ID, version.TemplateID, key)
+		assert.Len(t, fields, len(export.Fields), key)
+		assert.Empty(t, helper.NormalizeAnamneseRules(fields), key)
+	}
+}
+
+func keysOf[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
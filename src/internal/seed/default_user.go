@@ -1,9 +1,11 @@
 package seed
 
 import (
+	"errors"
 	"github.com/LacirJR/psygrow-api/src/internal/config"
 	"github.com/LacirJR/psygrow-api/src/internal/core/model"
 	"github.com/LacirJR/psygrow-api/src/internal/core/security"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"log"
@@ -27,16 +29,16 @@ func CreateDefaultAdminUser() {
 	}
 
 	// Verifica se o usuário já existe no banco de dados
-	var existingUser model.User
-	result := config.DB.Where("email = ?", defaultEmail).First(&existingUser)
+	userRepo := repository.NewUserRepository(config.DB)
+	_, err := userRepo.FindByEmail(defaultEmail)
 
-	if result.Error == nil {
+	if err == nil {
 		log.Printf("Usuário padrão com email '%s' já existe. Pulando criação.", defaultEmail)
 		return
 	}
 
-	if result.Error != gorm.ErrRecordNotFound {
-		log.Printf("Erro ao verificar existência do usuário padrão: %v", result.Error)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Erro ao verificar existência do usuário padrão: %v", err)
 		return
 	}
 
@@ -59,7 +61,7 @@ func CreateDefaultAdminUser() {
 		UpdatedAt:    time.Now(),
 	}
 
-	if err := config.DB.Create(&user).Error; err != nil {
+	if err := userRepo.Save(&user); err != nil {
 		log.Fatalf("Erro fatal ao criar usuário padrão: %v", err)
 		return
 	}
@@ -0,0 +1,116 @@
+package seed
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"github.com/LacirJR/psygrow-api/src/internal/config"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/LacirJR/psygrow-api/src/internal/infra/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"path"
+)
+
+//go:embed scales/*.json
+var scaleFiles embed.FS
+
+// scaleDefinition is the format of the embedded scale files. Items are numbered in the order
+// they are listed.
+type scaleDefinition struct {
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Instructions string `json:"instructions"`
+	Scoring      string `json:"scoring"`
+	Options      []struct {
+		Value int    `json:"value"`
+		Label string `json:"label"`
+	} `json:"options"`
+	Items []struct {
+		Text          string `json:"text"`
+		ReverseScored bool   `json:"reverse_scored"`
+		Critical      bool   `json:"critical"`
+	} `json:"items"`
+	Bands []struct {
+		MinScore float64 `json:"min_score"`
+		MaxScore float64 `json:"max_score"`
+		Severity string  `json:"severity"`
+	} `json:"bands"`
+}
+
+// SeedScales creates the psychometric scales that are still missing. Existing scales are kept
+// as they are, since applications refer to their items.
+func SeedScales() {
+	scales, err := loadScales()
+	if err != nil {
+		log.Printf("Erro ao carregar escalas psicométricas: %v", err)
+		return
+	}
+
+	scaleRepo := repository.NewScaleRepository(config.DB)
+	for _, scale := range scales {
+		_, err := scaleRepo.FindByCode(scale.Code)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Erro ao verificar escala '%s': %v", scale.Code, err)
+			continue
+		}
+
+		if err := scaleRepo.Create(&scale); err != nil {
+			log.Printf("Erro ao criar escala '%s': %v", scale.Code, err)
+			continue
+		}
+		log.Printf("Escala '%s' criada com sucesso!", scale.Name)
+	}
+}
+
+// loadScales reads the embedded scale files into new scales
+func loadScales() ([]model.Scale, error) {
+	entries, err := scaleFiles.ReadDir("scales")
+	if err != nil {
+		return nil, err
+	}
+
+	scales := make([]model.Scale, 0, len(entries))
+	for _, entry := range entries {
+		content, err := scaleFiles.ReadFile(path.Join("scales", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var definition scaleDefinition
+		if err := json.Unmarshal(content, &definition); err != nil {
+			return nil, err
+		}
+
+		scale := model.Scale{
+			ID:           uuid.New(),
+			Code:         definition.Code,
+			Name:         definition.Name,
+			Description:  definition.Description,
+			Instructions: definition.Instructions,
+			Scoring:      definition.Scoring,
+		}
+		for _, option := range definition.Options {
+			scale.Options = append(scale.Options, model.ScaleOption{ID: uuid.New(), ScaleID: scale.ID, Value: option.Value, Label: option.Label})
+		}
+		for i, item := range definition.Items {
+			scale.Items = append(scale.Items, model.ScaleItem{
+				ID:            uuid.New(),
+				ScaleID:       scale.ID,
+				ItemNumber:    i + 1,
+				Text:          item.Text,
+				ReverseScored: item.ReverseScored,
+				Critical:      item.Critical,
+			})
+		}
+		for _, band := range definition.Bands {
+			scale.Bands = append(scale.Bands, model.ScaleBand{ID: uuid.New(), ScaleID: scale.ID, MinScore: band.MinScore, MaxScore: band.MaxScore, Severity: band.Severity})
+		}
+		scales = append(scales, scale)
+	}
+	return scales, nil
+}
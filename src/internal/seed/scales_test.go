@@ -0,0 +1,27 @@
+package seed
+
+import (
+	"github.com/LacirJR/psygrow-api/src/internal/core/helper"
+	"github.com/LacirJR/psygrow-api/src/internal/core/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScales(t *testing.T) {
+	scales, err := loadScales()
+	assert.NoError(t, err)
+
+	codes := make([]string, 0, len(scales))
+	for _, scale := range scales {
+		codes = append(codes, scale.Code)
+		assert.Contains(t, []string{model.ScaleScoringSum, model.ScaleScoringMean}, scale.Scoring, scale.Code)
+		assert.NotEmpty(t, scale.Items, scale.Code)
+
+		// Every reachable total score has a severity
+		lowest, highest := helper.ScaleScoreRange(&scale)
+		for score := lowest; score <= highest; score++ {
+			assert.NotNil(t, helper.ScaleSeverity(&scale, score), "%s: %v", scale.Code, score)
+		}
+	}
+	assert.ElementsMatch(t, []string{"gad7", "phq9", "rses"}, codes)
+}